@@ -1,24 +1,39 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"github.com/Verce11o/yata/internal/config"
 	"github.com/Verce11o/yata/internal/http"
 	"github.com/Verce11o/yata/internal/http/auth"
+	"github.com/Verce11o/yata/internal/http/bookmarks"
 	"github.com/Verce11o/yata/internal/http/comments"
+	"github.com/Verce11o/yata/internal/http/conversations"
+	"github.com/Verce11o/yata/internal/http/drafts"
+	"github.com/Verce11o/yata/internal/http/health"
+	"github.com/Verce11o/yata/internal/http/media"
 	"github.com/Verce11o/yata/internal/http/middleware"
 	"github.com/Verce11o/yata/internal/http/notifications"
 	"github.com/Verce11o/yata/internal/http/tweets"
+	"github.com/Verce11o/yata/internal/http/users"
+	"github.com/Verce11o/yata/internal/http/webhooks"
+	"github.com/Verce11o/yata/internal/http/websocket"
 	"github.com/Verce11o/yata/internal/lib/logger"
 	trace "github.com/Verce11o/yata/internal/lib/metrics/tracer"
 	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/rabbitmq"
+	"github.com/Verce11o/yata/internal/realtime"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/Verce11o/yata/internal/scheduler"
 	"github.com/Verce11o/yata/internal/service"
+	"github.com/Verce11o/yata/internal/storage"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	fiberLogger "github.com/gofiber/fiber/v2/middleware/logger"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func Run(cfg *config.Config) {
@@ -31,19 +46,37 @@ func Run(cfg *config.Config) {
 	// Init metrics
 	tracer := trace.InitTracer("http")
 
+	// Init storage
+	blobStore := storage.NewBlobStore(cfg.Media)
+	repos := repository.NewRepositories(cfg)
+
+	// Live connections registry, consumer and services publish to it
+	hub := realtime.NewHub(cfg.Notifications.ReplayBuffer, cfg.Notifications.ReplayWindow)
+
 	// Init service
-	services := service.NewServices(cfg.Services, log, tracer)
+	services := service.NewServices(cfg, log, tracer, blobStore, repos, hub)
+
+	// Init scheduler
+	tweetScheduler := scheduler.NewScheduler(log, tracer.Tracer, cfg.Scheduler, repos, services)
 
 	// Init middleware
 	middlewareHandler := middleware.NewMiddlewareHandler(log, tracer.Tracer, services, cfg, validator)
 
 	// Init handlers
-	authHandler := auth.NewHandler(log, tracer.Tracer, services.Auth, validator)
+	authHandler := auth.NewHandler(log, tracer.Tracer, services.Auth, services.OIDC, services.TwoFactor, services.Verification, validator)
 	tweetHandler := tweets.NewHandler(log, tracer.Tracer, services, validator)
 	commentHandler := comments.NewHandler(log, tracer.Tracer, services, validator)
 	notificationHandler := notifications.NewHandler(log, tracer.Tracer, services, validator)
+	mediaHandler := media.NewHandler(log, tracer.Tracer, services, validator, cfg.Media)
+	bookmarkHandler := bookmarks.NewHandler(log, tracer.Tracer, services, validator)
+	draftHandler := drafts.NewHandler(log, tracer.Tracer, services, validator)
+	userHandler := users.NewHandler(log, tracer.Tracer, services, validator)
+	conversationHandler := conversations.NewHandler(log, tracer.Tracer, services, validator)
+	webhookHandler := webhooks.NewHandler(log, tracer.Tracer, services, validator)
+	wsHandler := websocket.NewHandler(log, tracer.Tracer, services, hub, cfg.Notifications, cfg.Presence)
+	healthHandler := health.NewHandler(log, tracer.Tracer, tweetScheduler)
 
-	handlers := http.NewHandlers(authHandler, tweetHandler, commentHandler, notificationHandler, middlewareHandler)
+	handlers := http.NewHandlers(authHandler, tweetHandler, commentHandler, notificationHandler, mediaHandler, bookmarkHandler, draftHandler, userHandler, conversationHandler, webhookHandler, wsHandler, healthHandler, middlewareHandler)
 
 	handlers.InitRoutes(app)
 
@@ -57,12 +90,96 @@ func Run(cfg *config.Config) {
 
 	log.Infof("Server is running on port: %v", cfg.HTTPServer.Port)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Collect expired partial uploads
+	go func() {
+		ticker := time.NewTicker(cfg.Media.GCInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				collected, err := services.Media.CollectExpiredUploads(ctx)
+				if err != nil {
+					log.Errorf("error while collecting expired uploads: %v", err)
+					continue
+				}
+				log.Debugf("collected %d expired uploads", collected)
+			}
+		}
+	}()
+
+	// Send due webhook deliveries
+	go func() {
+		ticker := time.NewTicker(cfg.Webhooks.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				delivered, err := services.Webhooks.DeliverDue(ctx)
+				if err != nil {
+					log.Errorf("error while delivering webhooks: %v", err)
+					continue
+				}
+				log.Debugf("delivered %d webhooks", delivered)
+			}
+		}
+	}()
+
+	// Announce closed polls, it does not depend on the scheduler being enabled
+	go func() {
+		ticker := time.NewTicker(cfg.Polls.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				closed, err := services.Polls.ClosePolls(ctx)
+				if err != nil {
+					log.Errorf("error while closing polls: %v", err)
+					continue
+				}
+				log.Debugf("closed %d polls", closed)
+			}
+		}
+	}()
+
+	// Push notifications to connected clients
+	amqpConn := rabbitmq.NewAmqpConnection(cfg.RabbitMQ)
+	defer amqpConn.Close()
+
+	notificationConsumer := rabbitmq.NewNotificationConsumer(amqpConn, log, tracer.Tracer, services)
+
+	go func() {
+		err := notificationConsumer.StartConsumer(cfg.RabbitMQ.QueueName, cfg.RabbitMQ.ConsumerTag, cfg.RabbitMQ.ExchangeName, cfg.RabbitMQ.BindingKey, hub)
+		if err != nil {
+			log.Errorf("notification consumer stopped: %v", err)
+		}
+	}()
+
+	// Publish scheduled tweets
+	if cfg.Scheduler.Enabled {
+		go tweetScheduler.Run(ctx)
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
 
 	log.Info("Server exiting..")
 
+	cancel()
+	hub.Close()
+
 	if err := app.Shutdown(); err != nil {
 		log.Fatal("Server Shutdown error: ", err)
 	}
@@ -3,21 +3,35 @@ package config
 import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
+	"time"
 )
 
 type Config struct {
-	Postgres   PostgresConfig `yaml:"postgres" env-required:"true"`
-	HTTPServer HTTPServer     `yaml:"http_server" env-required:"true"`
-	RabbitMQ   RabbitMQ       `yaml:"rabbitmq" env-required:"true"`
-	Services   Services       `yaml:"services" env-required:"true"`
-	App        App            `yaml:"app" env-required:"true"`
-	Metrics    Metrics        `yaml:"metrics" env-required:"true"`
-	Mode       string         `yaml:"mode"`
+	Postgres        PostgresConfig  `yaml:"postgres" env-required:"true"`
+	HTTPServer      HTTPServer      `yaml:"http_server" env-required:"true"`
+	RabbitMQ        RabbitMQ        `yaml:"rabbitmq" env-required:"true"`
+	Services        Services        `yaml:"services" env-required:"true"`
+	App             App             `yaml:"app" env-required:"true"`
+	Metrics         Metrics         `yaml:"metrics" env-required:"true"`
+	Media           Media           `yaml:"media"`
+	Scheduler       Scheduler       `yaml:"scheduler"`
+	Polls           Polls           `yaml:"polls"`
+	Recommendations Recommendations `yaml:"recommendations"`
+	Notifications   Notifications   `yaml:"notifications"`
+	Presence        Presence        `yaml:"presence"`
+	Webhooks        Webhooks        `yaml:"webhooks"`
+	OIDC            OIDC            `yaml:"oidc"`
+	TwoFactor       TwoFactor       `yaml:"two_factor"`
+	Verification    Verification    `yaml:"verification"`
+	Mode            string          `yaml:"mode"`
 }
 
 type App struct {
-	JWT  JWTConfig `yaml:"jwt"`
-	Port string    `yaml:"port"`
+	JWT     JWTConfig `yaml:"jwt"`
+	Port    string    `yaml:"port"`
+	Storage string    `yaml:"storage" env-default:"memory"`
+	// EditWindow is how long after creation tweet can be edited, zero means forever
+	EditWindow time.Duration `yaml:"edit_window" env-default:"1h"`
 }
 
 type JWTConfig struct {
@@ -55,6 +69,135 @@ type Metrics struct {
 	} `yaml:"jaeger"`
 }
 
+type Media struct {
+	Storage            string        `yaml:"storage" env-default:"local"`
+	LocalPath          string        `yaml:"local_path" env-default:"./data/media"`
+	S3                 S3Config      `yaml:"s3"`
+	MaxSize            int64         `yaml:"max_size" env-default:"10485760"`
+	MaxChunkSize       int64         `yaml:"max_chunk_size" env-default:"4194304"`
+	MaxVideoSize       int64         `yaml:"max_video_size" env-default:"52428800"`
+	MaxDuration        time.Duration `yaml:"max_duration" env-default:"140s"`
+	MaxPixels          int64         `yaml:"max_pixels" env-default:"16777216"`
+	MaxFrames          int           `yaml:"max_frames" env-default:"300"`
+	MaxAnimationPixels int64         `yaml:"max_animation_pixels" env-default:"268435456"`
+	UploadTTL          time.Duration `yaml:"upload_ttl" env-default:"24h"`
+	GCInterval         time.Duration `yaml:"gc_interval" env-default:"1h"`
+	BaseURL            string        `yaml:"base_url" env-default:"/api/media"`
+	CacheMaxAge        time.Duration `yaml:"cache_max_age" env-default:"8760h"`
+	SignSecret         string        `yaml:"sign_secret" env:"MEDIA_SIGN_SECRET"`
+	SignedURLTTL       time.Duration `yaml:"signed_url_ttl" env-default:"1h"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" env:"S3_ENDPOINT"`
+	AccessKey string `yaml:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"S3_SECRET_KEY"`
+	Bucket    string `yaml:"bucket" env:"S3_BUCKET"`
+	UseSSL    bool   `yaml:"use_ssl" env:"S3_USE_SSL"`
+}
+
+type Scheduler struct {
+	Enabled         bool          `yaml:"enabled" env-default:"true"`
+	Interval        time.Duration `yaml:"interval" env-default:"5s"`
+	BatchSize       int           `yaml:"batch_size" env-default:"50"`
+	LeaseTTL        time.Duration `yaml:"lease_ttl" env-default:"30s"`
+	ClaimTTL        time.Duration `yaml:"claim_ttl" env-default:"1m"`
+	MaxAttempts     int           `yaml:"max_attempts" env-default:"5"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env-default:"10s"`
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff" env-default:"5m"`
+}
+
+type Polls struct {
+	// Interval is how often closed polls are looked for, closing is announced even if scheduler is disabled
+	Interval  time.Duration `yaml:"interval" env-default:"5s"`
+	BatchSize int           `yaml:"batch_size" env-default:"100"`
+	// ClaimTTL is time given to notify about claimed poll before another replica picks it up
+	ClaimTTL time.Duration `yaml:"claim_ttl" env-default:"1m"`
+}
+
+type Recommendations struct {
+	// ActivityWindow is how far back posts count as recent activity
+	ActivityWindow time.Duration `yaml:"activity_window" env-default:"72h"`
+	// ActivityPages limits how many pages of the global feed are scanned for activity
+	ActivityPages int `yaml:"activity_pages" env-default:"5"`
+	// MaxFollowing limits how many followees are walked for friends of friends
+	MaxFollowing int `yaml:"max_following" env-default:"500"`
+}
+
+type Notifications struct {
+	// GroupWindow is how far apart notifications about the same object can be to be shown as one item
+	GroupWindow time.Duration `yaml:"group_window" env-default:"24h"`
+	// GroupActors is how many actors of a group are listed with usernames
+	GroupActors int `yaml:"group_actors" env-default:"3"`
+	// StreamHeartbeat is how often event streams send a comment to keep idle proxies from closing them
+	StreamHeartbeat time.Duration `yaml:"stream_heartbeat" env-default:"15s"`
+	// ReplayBuffer is how many latest events per user are kept to replay to reconnecting clients
+	ReplayBuffer int `yaml:"replay_buffer" env-default:"100"`
+	// ReplayWindow is how long events are kept for users without connections
+	ReplayWindow time.Duration `yaml:"replay_window" env-default:"10m"`
+	// UnreadCountTTL bounds how stale cached unread count gets if a change is not seen by this replica
+	UnreadCountTTL time.Duration `yaml:"unread_count_ttl" env-default:"1m"`
+}
+
+type Presence struct {
+	// TTL is how long connection stays online after the last presence heartbeat,
+	// connections that send nothing for this long are closed
+	TTL time.Duration `yaml:"ttl" env-default:"60s"`
+}
+
+type Webhooks struct {
+	Interval        time.Duration `yaml:"interval" env-default:"5s"`
+	BatchSize       int           `yaml:"batch_size" env-default:"50"`
+	Timeout         time.Duration `yaml:"timeout" env-default:"10s"`
+	ClaimTTL        time.Duration `yaml:"claim_ttl" env-default:"1m"`
+	MaxAttempts     int           `yaml:"max_attempts" env-default:"6"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env-default:"30s"`
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff" env-default:"1h"`
+	// DisableAfter is how many deliveries in a row may fail before webhook is disabled
+	DisableAfter int `yaml:"disable_after" env-default:"10"`
+	// AllowPrivateNetworks lets webhooks reach loopback and private addresses, for local development only
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" env-default:"false"`
+}
+
+type OIDC struct {
+	// StateTTL is how long user has to finish sign in at provider
+	StateTTL time.Duration `yaml:"state_ttl" env-default:"10m"`
+	Timeout  time.Duration `yaml:"timeout" env-default:"10s"`
+	// Providers are keyed by name used in /api/auth/oidc/:provider routes
+	Providers map[string]OIDCProvider `yaml:"providers"`
+}
+
+type OIDCProvider struct {
+	Issuer       string `yaml:"issuer"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	// RedirectURL must point to /api/auth/oidc/:provider/callback and be registered at provider
+	RedirectURL string `yaml:"redirect_url"`
+	// Scopes default to openid, email and profile
+	Scopes []string `yaml:"scopes"`
+}
+
+type TwoFactor struct {
+	// Issuer is shown in authenticator apps next to account name
+	Issuer string `yaml:"issuer" env-default:"yata"`
+	// ChallengeTTL is how long user has to enter code after password was accepted
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" env-default:"5m"`
+	// MaxAttempts limits codes tried per login challenge
+	MaxAttempts int `yaml:"max_attempts" env-default:"5"`
+	// Skew is how many 30 second steps codes may be off to cover clock drift
+	Skew          int `yaml:"skew" env-default:"1"`
+	RecoveryCodes int `yaml:"recovery_codes" env-default:"10"`
+}
+
+type Verification struct {
+	// CacheTTL is how long verified status of user is reused
+	CacheTTL time.Duration `yaml:"cache_ttl" env-default:"10m"`
+	// UnverifiedCacheTTL is shorter, so activation made from another instance is picked up soon
+	UnverifiedCacheTTL time.Duration `yaml:"unverified_cache_ttl" env-default:"30s"`
+	// Allowlist holds path patterns unverified users may still call, "*" matches one path segment
+	Allowlist []string `yaml:"allowlist" env-default:"/api/user/verify,/api/user/forgot-password,/api/user/reset-password,/api/user/2fa/*,/api/user/api-keys,/api/user/api-keys/*,/api/user/oidc/*/link,/api/user/settings,/api/notifications/*,/api/notifications/groups/*/read"`
+}
+
 type Services struct {
 	Auth struct {
 		Addr string `yaml:"addr"`
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+type AccountSettings struct {
+	UserID    string `json:"-" db:"user_id"`
+	IsPrivate bool   `json:"is_private" db:"is_private"`
+	// AllowMessages lets everyone send direct messages, otherwise only followers can
+	AllowMessages bool      `json:"allow_messages_from_everyone" db:"allow_messages"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateAccountSettingsInput changes only settings that are sent, omitted ones are kept as is
+type UpdateAccountSettingsInput struct {
+	IsPrivate     *bool `json:"is_private"`
+	AllowMessages *bool `json:"allow_messages_from_everyone"`
+}
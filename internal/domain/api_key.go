@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Scopes are "<resource>:read" for GET requests and "<resource>:write" for the rest
+const (
+	ScopeTweets        = "tweets"
+	ScopeUsers         = "users"
+	ScopeNotifications = "notifications"
+	ScopeMessages      = "messages"
+	ScopeWebhooks      = "webhooks"
+)
+
+const MaxAPIKeys = 20
+
+type APIKey struct {
+	KeyID  string   `json:"key_id"`
+	UserID string   `json:"-"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	// Key is the secret itself, only its hash is stored, so it is shown once on creation
+	Key        string     `json:"key,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateAPIKeyInput struct {
+	Name   string   `json:"name" validate:"required,max=64"`
+	Scopes []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=tweets:read tweets:write users:read users:write notifications:read notifications:write messages:read messages:write webhooks:read webhooks:write"`
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "time"
+
+type Bookmark struct {
+	UserID    string    `json:"user_id" db:"user_id"`
+	TweetID   string    `json:"tweet_id" db:"tweet_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
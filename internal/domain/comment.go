@@ -20,6 +20,7 @@ type CommentResponse struct {
 	UserID    string    `json:"user_id,omitempty"`
 	TweetID   string    `json:"tweet_id"`
 	Text      string    `json:"text"`
+	Media     *Media    `json:"media,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// MaxConversationMembers limits group conversations, creator included
+const MaxConversationMembers = 10
+
+type Conversation struct {
+	ConversationID string               `json:"conversation_id"`
+	CreatorID      string               `json:"creator_id"`
+	IsGroup        bool                 `json:"is_group"`
+	Members        []ConversationMember `json:"members"`
+	CreatedAt      time.Time            `json:"created_at"`
+	LastMessageAt  time.Time            `json:"last_message_at"`
+}
+
+// ConversationMember carries member's read receipt, the last message they have read
+type ConversationMember struct {
+	UserID            string     `json:"user_id"`
+	LastReadMessageID string     `json:"last_read_message_id,omitempty"`
+	LastReadAt        *time.Time `json:"last_read_at,omitempty"`
+}
+
+type Message struct {
+	MessageID      string    `json:"message_id" db:"message_id"`
+	ConversationID string    `json:"conversation_id" db:"conversation_id"`
+	SenderID       string    `json:"sender_id" db:"sender_id"`
+	Text           string    `json:"text" db:"text"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReadReceipt is pushed to conversation members when one of them reads messages
+type ReadReceipt struct {
+	ConversationID string    `json:"conversation_id"`
+	UserID         string    `json:"user_id"`
+	MessageID      string    `json:"message_id"`
+	ReadAt         time.Time `json:"read_at"`
+}
+
+// CreateConversationInput lists members besides the creator, one member makes a direct conversation
+type CreateConversationInput struct {
+	UserIDs []string `json:"user_ids" validate:"required,min=1,max=9,unique,dive,uuid"`
+}
+
+type SendMessageInput struct {
+	Text string `json:"text" validate:"required,max=1000"`
+}
+
+type ReadConversationInput struct {
+	MessageID string `json:"message_id" validate:"required,uuid"`
+}
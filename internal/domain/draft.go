@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// tweets carry a single attachment for now, so drafts keep at most one media id
+type CreateDraftInput struct {
+	Text     string   `json:"text"`
+	MediaIDs []string `json:"media_ids" validate:"max=1,dive,uuid"`
+}
+
+type UpdateDraftInput struct {
+	Text     string   `json:"text"`
+	MediaIDs []string `json:"media_ids" validate:"max=1,dive,uuid"`
+	Version  int64    `json:"version" validate:"required,min=1"`
+}
+
+type Draft struct {
+	DraftID   string    `json:"draft_id"`
+	UserID    string    `json:"user_id"`
+	Text      string    `json:"text"`
+	MediaIDs  []string  `json:"media_ids"`
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// PublishedTweetID is set once tweet is created but draft is not deleted yet
+	PublishedTweetID string `json:"-"`
+}
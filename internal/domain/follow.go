@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+const FollowRequestNotification = "follow_request"
+
+type FollowStatus string
+
+const (
+	FollowStatusFollowing FollowStatus = "following"
+	// FollowStatusRequested means target account is private and has to approve the request
+	FollowStatusRequested FollowStatus = "requested"
+)
+
+type Follow struct {
+	FollowerID string    `db:"follower_id"`
+	FolloweeID string    `db:"followee_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type FollowRequest struct {
+	RequesterID string    `json:"requester_id" db:"requester_id"`
+	TargetID    string    `json:"-" db:"target_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type FollowUser struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	// FollowsYou tells whether listed user follows the viewer
+	FollowsYou bool      `json:"follows_you"`
+	FollowedAt time.Time `json:"followed_at"`
+}
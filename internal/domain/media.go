@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+type InitMediaUploadInput struct {
+	ContentType string `json:"content_type" validate:"required"`
+	Size        int64  `json:"size" validate:"required,min=1"`
+	Private     bool   `json:"private"`
+}
+
+type MediaUpload struct {
+	UploadID    string    `json:"upload_id"`
+	UserID      string    `json:"user_id"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Offset      int64     `json:"offset"`
+	Private     bool      `json:"private"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Media struct {
+	MediaID      string    `json:"media_id"`
+	URL          string    `json:"url"`
+	ContentType  string    `json:"content_type"`
+	MediaType    MediaType `json:"media_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	DurationMs   int64     `json:"duration_ms,omitempty"`
+	Codec        string    `json:"codec,omitempty"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+}
+
+type AttachmentKind string
+
+const (
+	TweetAttachment   AttachmentKind = "tweets"
+	CommentAttachment AttachmentKind = "comments"
+)
+
+type MediaType string
+
+const (
+	MediaTypeImage MediaType = "image"
+	MediaTypeGIF   MediaType = "gif"
+	MediaTypeVideo MediaType = "video"
+)
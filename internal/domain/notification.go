@@ -11,4 +11,38 @@ type Notification struct {
 	Read           bool      `json:"read"`
 	CreatedAt      time.Time `json:"created_at"`
 	Type           string    `json:"type"`
+	TargetID       string    `json:"target_id,omitempty"`
+}
+
+type NotificationQuery struct {
+	Type       string
+	UnreadOnly bool
+	Cursor     string
+	Limit      int
+}
+
+type UpdateNotificationInput struct {
+	Read *bool `json:"read" validate:"required"`
+}
+
+type MarkNotificationsInput struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+type NotificationActor struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// NotificationGroup joins notifications of the same type about the same target made close in time,
+// GroupID is the id of its newest notification
+type NotificationGroup struct {
+	GroupID         string              `json:"group_id"`
+	Type            string              `json:"type"`
+	TargetID        string              `json:"target_id,omitempty"`
+	ActorCount      int                 `json:"actor_count"`
+	Actors          []NotificationActor `json:"actors"`
+	NotificationIDs []string            `json:"notification_ids"`
+	Read            bool                `json:"read"`
+	LatestAt        time.Time           `json:"latest_at"`
 }
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// OIDCState is kept between redirect to provider and callback, it is used once
+type OIDCState struct {
+	State    string
+	Provider string
+	Nonce    string
+	// CodeVerifier is PKCE secret, only its challenge is sent to provider
+	CodeVerifier string
+	// LinkUserID is set when signed in user links provider to own account
+	LinkUserID string
+	ExpiresAt  time.Time
+}
+
+// Identity links provider account to user
+type Identity struct {
+	Provider  string    `json:"provider" db:"provider"`
+	Subject   string    `json:"-" db:"subject"`
+	UserID    string    `json:"-" db:"user_id"`
+	Email     string    `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// OIDCResult is either session token of signed in user or linked identity
+type OIDCResult struct {
+	Token  string
+	Linked bool
+}
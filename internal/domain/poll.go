@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+const PollClosedNotification = "poll_closed"
+
+type CreatePollInput struct {
+	Options         []string `json:"options" validate:"min=2,max=4,dive,required,max=25"`
+	DurationMinutes int      `json:"duration_minutes" validate:"required,min=5,max=10080"`
+}
+
+type VotePollInput struct {
+	Option *int `json:"option" validate:"required,min=0"`
+}
+
+// PollRecord is poll as it is stored, Votes are counted per option
+type PollRecord struct {
+	TweetID   string
+	AuthorID  string
+	Options   []string
+	Votes     []int
+	ClosesAt  time.Time
+	CreatedAt time.Time
+}
+
+type PollOption struct {
+	Text  string `json:"text"`
+	Votes *int   `json:"votes,omitempty"`
+}
+
+// Poll is poll as it is seen by a viewer, tallies are present only when Closed or VotedOption is set
+type Poll struct {
+	Options     []PollOption `json:"options"`
+	TotalVotes  *int         `json:"total_votes,omitempty"`
+	VotedOption *int         `json:"voted_option,omitempty"`
+	Closed      bool         `json:"closed"`
+	ClosesAt    time.Time    `json:"closes_at"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type NotificationChannel string
+
+const (
+	ChannelInApp NotificationChannel = "in_app"
+	ChannelPush  NotificationChannel = "push"
+	ChannelEmail NotificationChannel = "email"
+)
+
+type ChannelPreferences struct {
+	InApp bool `json:"in_app"`
+	Push  bool `json:"push"`
+	Email bool `json:"email"`
+}
+
+// QuietHours silence push and email between Start and End, given as HH:MM in user's time zone.
+// End before Start means the period goes over midnight.
+type QuietHours struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start" validate:"required_if=Enabled true"`
+	End     string `json:"end" validate:"required_if=Enabled true"`
+}
+
+type NotificationPreferences struct {
+	UserID string `json:"-"`
+	// Types not listed here are delivered to every channel
+	Types      map[string]ChannelPreferences `json:"types"`
+	QuietHours QuietHours                    `json:"quiet_hours"`
+	TimeZone   string                        `json:"time_zone"`
+	UpdatedAt  time.Time                     `json:"updated_at"`
+}
+
+type UpdateNotificationPreferencesInput struct {
+	Types      map[string]ChannelPreferences `json:"types" validate:"max=50,dive,keys,required,max=64,endkeys"`
+	QuietHours QuietHours                    `json:"quiet_hours"`
+	TimeZone   string                        `json:"time_zone" validate:"omitempty,max=64"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceOffline PresenceStatus = "offline"
+)
+
+type Presence struct {
+	UserID     string         `json:"user_id"`
+	Status     PresenceStatus `json:"status"`
+	LastSeenAt *time.Time     `json:"last_seen_at,omitempty"`
+}
+
+// Typing is sent to everyone who has tweet's comment thread open
+type Typing struct {
+	TweetID string `json:"tweet_id"`
+	UserID  string `json:"user_id"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+type UserRecommendation struct {
+	UserID        string  `json:"user_id"`
+	Username      string  `json:"username"`
+	MutualFollows int     `json:"mutual_follows"`
+	RecentPosts   int     `json:"recent_posts"`
+	Followers     int     `json:"followers"`
+	Score         float64 `json:"score"`
+}
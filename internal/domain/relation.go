@@ -0,0 +1,10 @@
+package domain
+
+type RelationType string
+
+const (
+	// RelationBlock hides blocker's content from blocked user and stops their interactions
+	RelationBlock RelationType = "block"
+	// RelationMute only hides muted user's content from the muter
+	RelationMute RelationType = "mute"
+)
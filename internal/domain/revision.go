@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// TweetRevision is a past version of tweet, CreatedAt is when it became current and ReplacedAt when it was edited
+type TweetRevision struct {
+	TweetID    string    `json:"tweet_id"`
+	Revision   int       `json:"revision"`
+	Text       string    `json:"text"`
+	MediaID    string    `json:"-"`
+	Media      *Media    `json:"media,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+type EditStats struct {
+	Count    int
+	EditedAt time.Time
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+type ScheduledTweetStatus string
+
+const (
+	ScheduledTweetPending    ScheduledTweetStatus = "pending"
+	ScheduledTweetPublishing ScheduledTweetStatus = "publishing"
+	ScheduledTweetPublished  ScheduledTweetStatus = "published"
+	ScheduledTweetFailed     ScheduledTweetStatus = "failed"
+)
+
+type CreateScheduledTweetInput struct {
+	Text      string    `json:"text" validate:"required"`
+	MediaID   string    `json:"media_id"`
+	PublishAt time.Time `json:"publish_at" validate:"required"`
+}
+
+type UpdateScheduledTweetInput struct {
+	Text      *string    `json:"text" validate:"omitempty,min=1"`
+	MediaID   *string    `json:"media_id"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+type ScheduledTweet struct {
+	ScheduledID string               `json:"scheduled_id" db:"id"`
+	UserID      string               `json:"user_id" db:"user_id"`
+	Text        string               `json:"text" db:"text"`
+	MediaID     string               `json:"media_id,omitempty" db:"media_id"`
+	PublishAt   time.Time            `json:"publish_at" db:"publish_at"`
+	Status      ScheduledTweetStatus `json:"status" db:"status"`
+	TweetID     string               `json:"tweet_id,omitempty" db:"tweet_id"`
+	Attempts    int                  `json:"attempts" db:"attempts"`
+	LastError   string               `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt   time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+type SchedulerState struct {
+	Running    bool      `json:"running"`
+	Holder     string    `json:"holder"`
+	Leader     bool      `json:"leader"`
+	LastRunAt  time.Time `json:"last_run_at"`
+	LastError  string    `json:"last_error,omitempty"`
+	Published  uint64    `json:"published"`
+	Retried    uint64    `json:"retried"`
+	Failed     uint64    `json:"failed"`
+	Publishing int       `json:"publishing"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// ThreadLink is kept for replies only, tweet without link starts its own conversation
+type ThreadLink struct {
+	TweetID          string
+	InReplyToTweetID string
+	ConversationID   string
+	AuthorID         string
+	CreatedAt        time.Time
+}
+
+type ThreadTweetInput struct {
+	Text    string `json:"text" validate:"required"`
+	MediaID string `json:"media_id" validate:"omitempty,uuid"`
+}
+
+type CreateThreadInput struct {
+	InReplyToTweetID string             `json:"in_reply_to_tweet_id" validate:"omitempty,uuid"`
+	Tweets           []ThreadTweetInput `json:"tweets" validate:"required,min=2,max=25,dive"`
+}
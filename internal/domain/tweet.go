@@ -8,10 +8,17 @@ type CreateTweetInput struct {
 }
 
 type TweetResponse struct {
-	TweetID   string    `json:"tweet_id"`
-	UserID    string    `json:"user_id,omitempty"`
-	Text      string    `json:"text"`
-	CreatedAt time.Time `json:"created_at"`
+	TweetID        string     `json:"tweet_id"`
+	UserID         string     `json:"user_id,omitempty"`
+	Text           string     `json:"text"`
+	Media          *Media     `json:"media,omitempty"`
+	Poll           *Poll      `json:"poll,omitempty"`
+	InReplyTo      string     `json:"in_reply_to_tweet_id,omitempty"`
+	ConversationID string     `json:"conversation_id,omitempty"`
+	BookmarkedByMe bool       `json:"bookmarked_by_me"`
+	EditCount      int        `json:"edit_count"`
+	EditedAt       *time.Time `json:"edited_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
 }
 
 type CreateTweetRequest struct {
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+type TwoFactor struct {
+	UserID string
+	Secret string
+	// Enabled is false until setup is confirmed with a code
+	Enabled bool
+	// LastUsedStep is time step of the last accepted code, codes of it and earlier steps are rejected
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallenge is issued by the first login step, only hash of its token is stored
+type TwoFactorChallenge struct {
+	TokenHash string
+	UserID    string
+	Attempts  int
+	ExpiresAt time.Time
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// TwoFactorLoginInput accepts authenticator code or one of recovery codes
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token" validate:"required,max=128"`
+	Code           string `json:"code" validate:"required,max=32"`
+}
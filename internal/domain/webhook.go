@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookEventFollower = "follower.new"
+	WebhookEventMention  = "mention"
+	WebhookEventComment  = "comment.created"
+	// WebhookEventTest is only sent by test endpoint, webhooks cannot subscribe to it
+	WebhookEventTest = "test"
+)
+
+// MentionNotification is produced by notifications service when user is mentioned in a tweet
+const MentionNotification = "mention"
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+type Webhook struct {
+	WebhookID string   `json:"webhook_id"`
+	UserID    string   `json:"-"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	// Secret signs deliveries, it is only shown when webhook is created
+	Secret  string `json:"secret,omitempty"`
+	Enabled bool   `json:"enabled"`
+	// Failures counts deliveries failed in a row, webhook is disabled when it gets too high
+	Failures  int       `json:"consecutive_failures"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateWebhookInput struct {
+	URL    string   `json:"url" validate:"required,http_url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=follower.new mention comment.created"`
+}
+
+// UpdateWebhookInput keeps omitted fields, enabling webhook again resets its failures
+type UpdateWebhookInput struct {
+	URL     *string  `json:"url" validate:"omitempty,http_url,max=2048"`
+	Events  []string `json:"events" validate:"omitempty,min=1,unique,dive,oneof=follower.new mention comment.created"`
+	Enabled *bool    `json:"enabled"`
+}
+
+type WebhookDelivery struct {
+	DeliveryID string                `json:"delivery_id" db:"id"`
+	WebhookID  string                `json:"webhook_id" db:"webhook_id"`
+	Event      string                `json:"event" db:"event"`
+	Payload    json.RawMessage       `json:"payload" db:"payload"`
+	Status     WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts   int                   `json:"attempts" db:"attempts"`
+	// ResponseStatus is HTTP status of the last attempt, zero if request did not get a response
+	ResponseStatus int       `json:"response_status" db:"response_status"`
+	LastError      string    `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookPayload is the body of every delivery
+type WebhookPayload struct {
+	DeliveryID string    `json:"id"`
+	Event      string    `json:"event"`
+	CreatedAt  time.Time `json:"created_at"`
+	Data       any       `json:"data"`
+}
+
+type FollowerEvent struct {
+	FollowerID string `json:"follower_id"`
+}
+
+type CommentEvent struct {
+	TweetID   string `json:"tweet_id"`
+	CommentID string `json:"comment_id"`
+	UserID    string `json:"user_id"`
+}
+
+type MentionEvent struct {
+	TweetID string `json:"tweet_id"`
+	UserID  string `json:"user_id"`
+}
@@ -14,14 +14,17 @@ import (
 )
 
 type Handler struct {
-	log       *zap.SugaredLogger
-	tracer    trace.Tracer
-	service   service.Auth
-	validator *validator.Validate
+	log          *zap.SugaredLogger
+	tracer       trace.Tracer
+	service      service.Auth
+	oidc         service.OIDC
+	twoFactor    service.TwoFactor
+	verification service.Verification
+	validator    *validator.Validate
 }
 
-func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, service service.Auth, validator *validator.Validate) *Handler {
-	return &Handler{log: log, tracer: tracer, service: service, validator: validator}
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, service service.Auth, oidc service.OIDC, twoFactor service.TwoFactor, verification service.Verification, validator *validator.Validate) *Handler {
+	return &Handler{log: log, tracer: tracer, service: service, oidc: oidc, twoFactor: twoFactor, verification: verification, validator: validator}
 }
 
 func (h *Handler) SignUp(c *fiber.Ctx) error {
@@ -67,6 +70,20 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	challengeToken, err := h.twoFactor.StartLogin(ctx, token)
+
+	if err != nil {
+		h.log.Errorf("Login: %s", err.Error())
+		return response.WithError(c, err)
+	}
+
+	if challengeToken != "" {
+		return c.Status(http.StatusOK).JSON(fiber.Map{
+			"two_factor_required": true,
+			"challenge_token":     challengeToken,
+		})
+	}
+
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"token": token,
 	})
@@ -113,6 +130,9 @@ func (h *Handler) Activate(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	// cached unverified status would keep blocking user until it expires
+	h.verification.Forget(c.Locals("userID").(string))
+
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 	})
@@ -219,3 +239,91 @@ func (h *Handler) ResetPassword(c *fiber.Ctx) error {
 	})
 
 }
+
+// OIDCLogin redirects browser to provider sign in page
+func (h *Handler) OIDCLogin(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.OIDCLogin")
+	defer span.End()
+
+	authURL, err := h.oidc.AuthURL(ctx, c.Params("provider"), "")
+
+	if err != nil {
+		h.log.Errorf("OIDCLogin: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Redirect(authURL, http.StatusFound)
+}
+
+// OIDCCallback responds like Login, or with success when provider was linked
+func (h *Handler) OIDCCallback(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.OIDCCallback")
+	defer span.End()
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		h.log.Debugf("OIDCCallback:HTTP: provider error %s: %s", providerErr, c.Query("error_description"))
+		return response.WithError(c, response.ErrOIDCFailed)
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+
+	if code == "" || state == "" {
+		h.log.Debugf("OIDCCallback:HTTP: empty code or state")
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	result, err := h.oidc.Callback(ctx, c.Params("provider"), code, state)
+
+	if err != nil {
+		h.log.Errorf("OIDCCallback: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	if result.Linked {
+		return c.Status(http.StatusOK).JSON(fiber.Map{
+			"message": "success",
+		})
+	}
+
+	// provider sign in replaces password only, second factor is still required
+	challengeToken, err := h.twoFactor.StartLogin(ctx, result.Token)
+
+	if err != nil {
+		h.log.Errorf("OIDCCallback: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	if challengeToken != "" {
+		return c.Status(http.StatusOK).JSON(fiber.Map{
+			"two_factor_required": true,
+			"challenge_token":     challengeToken,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"token": result.Token,
+	})
+}
+
+// LinkOIDC returns provider sign in URL, callback links provider to current user
+func (h *Handler) LinkOIDC(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.LinkOIDC")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	authURL, err := h.oidc.AuthURL(ctx, c.Params("provider"), userID.(string))
+
+	if err != nil {
+		h.log.Errorf("LinkOIDC: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"authorization_url": authURL,
+	})
+}
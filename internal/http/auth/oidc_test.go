@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/oidc/oidctest"
+	"github.com/Verce11o/yata/internal/lib/token"
+	"github.com/Verce11o/yata/internal/repository/memory"
+	"github.com/Verce11o/yata/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const testJWTSecret = "secret"
+
+// fakeAuth registers every new user, other auth calls are not used by provider sign in
+type fakeAuth struct {
+	service.Auth
+}
+
+func (fakeAuth) Register(ctx context.Context, input domain.SignUpInput) (string, error) {
+	return uuid.NewString(), nil
+}
+
+// noTwoFactor lets everyone in without second factor
+type noTwoFactor struct {
+	service.TwoFactor
+}
+
+func (noTwoFactor) StartLogin(ctx context.Context, sessionToken string) (string, error) {
+	return "", nil
+}
+
+func newOIDCApp(t *testing.T) (*fiber.App, *oidctest.Server) {
+	t.Helper()
+
+	provider, err := oidctest.NewServer("yata", "client-secret", oidctest.User{
+		Subject:       "subject-1",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+	})
+	if err != nil {
+		t.Fatalf("cannot start provider: %v", err)
+	}
+	t.Cleanup(provider.Close)
+
+	providerCfg := provider.Config("http://gateway.test/api/auth/oidc/mock/callback")
+
+	oidcService := service.NewOIDCService(zap.NewNop().Sugar(), noop.NewTracerProvider().Tracer(""), memory.NewOIDCRepository(), fakeAuth{}, config.OIDC{
+		StateTTL: time.Minute,
+		Timeout:  5 * time.Second,
+		Providers: map[string]config.OIDCProvider{
+			"mock": {
+				Issuer:       providerCfg.Issuer,
+				ClientID:     providerCfg.ClientID,
+				ClientSecret: providerCfg.ClientSecret,
+				RedirectURL:  providerCfg.RedirectURL,
+			},
+		},
+	}, config.JWTConfig{Secret: testJWTSecret, TokenTTLHours: 1})
+
+	h := NewHandler(zap.NewNop().Sugar(), noop.NewTracerProvider().Tracer(""), fakeAuth{}, oidcService, noTwoFactor{}, nil, validator.New())
+
+	app := fiber.New()
+	app.Get("/api/auth/oidc/:provider", h.OIDCLogin)
+	app.Get("/api/auth/oidc/:provider/callback", h.OIDCCallback)
+
+	return app, provider
+}
+
+func do(t *testing.T, app *fiber.App, target string) (*http.Response, map[string]any) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil), -1)
+	if err != nil {
+		t.Fatalf("GET %s: %v", target, err)
+	}
+	defer resp.Body.Close()
+
+	var body map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	return resp, body
+}
+
+// authorize starts sign in at the gateway and returns code and state provider redirected back with
+func authorize(t *testing.T, app *fiber.App, provider *oidctest.Server) (string, string) {
+	t.Helper()
+
+	resp, _ := do(t, app, "/api/auth/oidc/mock")
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("OIDCLogin status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	code, state, err := provider.Authorize(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+
+	return code, state
+}
+
+func callbackURL(code, state string) string {
+	return "/api/auth/oidc/mock/callback?" + url.Values{"code": {code}, "state": {state}}.Encode()
+}
+
+func TestOIDCCallbackSignsIn(t *testing.T) {
+	app, provider := newOIDCApp(t)
+
+	code, state := authorize(t, app, provider)
+
+	resp, body := do(t, app, callbackURL(code, state))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("OIDCCallback status = %d, body %v", resp.StatusCode, body)
+	}
+
+	sessionToken, _ := body["token"].(string)
+	if _, err := token.ParseToken(sessionToken, testJWTSecret); err != nil {
+		t.Errorf("OIDCCallback token is invalid: %v", err)
+	}
+}
+
+func TestOIDCCallbackErrors(t *testing.T) {
+	app, provider := newOIDCApp(t)
+
+	code, state := authorize(t, app, provider)
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+	}{
+		{name: "unknown provider", target: "/api/auth/oidc/unknown", status: http.StatusNotFound},
+		{name: "provider error", target: "/api/auth/oidc/mock/callback?error=access_denied", status: http.StatusUnauthorized},
+		{name: "missing code", target: "/api/auth/oidc/mock/callback?state=" + state, status: http.StatusBadRequest},
+		{name: "bad state", target: callbackURL(code, "unknown"), status: http.StatusBadRequest},
+		{name: "bad code", target: callbackURL("unknown", state), status: http.StatusUnauthorized},
+		// state was used by the bad code request above
+		{name: "consumed state", target: callbackURL(code, state), status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := do(t, app, tt.target)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d, body %v", resp.StatusCode, tt.status, body)
+			}
+		})
+	}
+}
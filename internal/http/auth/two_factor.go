@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+// LoginTwoFactor is the second login step, it responds with the same token as Login
+func (h *Handler) LoginTwoFactor(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.LoginTwoFactor")
+	defer span.End()
+
+	var input domain.TwoFactorLoginInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("LoginTwoFactor:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	token, err := h.twoFactor.CompleteLogin(ctx, input)
+
+	if err != nil {
+		h.log.Infof("LoginTwoFactor: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"token": token,
+	})
+}
+
+func (h *Handler) GetTwoFactorStatus(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetTwoFactorStatus")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	twoFactorStatus, err := h.twoFactor.GetStatus(ctx, userID.(string))
+
+	if err != nil {
+		h.log.Errorf("GetTwoFactorStatus: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(twoFactorStatus)
+}
+
+func (h *Handler) SetupTwoFactor(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.SetupTwoFactor")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	setup, err := h.twoFactor.Setup(ctx, userID.(string))
+
+	if err != nil {
+		h.log.Errorf("SetupTwoFactor: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(setup)
+}
+
+func (h *Handler) ConfirmTwoFactor(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.ConfirmTwoFactor")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.TwoFactorCodeInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("ConfirmTwoFactor:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	codes, err := h.twoFactor.Confirm(ctx, userID.(string), input.Code)
+
+	if err != nil {
+		h.log.Errorf("ConfirmTwoFactor: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(codes)
+}
+
+func (h *Handler) DisableTwoFactor(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.DisableTwoFactor")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.TwoFactorCodeInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("DisableTwoFactor:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	if err := h.twoFactor.Disable(ctx, userID.(string), input.Code); err != nil {
+		h.log.Errorf("DisableTwoFactor: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.RegenerateRecoveryCodes")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.TwoFactorCodeInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("RegenerateRecoveryCodes:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	codes, err := h.twoFactor.RegenerateRecoveryCodes(ctx, userID.(string), input.Code)
+
+	if err != nil {
+		h.log.Errorf("RegenerateRecoveryCodes: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(codes)
+}
@@ -0,0 +1,110 @@
+package bookmarks
+
+import (
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+type Handler struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	services  *service.Services
+	validator *validator.Validate
+}
+
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, services *service.Services, validator *validator.Validate) *Handler {
+	return &Handler{log: log, tracer: tracer, services: services, validator: validator}
+}
+
+func (h *Handler) AddBookmark(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.AddBookmark")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	tweetID := c.Params("id")
+
+	if _, err := uuid.Parse(tweetID); err != nil {
+		h.log.Debugf("AddBookmark:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	err := h.services.Bookmarks.AddBookmark(ctx, userID.(string), tweetID)
+
+	if err != nil {
+		h.log.Errorf("AddBookmark:GRPC: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) DeleteBookmark(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.DeleteBookmark")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	tweetID := c.Params("id")
+
+	if _, err := uuid.Parse(tweetID); err != nil {
+		h.log.Debugf("DeleteBookmark:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	err := h.services.Bookmarks.DeleteBookmark(ctx, userID.(string), tweetID)
+
+	if err != nil {
+		h.log.Errorf("DeleteBookmark: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) GetBookmarks(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetBookmarks")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	cursor := c.Query("cursor")
+	limit := c.QueryInt("limit")
+
+	tweets, cursor, err := h.services.Bookmarks.GetBookmarks(ctx, userID.(string), cursor, limit)
+
+	if err != nil {
+		h.log.Errorf("GetBookmarks: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	tweets, err = h.services.FilterTweets(ctx, userID.(string), tweets)
+
+	if err != nil {
+		h.log.Errorf("GetBookmarks: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	if err := h.services.HydrateTweets(ctx, userID.(string), tweets); err != nil {
+		h.log.Errorf("GetBookmarks: %v", err.Error())
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   tweets,
+		"cursor": cursor,
+	})
+}
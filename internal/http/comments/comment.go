@@ -32,6 +32,19 @@ func (h *Handler) CreateComment(c *fiber.Ctx) error {
 
 	tweetID := c.Params("id")
 
+	tweet, err := h.services.Tweets.GetTweet(ctx, tweetID)
+
+	if err != nil {
+		h.log.Errorf("CreateComment:GRPC: %v", err.Error())
+		st, _ := status.FromError(err)
+		return response.WithGRPCError(c, st.Code())
+	}
+
+	if err := h.services.CheckAccess(ctx, userID.(string), tweet.UserID); err != nil {
+		h.log.Debugf("CreateComment: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
 	text := c.FormValue("text")
 
 	imageInput, err := c.FormFile("image")
@@ -54,6 +67,24 @@ func (h *Handler) CreateComment(c *fiber.Ctx) error {
 
 	}
 
+	mediaID := c.FormValue("media_id")
+
+	if mediaID != "" {
+		image, err = h.services.Media.GetImage(ctx, userID.(string), mediaID)
+
+		if err != nil {
+			h.log.Debugf("CreateComment:HTTP: %v", err.Error())
+			return response.WithError(c, err)
+		}
+	} else if image != nil {
+		mediaID, err = h.services.Media.StoreImage(ctx, userID.(string), image)
+
+		if err != nil {
+			h.log.Errorf("CreateComment: %v", err.Error())
+			return response.WithError(c, err)
+		}
+	}
+
 	commentID, err := h.services.Comments.CreateComment(ctx, domain.CreateCommentRequest{
 		UserID:  userID.(string),
 		TweetID: tweetID,
@@ -67,6 +98,24 @@ func (h *Handler) CreateComment(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	if mediaID != "" {
+		if err := h.services.Media.Attach(ctx, domain.CommentAttachment, commentID, mediaID); err != nil {
+			h.log.Errorf("CreateComment: %v", err.Error())
+		}
+	}
+
+	if tweet.UserID != userID.(string) {
+		err = h.services.Webhooks.Dispatch(ctx, tweet.UserID, domain.WebhookEventComment, domain.CommentEvent{
+			TweetID:   tweetID,
+			CommentID: commentID,
+			UserID:    userID.(string),
+		})
+
+		if err != nil {
+			h.log.Errorf("CreateComment: %v", err.Error())
+		}
+	}
+
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"id": commentID,
 	})
@@ -86,6 +135,10 @@ func (h *Handler) GetComment(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	if err := h.services.HydrateComment(ctx, &comment); err != nil {
+		h.log.Errorf("GetComment: %v", err.Error())
+	}
+
 	return c.Status(http.StatusOK).JSON(comment)
 }
 
@@ -93,10 +146,24 @@ func (h *Handler) GetAllTweetComments(c *fiber.Ctx) error {
 	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetComment")
 	defer span.End()
 
+	userID := c.Locals("userID")
 	tweetID := c.Params("id")
 
 	h.log.Debugf("tweetID: %v", tweetID)
 
+	tweet, err := h.services.Tweets.GetTweet(ctx, tweetID)
+
+	if err != nil {
+		h.log.Errorf("GetAllTweetComments:GRPC: %v", err.Error())
+		st, _ := status.FromError(err)
+		return response.WithGRPCError(c, st.Code())
+	}
+
+	if err := h.services.CheckAccess(ctx, userID.(string), tweet.UserID); err != nil {
+		h.log.Debugf("GetAllTweetComments: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
 	cursor := c.Query("cursor")
 
 	comments, cursor, err := h.services.Comments.GetAllTweetComments(ctx, cursor, tweetID)
@@ -106,6 +173,17 @@ func (h *Handler) GetAllTweetComments(c *fiber.Ctx) error {
 		return response.WithError(c, err)
 	}
 
+	comments, err = h.services.FilterComments(ctx, userID.(string), comments)
+
+	if err != nil {
+		h.log.Errorf("GetAllTweetComments: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	if err := h.services.HydrateComments(ctx, comments); err != nil {
+		h.log.Errorf("GetAllTweetComments: %v", err.Error())
+	}
+
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"data":   comments,
 		"cursor": cursor,
@@ -142,6 +220,24 @@ func (h *Handler) UpdateComment(c *fiber.Ctx) error {
 
 	}
 
+	mediaID := c.FormValue("media_id")
+
+	if mediaID != "" {
+		image, err = h.services.Media.GetImage(ctx, userID.(string), mediaID)
+
+		if err != nil {
+			h.log.Debugf("UpdateComment:HTTP: %v", err.Error())
+			return response.WithError(c, err)
+		}
+	} else if image != nil {
+		mediaID, err = h.services.Media.StoreImage(ctx, userID.(string), image)
+
+		if err != nil {
+			h.log.Errorf("UpdateComment: %v", err.Error())
+			return response.WithError(c, err)
+		}
+	}
+
 	comment, err := h.services.Comments.UpdateComment(ctx, domain.UpdateCommentRequest{
 		TweetID:   tweetID,
 		UserID:    userID.(string),
@@ -156,6 +252,16 @@ func (h *Handler) UpdateComment(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	if mediaID != "" {
+		if err := h.services.Media.Attach(ctx, domain.CommentAttachment, comment.CommentID, mediaID); err != nil {
+			h.log.Errorf("UpdateComment: %v", err.Error())
+		}
+	}
+
+	if err := h.services.HydrateComment(ctx, &comment); err != nil {
+		h.log.Errorf("UpdateComment: %v", err.Error())
+	}
+
 	return c.Status(http.StatusOK).JSON(comment)
 }
 
@@ -174,6 +280,10 @@ func (h *Handler) DeleteComment(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	if err := h.services.Media.Detach(ctx, domain.CommentAttachment, commentID); err != nil {
+		h.log.Errorf("DeleteComment: %v", err.Error())
+	}
+
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 	})
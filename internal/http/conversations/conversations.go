@@ -0,0 +1,173 @@
+package conversations
+
+import (
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Handler struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	services  *service.Services
+	validator *validator.Validate
+}
+
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, services *service.Services, validator *validator.Validate) *Handler {
+	return &Handler{log: log, tracer: tracer, services: services, validator: validator}
+}
+
+func (h *Handler) CreateConversation(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.CreateConversation")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.CreateConversationInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("CreateConversation:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	conversation, err := h.services.Conversations.CreateConversation(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("CreateConversation: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(conversation)
+}
+
+func (h *Handler) GetConversations(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetConversations")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	conversations, cursor, err := h.services.Conversations.GetConversations(ctx, userID.(string), c.Query("cursor"), c.QueryInt("limit"))
+
+	if err != nil {
+		h.log.Errorf("GetConversations: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   conversations,
+		"cursor": cursor,
+	})
+}
+
+func (h *Handler) GetConversation(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetConversation")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	conversationID := c.Params("id")
+
+	if _, err := uuid.Parse(conversationID); err != nil {
+		h.log.Debugf("GetConversation:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	conversation, err := h.services.Conversations.GetConversation(ctx, userID.(string), conversationID)
+
+	if err != nil {
+		h.log.Errorf("GetConversation: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(conversation)
+}
+
+func (h *Handler) SendMessage(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.SendMessage")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	conversationID := c.Params("id")
+
+	if _, err := uuid.Parse(conversationID); err != nil {
+		h.log.Debugf("SendMessage:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	var input domain.SendMessageInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("SendMessage:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	message, err := h.services.Conversations.SendMessage(ctx, userID.(string), conversationID, input)
+
+	if err != nil {
+		h.log.Errorf("SendMessage: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(message)
+}
+
+func (h *Handler) GetMessages(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetMessages")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	conversationID := c.Params("id")
+
+	if _, err := uuid.Parse(conversationID); err != nil {
+		h.log.Debugf("GetMessages:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	messages, cursor, err := h.services.Conversations.GetMessages(ctx, userID.(string), conversationID, c.Query("cursor"), c.QueryInt("limit"))
+
+	if err != nil {
+		h.log.Errorf("GetMessages: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   messages,
+		"cursor": cursor,
+	})
+}
+
+func (h *Handler) MarkRead(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.MarkRead")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	conversationID := c.Params("id")
+
+	if _, err := uuid.Parse(conversationID); err != nil {
+		h.log.Debugf("MarkRead:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	var input domain.ReadConversationInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("MarkRead:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	err := h.services.Conversations.MarkRead(ctx, userID.(string), conversationID, input.MessageID)
+
+	if err != nil {
+		h.log.Errorf("MarkRead: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
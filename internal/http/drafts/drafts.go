@@ -0,0 +1,175 @@
+package drafts
+
+import (
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+type Handler struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	services  *service.Services
+	validator *validator.Validate
+}
+
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, services *service.Services, validator *validator.Validate) *Handler {
+	return &Handler{log: log, tracer: tracer, services: services, validator: validator}
+}
+
+func (h *Handler) CreateDraft(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.CreateDraft")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.CreateDraftInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("CreateDraft:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	draft, err := h.services.Drafts.CreateDraft(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("CreateDraft: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(draft)
+}
+
+func (h *Handler) GetDraft(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetDraft")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	draftID := c.Params("id")
+
+	if _, err := uuid.Parse(draftID); err != nil {
+		return response.WithError(c, response.ErrDraftNotFound)
+	}
+
+	draft, err := h.services.Drafts.GetDraft(ctx, userID.(string), draftID)
+
+	if err != nil {
+		h.log.Debugf("GetDraft: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(draft)
+}
+
+func (h *Handler) GetDrafts(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetDrafts")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	cursor := c.Query("cursor")
+
+	drafts, cursor, err := h.services.Drafts.GetDrafts(ctx, userID.(string), cursor, c.QueryInt("limit"))
+
+	if err != nil {
+		h.log.Errorf("GetDrafts: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   drafts,
+		"cursor": cursor,
+	})
+}
+
+func (h *Handler) UpdateDraft(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.UpdateDraft")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	draftID := c.Params("id")
+
+	if _, err := uuid.Parse(draftID); err != nil {
+		return response.WithError(c, response.ErrDraftNotFound)
+	}
+
+	var input domain.UpdateDraftInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("UpdateDraft:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	draft, err := h.services.Drafts.UpdateDraft(ctx, userID.(string), draftID, input)
+
+	if errors.Is(err, response.ErrDraftVersionConflict) {
+		h.log.Debugf("UpdateDraft: %v", err.Error())
+		return c.Status(http.StatusConflict).JSON(fiber.Map{
+			"message": err.Error(),
+			"draft":   draft,
+		})
+	}
+
+	if err != nil {
+		h.log.Errorf("UpdateDraft: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(draft)
+}
+
+func (h *Handler) DeleteDraft(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.DeleteDraft")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	draftID := c.Params("id")
+
+	if _, err := uuid.Parse(draftID); err != nil {
+		return response.WithError(c, response.ErrDraftNotFound)
+	}
+
+	err := h.services.Drafts.DeleteDraft(ctx, userID.(string), draftID)
+
+	if err != nil {
+		h.log.Errorf("DeleteDraft: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) PublishDraft(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.PublishDraft")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	draftID := c.Params("id")
+
+	if _, err := uuid.Parse(draftID); err != nil {
+		return response.WithError(c, response.ErrDraftNotFound)
+	}
+
+	tweetID, err := h.services.Drafts.PublishDraft(ctx, userID.(string), draftID)
+
+	if err != nil {
+		h.log.Errorf("PublishDraft: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"id": tweetID,
+	})
+}
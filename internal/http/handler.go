@@ -1,11 +1,21 @@
 package http
 
 import (
+	"github.com/Verce11o/yata/internal/domain"
 	authHandler "github.com/Verce11o/yata/internal/http/auth"
+	bookmarksHandler "github.com/Verce11o/yata/internal/http/bookmarks"
 	commentsHandler "github.com/Verce11o/yata/internal/http/comments"
+	conversationsHandler "github.com/Verce11o/yata/internal/http/conversations"
+	draftsHandler "github.com/Verce11o/yata/internal/http/drafts"
+	healthHandler "github.com/Verce11o/yata/internal/http/health"
+	mediaHandler "github.com/Verce11o/yata/internal/http/media"
 	middlewareHandler "github.com/Verce11o/yata/internal/http/middleware"
 	notificationHandler "github.com/Verce11o/yata/internal/http/notifications"
 	tweetHandler "github.com/Verce11o/yata/internal/http/tweets"
+	usersHandler "github.com/Verce11o/yata/internal/http/users"
+	webhooksHandler "github.com/Verce11o/yata/internal/http/webhooks"
+	websocketHandler "github.com/Verce11o/yata/internal/http/websocket"
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -14,48 +24,144 @@ type Handlers struct {
 	tweets        *tweetHandler.Handler
 	comments      *commentsHandler.Handler
 	notifications *notificationHandler.Handler
+	media         *mediaHandler.Handler
+	bookmarks     *bookmarksHandler.Handler
+	drafts        *draftsHandler.Handler
+	users         *usersHandler.Handler
+	conversations *conversationsHandler.Handler
+	webhooks      *webhooksHandler.Handler
+	websocket     *websocketHandler.Handler
+	health        *healthHandler.Handler
 	middleware    *middlewareHandler.Handler
 }
 
-func NewHandlers(auth *authHandler.Handler, tweets *tweetHandler.Handler, comments *commentsHandler.Handler, notifications *notificationHandler.Handler, middleware *middlewareHandler.Handler) *Handlers {
-	return &Handlers{auth: auth, tweets: tweets, comments: comments, notifications: notifications, middleware: middleware}
+func NewHandlers(auth *authHandler.Handler, tweets *tweetHandler.Handler, comments *commentsHandler.Handler, notifications *notificationHandler.Handler, media *mediaHandler.Handler, bookmarks *bookmarksHandler.Handler, drafts *draftsHandler.Handler, users *usersHandler.Handler, conversations *conversationsHandler.Handler, webhooks *webhooksHandler.Handler, websocket *websocketHandler.Handler, health *healthHandler.Handler, middleware *middlewareHandler.Handler) *Handlers {
+	return &Handlers{auth: auth, tweets: tweets, comments: comments, notifications: notifications, media: media, bookmarks: bookmarks, drafts: drafts, users: users, conversations: conversations, webhooks: webhooks, websocket: websocket, health: health, middleware: middleware}
 }
 
 func (h *Handlers) InitRoutes(app *fiber.App) {
 	api := app.Group("/api")
 	{
+		api.Get("/health", h.health.Health)
+
 		auth := api.Group("/auth")
 		{
 			auth.Post("/signup", h.auth.SignUp)
 			auth.Post("/login", h.auth.Login)
+			auth.Post("/login/2fa", h.auth.LoginTwoFactor)
+
+			auth.Get("/oidc/:provider", h.auth.OIDCLogin)
+			auth.Get("/oidc/:provider/callback", h.auth.OIDCCallback)
 
 		}
 
-		user := api.Group("/user", h.middleware.AuthMiddleware)
+		user := api.Group("/user", h.middleware.AuthMiddleware, h.middleware.RequireVerified)
 		{
-			user.Get("/getme", h.auth.GetUserByID)
-			user.Post("/verify", h.auth.Verify)
-			user.Get("/activate", h.auth.Activate)
+			user.Get("/getme", h.middleware.RequireScope(domain.ScopeUsers), h.auth.GetUserByID)
+			user.Post("/verify", h.middleware.SessionOnly, h.auth.Verify)
+			user.Get("/activate", h.middleware.SessionOnly, h.auth.Activate)
+
+			user.Post("/forgot-password", h.middleware.SessionOnly, h.auth.ForgotPassword)
+			user.Get("/verify-password", h.middleware.SessionOnly, h.auth.VerifyPassword)
+			user.Put("/reset-password", h.middleware.SessionOnly, h.middleware.PasswordResetMiddleware, h.auth.ResetPassword)
+
+			user.Get("/bookmarks", h.middleware.RequireScope(domain.ScopeTweets), h.bookmarks.GetBookmarks)
+
+			user.Get("/settings", h.middleware.RequireScope(domain.ScopeUsers), h.users.GetSettings)
+			user.Put("/settings", h.middleware.RequireScope(domain.ScopeUsers), h.users.UpdateSettings)
+
+			user.Get("/follow-requests", h.middleware.RequireScope(domain.ScopeUsers), h.notifications.GetFollowRequests)
+			user.Post("/follow-requests/:id/approve", h.middleware.RequireScope(domain.ScopeUsers), h.notifications.ApproveFollowRequest)
+			user.Post("/follow-requests/:id/reject", h.middleware.RequireScope(domain.ScopeUsers), h.notifications.RejectFollowRequest)
 
-			user.Post("/forgot-password", h.auth.ForgotPassword)
-			user.Get("/verify-password", h.auth.VerifyPassword)
-			user.Put("/reset-password", h.middleware.PasswordResetMiddleware, h.auth.ResetPassword)
+			user.Post("/oidc/:provider/link", h.middleware.SessionOnly, h.auth.LinkOIDC)
+
+			twoFactor := user.Group("/2fa", h.middleware.SessionOnly)
+			{
+				twoFactor.Get("/", h.auth.GetTwoFactorStatus)
+				twoFactor.Post("/setup", h.auth.SetupTwoFactor)
+				twoFactor.Post("/confirm", h.auth.ConfirmTwoFactor)
+				twoFactor.Post("/disable", h.auth.DisableTwoFactor)
+				twoFactor.Post("/recovery-codes", h.auth.RegenerateRecoveryCodes)
+			}
+
+			apiKeys := user.Group("/api-keys", h.middleware.SessionOnly)
+			{
+				apiKeys.Post("/", h.users.CreateAPIKey)
+				apiKeys.Get("/", h.users.GetAPIKeys)
+				apiKeys.Delete("/:id", h.users.RevokeAPIKey)
+			}
 
 			subscribe := user.Group("/:id")
 			{
-				subscribe.Post("/subscribe", h.notifications.SubscribeToUser)
-				subscribe.Post("/unsubscribe", h.notifications.UnSubscribeFromUser)
+				subscribe.Post("/subscribe", h.middleware.RequireScope(domain.ScopeUsers), h.notifications.SubscribeToUser)
+				subscribe.Post("/unsubscribe", h.middleware.RequireScope(domain.ScopeUsers), h.notifications.UnSubscribeFromUser)
 			}
 
 		}
 
-		tweets := api.Group("/tweets", h.middleware.AuthMiddleware)
+		users := api.Group("/users", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeUsers), h.middleware.RequireVerified)
+		{
+			users.Post("/:id/block", h.users.BlockUser)
+			users.Delete("/:id/block", h.users.UnblockUser)
+			users.Post("/:id/mute", h.users.MuteUser)
+			users.Delete("/:id/mute", h.users.UnmuteUser)
+
+			users.Get("/:id/followers", h.notifications.GetFollowers)
+			users.Get("/:id/following", h.notifications.GetFollowing)
+			users.Get("/:id/mutuals", h.notifications.GetMutuals)
+			users.Get("/:id/presence", h.users.GetPresence)
+		}
+
+		conversations := api.Group("/conversations", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeMessages), h.middleware.RequireVerified)
+		{
+			conversations.Post("/", h.conversations.CreateConversation)
+			conversations.Get("/", h.conversations.GetConversations)
+			conversations.Get("/:id", h.conversations.GetConversation)
+			conversations.Post("/:id/messages", h.conversations.SendMessage)
+			conversations.Get("/:id/messages", h.conversations.GetMessages)
+			conversations.Post("/:id/read", h.conversations.MarkRead)
+		}
+
+		webhooks := api.Group("/webhooks", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeWebhooks), h.middleware.RequireVerified)
+		{
+			webhooks.Post("/", h.webhooks.CreateWebhook)
+			webhooks.Get("/", h.webhooks.GetWebhooks)
+			webhooks.Get("/:id", h.webhooks.GetWebhook)
+			webhooks.Put("/:id", h.webhooks.UpdateWebhook)
+			webhooks.Delete("/:id", h.webhooks.DeleteWebhook)
+			webhooks.Get("/:id/deliveries", h.webhooks.GetDeliveries)
+			webhooks.Post("/:id/test", h.webhooks.SendTestEvent)
+		}
+
+		recommendations := api.Group("/recommendations", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeUsers), h.middleware.RequireVerified)
+		{
+			recommendations.Get("/users", h.users.RecommendUsers)
+		}
+
+		tweets := api.Group("/tweets", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeTweets), h.middleware.RequireVerified)
 		{
 			tweets.Post("/", h.tweets.CreateTweet)
 			tweets.Get("/", h.tweets.GetAllTweets)
+
+			scheduled := tweets.Group("/scheduled")
+			{
+				scheduled.Post("/", h.tweets.CreateScheduledTweet)
+				scheduled.Get("/", h.tweets.GetScheduledTweets)
+				scheduled.Patch("/:id", h.tweets.UpdateScheduledTweet)
+				scheduled.Delete("/:id", h.tweets.CancelScheduledTweet)
+			}
+
 			tweets.Get("/:id", h.tweets.GetTweet)
 			tweets.Put("/:id", h.tweets.UpdateTweet)
 			tweets.Delete("/:id", h.tweets.DeleteTweet)
+			tweets.Get("/:id/history", h.tweets.GetTweetHistory)
+			tweets.Get("/:id/thread", h.tweets.GetTweetThread)
+
+			tweets.Post("/:id/poll/vote", h.tweets.VotePoll)
+
+			tweets.Post("/:id/bookmark", h.bookmarks.AddBookmark)
+			tweets.Delete("/:id/bookmark", h.bookmarks.DeleteBookmark)
 
 			comments := tweets.Group("/:id/comments")
 			{
@@ -67,11 +173,43 @@ func (h *Handlers) InitRoutes(app *fiber.App) {
 
 		}
 
-		notifications := api.Group("/notifications", h.middleware.AuthMiddleware)
+		threads := api.Group("/threads", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeTweets), h.middleware.RequireVerified)
+		{
+			threads.Post("/", h.tweets.CreateThread)
+		}
+
+		drafts := api.Group("/drafts", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeTweets), h.middleware.RequireVerified)
+		{
+			drafts.Post("/", h.drafts.CreateDraft)
+			drafts.Get("/", h.drafts.GetDrafts)
+			drafts.Get("/:id", h.drafts.GetDraft)
+			drafts.Put("/:id", h.drafts.UpdateDraft)
+			drafts.Delete("/:id", h.drafts.DeleteDraft)
+			drafts.Post("/:id/publish", h.drafts.PublishDraft)
+		}
+
+		notifications := api.Group("/notifications", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeNotifications), h.middleware.RequireVerified)
 		{
 			notifications.Get("/", h.notifications.GetNotifications)
+			notifications.Get("/unread-count", h.notifications.GetUnreadCount)
+			notifications.Post("/read", h.notifications.MarkNotificationsAsRead)
+			notifications.Post("/groups/:id/read", h.notifications.MarkGroupAsRead)
+			notifications.Get("/preferences", h.notifications.GetPreferences)
+			notifications.Put("/preferences", h.notifications.UpdatePreferences)
+			notifications.Get("/ws", h.websocket.Upgrade, websocket.New(h.websocket.EstablishConnection))
+			notifications.Get("/stream", h.websocket.Stream)
 			notifications.Post("/read-notification", h.notifications.MarkNotificationAsRead)
 			notifications.Post("/read-all-notifications", h.notifications.ReadAllNotifications)
+			notifications.Patch("/:id", h.notifications.UpdateNotification)
+		}
+
+		api.Get("/media/:name", h.media.ServeMedia)
+
+		media := api.Group("/media", h.middleware.AuthMiddleware, h.middleware.RequireScope(domain.ScopeTweets), h.middleware.RequireVerified)
+		{
+			media.Post("/", h.media.InitUpload)
+			media.Put("/:id", h.media.AppendChunk)
+			media.Post("/:id/complete", h.media.CompleteUpload)
 		}
 
 	}
@@ -0,0 +1,38 @@
+package health
+
+import (
+	"github.com/Verce11o/yata/internal/scheduler"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Handler struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	scheduler *scheduler.Scheduler
+}
+
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, scheduler *scheduler.Scheduler) *Handler {
+	return &Handler{log: log, tracer: tracer, scheduler: scheduler}
+}
+
+// Health is public, so it reports only status of each dependency. Holder and errors may contain
+// hostnames and addresses, they are logged by the scheduler itself
+func (h *Handler) Health(c *fiber.Ctx) error {
+	_, span := h.tracer.Start(c.UserContext(), "Gateway.Health")
+	defer span.End()
+
+	schedulerStatus := "ok"
+
+	if state := h.scheduler.State(); !state.Running {
+		schedulerStatus = "stopped"
+		h.log.Debugf("Health: scheduler %s is not running, last error: %s", state.Holder, state.LastError)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"status":    "ok",
+		"scheduler": schedulerStatus,
+	})
+}
@@ -0,0 +1,221 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Handler struct {
+	log         *zap.SugaredLogger
+	tracer      trace.Tracer
+	services    *service.Services
+	validator   *validator.Validate
+	cacheMaxAge time.Duration
+}
+
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, services *service.Services, validator *validator.Validate, cfg config.Media) *Handler {
+	return &Handler{log: log, tracer: tracer, services: services, validator: validator, cacheMaxAge: cfg.CacheMaxAge}
+}
+
+func (h *Handler) InitUpload(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.InitUpload")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.InitMediaUploadInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Errorf("InitUpload:HTTP: %s", err.Error())
+		return response.WithError(c, err)
+	}
+
+	upload, err := h.services.Media.InitUpload(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("InitUpload: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(upload)
+}
+
+func (h *Handler) AppendChunk(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.AppendChunk")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	uploadID := c.Params("id")
+
+	offset, err := strconv.ParseInt(c.Query("offset"), 10, 64)
+
+	if err != nil || offset < 0 {
+		h.log.Debugf("AppendChunk:HTTP: invalid offset %q", c.Query("offset"))
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	upload, err := h.services.Media.AppendChunk(ctx, userID.(string), uploadID, offset, c.Body())
+
+	if errors.Is(err, response.ErrInvalidOffset) {
+		h.log.Debugf("AppendChunk: %v", err.Error())
+		return c.Status(http.StatusConflict).JSON(fiber.Map{
+			"message": err.Error(),
+			"offset":  upload.Offset,
+		})
+	}
+
+	if err != nil {
+		h.log.Errorf("AppendChunk: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(upload)
+}
+
+func (h *Handler) CompleteUpload(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.CompleteUpload")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	uploadID := c.Params("id")
+
+	media, err := h.services.Media.CompleteUpload(ctx, userID.(string), uploadID)
+
+	if err != nil {
+		h.log.Errorf("CompleteUpload: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(media)
+}
+
+func (h *Handler) ServeMedia(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.ServeMedia")
+	defer span.End()
+
+	name := c.Params("name")
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+
+	reader, info, err := h.services.Media.OpenMedia(ctx, name, expires, c.Query("signature"))
+
+	if err != nil {
+		h.log.Debugf("ServeMedia: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	etag := fmt.Sprintf("%q", info.ETag)
+
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, info.ModTime.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+
+	if info.Metadata["private"] == "true" {
+		c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", max(expires-time.Now().Unix(), 0)))
+	} else {
+		c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d, immutable", int64(h.cacheMaxAge.Seconds())))
+	}
+
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" && (match == etag || match == "*") {
+		reader.Close()
+		return c.SendStatus(http.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, info.ContentType)
+
+	start, end, err := parseRange(c.Get(fiber.HeaderRange), info.Size)
+
+	if errors.Is(err, errUnsatisfiableRange) {
+		reader.Close()
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", info.Size))
+		return c.SendStatus(http.StatusRequestedRangeNotSatisfiable)
+	}
+
+	// If-Range with stale validator means client has to get the whole file
+	if ifRange := c.Get(fiber.HeaderIfRange); ifRange != "" && ifRange != etag {
+		err = errNoRange
+	}
+
+	if errors.Is(err, errNoRange) {
+		return c.Status(http.StatusOK).SendStream(reader, int(info.Size))
+	}
+
+	if _, err := reader.Seek(start, io.SeekStart); err != nil {
+		reader.Close()
+		h.log.Errorf("ServeMedia: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	length := end - start + 1
+
+	c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, end, info.Size))
+
+	return c.Status(http.StatusPartialContent).SendStream(struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(reader, length), reader}, int(length))
+}
+
+var (
+	errNoRange            = errors.New("no range")
+	errUnsatisfiableRange = errors.New("unsatisfiable range")
+)
+
+// parseRange supports single byte range only, multiple ranges are served as a whole file
+func parseRange(header string, size int64) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, errNoRange
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, 0, errNoRange
+	}
+
+	var start, end int64
+
+	if first == "" {
+		// suffix range: last N bytes
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix <= 0 {
+			return 0, 0, errUnsatisfiableRange
+		}
+		start, end = max(size-suffix, 0), size-1
+	} else {
+		var err error
+
+		start, err = strconv.ParseInt(first, 10, 64)
+		if err != nil || start < 0 {
+			return 0, 0, errNoRange
+		}
+
+		end = size - 1
+
+		if last != "" {
+			end, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || end < start {
+				return 0, 0, errNoRange
+			}
+			end = min(end, size-1)
+		}
+	}
+
+	if start >= size {
+		return 0, 0, errUnsatisfiableRange
+	}
+
+	return start, end, nil
+}
@@ -16,6 +16,8 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"path"
+	"slices"
 	"strings"
 )
 
@@ -55,6 +57,23 @@ func (h *Handler) AuthMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
+	if strings.EqualFold(headerParts[0], "ApiKey") {
+		span.AddEvent("authenticate api key")
+
+		key, err := h.services.APIKeys.Authenticate(ctx, headerParts[1])
+
+		if err != nil {
+			h.log.Infof("AuthMiddleware: %v", err.Error())
+			return response.WithError(c, err)
+		}
+
+		c.Locals("userID", key.UserID)
+		c.Locals("scopes", key.Scopes)
+
+		span.AddEvent("next request")
+		return c.Next()
+	}
+
 	span.AddEvent("parseToken")
 
 	userID, err := token.ParseToken(headerParts[1], h.cfg.App.JWT.Secret)
@@ -91,6 +110,88 @@ func (h *Handler) AuthMiddleware(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+// RequireScope limits api keys to routes of granted resources, GET needs "<resource>:read" and other methods "<resource>:write".
+// Session tokens are not limited
+func (h *Handler) RequireScope(resource string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scopes, ok := c.Locals("scopes").([]string)
+		if !ok {
+			return c.Next()
+		}
+
+		scope := resource + ":write"
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			scope = resource + ":read"
+		}
+
+		if !slices.Contains(scopes, scope) {
+			h.log.Debugf("RequireScope: missing %s", scope)
+			return response.WithError(c, response.ErrInsufficientScope)
+		}
+
+		return c.Next()
+	}
+}
+
+// SessionOnly rejects api keys on account routes, so a leaked key cannot take over the account
+func (h *Handler) SessionOnly(c *fiber.Ctx) error {
+	if c.Locals("scopes") != nil {
+		h.log.Debugf("SessionOnly: api key used for %s", c.OriginalURL())
+		return response.WithError(c, response.ErrSessionRequired)
+	}
+
+	return c.Next()
+}
+
+// RequireVerified rejects mutating requests of users who did not verify email, except for allowlisted paths.
+// Reads are not limited
+func (h *Handler) RequireVerified(c *fiber.Ctx) error {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return c.Next()
+	}
+
+	if h.verificationAllowed(c.Path()) {
+		return c.Next()
+	}
+
+	ctx, span := h.tracer.Start(c.UserContext(), "RequireVerified")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	verified, err := h.services.Verification.IsVerified(ctx, userID.(string))
+
+	if err != nil {
+		h.log.Errorf("RequireVerified: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	if !verified {
+		h.log.Debugf("RequireVerified: %s %s by unverified user %s", c.Method(), c.Path(), userID)
+		return response.WithError(c, response.ErrEmailNotVerified)
+	}
+
+	return c.Next()
+}
+
+func (h *Handler) verificationAllowed(requestPath string) bool {
+	if len(requestPath) > 1 {
+		requestPath = strings.TrimSuffix(requestPath, "/")
+	}
+
+	for _, pattern := range h.cfg.Verification.Allowlist {
+		if matched, _ := path.Match(pattern, requestPath); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
 // TODO find better solution (this middleware calls everytime when sending request to password reset. must call only once)
 
 func (h *Handler) PasswordResetMiddleware(c *fiber.Ctx) error {
@@ -1,6 +1,8 @@
 package notifications
 
 import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
 	"github.com/Verce11o/yata/internal/lib/response"
 	"github.com/Verce11o/yata/internal/service"
 	"github.com/go-playground/validator/v10"
@@ -44,16 +46,24 @@ func (h *Handler) SubscribeToUser(c *fiber.Ctx) error {
 		return response.WithError(c, response.ErrUserNotFound)
 	}
 
-	err = h.services.Notifications.SubscribeToUser(ctx, userID.(string), toUserID)
+	if err := h.services.Relations.CheckBlocked(ctx, toUserID, userID.(string)); err != nil {
+		h.log.Debugf("SubscribeToUser: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	followStatus, err := h.services.Notifications.SubscribeToUser(ctx, userID.(string), toUserID)
 
 	if err != nil {
 		h.log.Errorf("SubscribeToUser:GRPC: %v", err.Error())
-		st, _ := status.FromError(err)
-		return response.WithGRPCError(c, st.Code())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
+		"status":  followStatus,
 	})
 }
 
@@ -75,8 +85,10 @@ func (h *Handler) UnSubscribeFromUser(c *fiber.Ctx) error {
 
 	if err != nil {
 		h.log.Errorf("UnSubscribeFromUser:GRPC: %v", err.Error())
-		st, _ := status.FromError(err)
-		return response.WithGRPCError(c, st.Code())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{
@@ -90,18 +102,160 @@ func (h *Handler) GetNotifications(c *fiber.Ctx) error {
 
 	userID := c.Locals("userID")
 
-	resp, err := h.services.Notifications.GetNotifications(ctx, userID.(string))
+	query := domain.NotificationQuery{
+		Type:       c.Query("type"),
+		UnreadOnly: c.QueryBool("unread_only"),
+		Cursor:     c.Query("cursor"),
+		Limit:      c.QueryInt("limit"),
+	}
+
+	if c.QueryBool("grouped") {
+		return h.getNotificationGroups(ctx, c, userID.(string), query)
+	}
+
+	resp, cursor, err := h.services.Notifications.GetNotifications(ctx, userID.(string), query)
 
 	if err != nil {
 		h.log.Errorf("GetNotifications:GRPC: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   resp,
+		"cursor": cursor,
+	})
+
+}
+
+func (h *Handler) getNotificationGroups(ctx context.Context, c *fiber.Ctx, userID string, query domain.NotificationQuery) error {
+	resp, cursor, err := h.services.Notifications.GetNotificationGroups(ctx, userID, query)
+
+	if err != nil {
+		h.log.Errorf("GetNotificationGroups:GRPC: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   resp,
+		"cursor": cursor,
+	})
+}
+
+func (h *Handler) MarkGroupAsRead(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.MarkGroupAsRead")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	groupID := c.Params("id")
+
+	if _, err := uuid.Parse(groupID); err != nil {
+		h.log.Debugf("MarkGroupAsRead:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	err := h.services.Notifications.MarkGroupAsRead(ctx, userID.(string), groupID)
+
+	if err != nil {
+		h.log.Errorf("MarkGroupAsRead:GRPC: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) GetUnreadCount(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetUnreadCount")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	count, err := h.services.Notifications.GetUnreadCount(ctx, userID.(string))
+
+	if err != nil {
+		h.log.Errorf("GetUnreadCount: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"count": count,
+	})
+}
+
+func (h *Handler) UpdateNotification(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.UpdateNotification")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	notificationID := c.Params("id")
+
+	if _, err := uuid.Parse(notificationID); err != nil {
+		h.log.Debugf("UpdateNotification:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	var input domain.UpdateNotificationInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("UpdateNotification:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	// notifications service cannot mark notification as unread again
+	if !*input.Read {
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	err := h.services.Notifications.MarkNotificationAsRead(ctx, userID.(string), notificationID)
+
+	if err != nil {
+		h.log.Errorf("UpdateNotification:GRPC: %v", err.Error())
 		st, _ := status.FromError(err)
 		return response.WithGRPCError(c, st.Code())
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{
-		"data": resp,
+		"message": "success",
 	})
+}
+
+func (h *Handler) MarkNotificationsAsRead(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.MarkNotificationsAsRead")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.MarkNotificationsInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("MarkNotificationsAsRead:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
 
+	err := h.services.Notifications.MarkNotificationsAsRead(ctx, userID.(string), input.IDs)
+
+	if err != nil {
+		h.log.Errorf("MarkNotificationsAsRead:GRPC: %v", err.Error())
+		st, _ := status.FromError(err)
+		return response.WithGRPCError(c, st.Code())
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
 }
 
 func (h *Handler) MarkNotificationAsRead(c *fiber.Ctx) error {
@@ -151,3 +305,164 @@ func (h *Handler) ReadAllNotifications(c *fiber.Ctx) error {
 	})
 
 }
+
+func (h *Handler) GetFollowRequests(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetFollowRequests")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	cursor := c.Query("cursor")
+	limit := c.QueryInt("limit")
+
+	requests, cursor, err := h.services.Notifications.GetFollowRequests(ctx, userID.(string), cursor, limit)
+
+	if err != nil {
+		h.log.Errorf("GetFollowRequests: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   requests,
+		"cursor": cursor,
+	})
+}
+
+func (h *Handler) ApproveFollowRequest(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.ApproveFollowRequest")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	requesterID := c.Params("id")
+
+	if _, err := uuid.Parse(requesterID); err != nil {
+		h.log.Debugf("ApproveFollowRequest:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrFollowRequestNotFound)
+	}
+
+	err := h.services.Notifications.ApproveFollowRequest(ctx, userID.(string), requesterID)
+
+	if err != nil {
+		h.log.Errorf("ApproveFollowRequest: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) RejectFollowRequest(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.RejectFollowRequest")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	requesterID := c.Params("id")
+
+	if _, err := uuid.Parse(requesterID); err != nil {
+		h.log.Debugf("RejectFollowRequest:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrFollowRequestNotFound)
+	}
+
+	if err := h.services.Notifications.RejectFollowRequest(ctx, userID.(string), requesterID); err != nil {
+		h.log.Errorf("RejectFollowRequest: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) GetFollowers(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetFollowers")
+	defer span.End()
+
+	return h.listFollows(ctx, c, "GetFollowers", h.services.Notifications.GetFollowers)
+}
+
+func (h *Handler) GetFollowing(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetFollowing")
+	defer span.End()
+
+	return h.listFollows(ctx, c, "GetFollowing", h.services.Notifications.GetFollowing)
+}
+
+func (h *Handler) GetMutuals(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetMutuals")
+	defer span.End()
+
+	return h.listFollows(ctx, c, "GetMutuals", h.services.Notifications.GetMutuals)
+}
+
+type listFollowsFunc func(ctx context.Context, viewerID, userID, cursor string, limit int) ([]domain.FollowUser, string, error)
+
+func (h *Handler) listFollows(ctx context.Context, c *fiber.Ctx, name string, list listFollowsFunc) error {
+	viewerID := c.Locals("userID")
+	userID := c.Params("id")
+	cursor := c.Query("cursor")
+	limit := c.QueryInt("limit")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		h.log.Debugf("%s:HTTP: %v", name, err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	if err := h.services.CheckAccess(ctx, viewerID.(string), userID); err != nil {
+		h.log.Debugf("%s: %v", name, err.Error())
+		return response.WithError(c, err)
+	}
+
+	users, cursor, err := list(ctx, viewerID.(string), userID, cursor, limit)
+
+	if err != nil {
+		h.log.Errorf("%s: %v", name, err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   users,
+		"cursor": cursor,
+	})
+}
+
+func (h *Handler) GetPreferences(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetPreferences")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	preferences, err := h.services.Notifications.GetPreferences(ctx, userID.(string))
+
+	if err != nil {
+		h.log.Errorf("GetPreferences: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(preferences)
+}
+
+func (h *Handler) UpdatePreferences(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.UpdatePreferences")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.UpdateNotificationPreferencesInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("UpdatePreferences:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	preferences, err := h.services.Notifications.UpdatePreferences(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("UpdatePreferences: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(preferences)
+}
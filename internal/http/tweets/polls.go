@@ -0,0 +1,58 @@
+package tweets
+
+import (
+	"encoding/json"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (h *Handler) VotePoll(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.VotePoll")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	tweetID := c.Params("id")
+
+	if _, err := uuid.Parse(tweetID); err != nil {
+		return response.WithError(c, response.ErrPollNotFound)
+	}
+
+	var input domain.VotePollInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("VotePoll:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	poll, err := h.services.Polls.Vote(ctx, userID.(string), tweetID, *input.Option)
+
+	if err != nil {
+		h.log.Debugf("VotePoll: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(poll)
+}
+
+// readPoll reads optional poll sent as JSON in "poll" form field next to text and image
+func (h *Handler) readPoll(c *fiber.Ctx) (*domain.CreatePollInput, error) {
+	raw := c.FormValue("poll")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var poll domain.CreatePollInput
+
+	if err := json.Unmarshal([]byte(raw), &poll); err != nil {
+		return nil, response.ErrInvalidRequest
+	}
+
+	if err := h.validator.Struct(poll); err != nil {
+		return nil, response.ErrInvalidPoll
+	}
+
+	return &poll, nil
+}
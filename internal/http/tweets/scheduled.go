@@ -0,0 +1,105 @@
+package tweets
+
+import (
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (h *Handler) CreateScheduledTweet(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.CreateScheduledTweet")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.CreateScheduledTweetInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("CreateScheduledTweet:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	tweet, err := h.services.ScheduledTweets.CreateScheduledTweet(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("CreateScheduledTweet: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(tweet)
+}
+
+func (h *Handler) GetScheduledTweets(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetScheduledTweets")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	cursor := c.Query("cursor")
+
+	tweets, cursor, err := h.services.ScheduledTweets.GetScheduledTweets(ctx, userID.(string), cursor, c.QueryInt("limit"))
+
+	if err != nil {
+		h.log.Errorf("GetScheduledTweets: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   tweets,
+		"cursor": cursor,
+	})
+}
+
+func (h *Handler) UpdateScheduledTweet(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.UpdateScheduledTweet")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	scheduledID := c.Params("id")
+
+	if _, err := uuid.Parse(scheduledID); err != nil {
+		h.log.Debugf("UpdateScheduledTweet:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrScheduledTweetNotFound)
+	}
+
+	var input domain.UpdateScheduledTweetInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("UpdateScheduledTweet:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	tweet, err := h.services.ScheduledTweets.UpdateScheduledTweet(ctx, userID.(string), scheduledID, input)
+
+	if err != nil {
+		h.log.Errorf("UpdateScheduledTweet: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(tweet)
+}
+
+func (h *Handler) CancelScheduledTweet(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.CancelScheduledTweet")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	scheduledID := c.Params("id")
+
+	if _, err := uuid.Parse(scheduledID); err != nil {
+		h.log.Debugf("CancelScheduledTweet:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrScheduledTweetNotFound)
+	}
+
+	err := h.services.ScheduledTweets.CancelScheduledTweet(ctx, userID.(string), scheduledID)
+
+	if err != nil {
+		h.log.Errorf("CancelScheduledTweet: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
@@ -0,0 +1,77 @@
+package tweets
+
+import (
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+func (h *Handler) CreateThread(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.CreateThread")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.CreateThreadInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("CreateThread:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	tweetIDs, conversationID, err := h.services.Threads.CreateThread(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("CreateThread: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":            tweetIDs,
+		"conversation_id": conversationID,
+	})
+}
+
+func (h *Handler) GetTweetThread(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetTweetThread")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	tweetID := c.Params("id")
+
+	if _, err := uuid.Parse(tweetID); err != nil {
+		h.log.Debugf("GetTweetThread:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	tweets, err := h.services.Threads.GetThread(ctx, tweetID)
+
+	if err != nil {
+		h.log.Errorf("GetTweetThread: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	tweets, err = h.services.FilterTweets(ctx, userID.(string), tweets)
+
+	if err != nil {
+		h.log.Errorf("GetTweetThread: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	if err := h.services.HydrateTweets(ctx, userID.(string), tweets); err != nil {
+		h.log.Errorf("GetTweetThread: %v", err.Error())
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": tweets,
+	})
+}
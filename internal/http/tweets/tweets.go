@@ -7,8 +7,10 @@ import (
 	"github.com/Verce11o/yata/internal/service"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"net/http"
 )
@@ -52,6 +54,52 @@ func (h *Handler) CreateTweet(c *fiber.Ctx) error {
 
 	}
 
+	poll, err := h.readPoll(c)
+
+	if err != nil {
+		h.log.Debugf("CreateTweet:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	inReplyTo := c.FormValue("in_reply_to_tweet_id")
+
+	var conversationID string
+
+	if inReplyTo != "" {
+		if _, err := uuid.Parse(inReplyTo); err != nil {
+			h.log.Debugf("CreateTweet:HTTP: %v", err.Error())
+			return response.WithError(c, response.ErrInvalidRequest)
+		}
+
+		conversationID, err = h.services.Threads.GetConversationID(ctx, userID.(string), inReplyTo)
+
+		if err != nil {
+			h.log.Errorf("CreateTweet: %v", err.Error())
+			if st, ok := status.FromError(err); ok {
+				return response.WithGRPCError(c, st.Code())
+			}
+			return response.WithError(c, err)
+		}
+	}
+
+	mediaID := c.FormValue("media_id")
+
+	if mediaID != "" {
+		image, err = h.services.Media.GetImage(ctx, userID.(string), mediaID)
+
+		if err != nil {
+			h.log.Debugf("CreateTweet:HTTP: %v", err.Error())
+			return response.WithError(c, err)
+		}
+	} else if image != nil {
+		mediaID, err = h.services.Media.StoreImage(ctx, userID.(string), image)
+
+		if err != nil {
+			h.log.Errorf("CreateTweet: %v", err.Error())
+			return response.WithError(c, err)
+		}
+	}
+
 	tweetID, err := h.services.Tweets.CreateTweet(ctx, domain.CreateTweetRequest{
 		UserID: userID.(string),
 		Text:   text,
@@ -64,6 +112,50 @@ func (h *Handler) CreateTweet(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	if inReplyTo != "" {
+		err := h.services.Threads.AddReply(ctx, domain.ThreadLink{
+			TweetID:          tweetID,
+			InReplyToTweetID: inReplyTo,
+			ConversationID:   conversationID,
+			AuthorID:         userID.(string),
+		})
+
+		if err != nil {
+			h.log.Errorf("CreateTweet: %v", err.Error())
+
+			if err := h.services.Tweets.DeleteTweet(ctx, userID.(string), tweetID); err != nil {
+				h.log.Errorf("CreateTweet:GRPC: cannot rollback tweet %s: %v", tweetID, err.Error())
+			}
+
+			return response.WithError(c, err)
+		}
+	}
+
+	if poll != nil {
+		if err := h.services.Polls.CreatePoll(ctx, userID.(string), tweetID, *poll); err != nil {
+			h.log.Errorf("CreateTweet: %v", err.Error())
+
+			// tweet without requested poll is worse than no tweet at all
+			if err := h.services.Tweets.DeleteTweet(ctx, userID.(string), tweetID); err != nil {
+				h.log.Errorf("CreateTweet:GRPC: cannot rollback tweet %s: %v", tweetID, err.Error())
+			}
+
+			if inReplyTo != "" {
+				if err := h.services.Threads.DeleteLink(ctx, tweetID); err != nil {
+					h.log.Errorf("CreateTweet: %v", err.Error())
+				}
+			}
+
+			return response.WithError(c, err)
+		}
+	}
+
+	if mediaID != "" {
+		if err := h.services.Media.Attach(ctx, domain.TweetAttachment, tweetID, mediaID); err != nil {
+			h.log.Errorf("CreateTweet: %v", err.Error())
+		}
+	}
+
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"id": tweetID,
 	})
@@ -74,6 +166,7 @@ func (h *Handler) GetTweet(c *fiber.Ctx) error {
 	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetTweet")
 	defer span.End()
 
+	userID := c.Locals("userID")
 	tweetID := c.Params("id")
 
 	tweet, err := h.services.Tweets.GetTweet(ctx, tweetID)
@@ -84,6 +177,15 @@ func (h *Handler) GetTweet(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	if err := h.services.CheckAccess(ctx, userID.(string), tweet.UserID); err != nil {
+		h.log.Debugf("GetTweet: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	if err := h.services.HydrateTweet(ctx, userID.(string), &tweet); err != nil {
+		h.log.Errorf("GetTweet: %v", err.Error())
+	}
+
 	return c.Status(http.StatusOK).JSON(tweet)
 
 }
@@ -92,6 +194,7 @@ func (h *Handler) GetAllTweets(c *fiber.Ctx) error {
 	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetAllTweets")
 	defer span.End()
 
+	userID := c.Locals("userID")
 	cursor := c.Query("cursor")
 
 	tweets, cursor, err := h.services.Tweets.GetAllTweets(ctx, cursor)
@@ -101,6 +204,17 @@ func (h *Handler) GetAllTweets(c *fiber.Ctx) error {
 		return response.WithError(c, err)
 	}
 
+	tweets, err = h.services.FilterTweets(ctx, userID.(string), tweets)
+
+	if err != nil {
+		h.log.Errorf("GetAllTweets: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	if err := h.services.HydrateTweets(ctx, userID.(string), tweets); err != nil {
+		h.log.Errorf("GetAllTweets: %v", err.Error())
+	}
+
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"data":   tweets,
 		"cursor": cursor,
@@ -115,6 +229,23 @@ func (h *Handler) UpdateTweet(c *fiber.Ctx) error {
 	userID := c.Locals("userID")
 	tweetID := c.Params("id")
 
+	current, err := h.services.Tweets.GetTweet(ctx, tweetID)
+
+	if err != nil {
+		h.log.Errorf("UpdateTweet:GRPC: %v", err.Error())
+		st, _ := status.FromError(err)
+		return response.WithGRPCError(c, st.Code())
+	}
+
+	if current.UserID != userID.(string) {
+		return response.WithGRPCError(c, codes.PermissionDenied)
+	}
+
+	if err := h.services.HydrateTweet(ctx, userID.(string), &current); err != nil {
+		h.log.Errorf("UpdateTweet: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
 	text := c.FormValue("text")
 
 	imageInput, err := c.FormFile("image")
@@ -137,6 +268,31 @@ func (h *Handler) UpdateTweet(c *fiber.Ctx) error {
 
 	}
 
+	mediaID := c.FormValue("media_id")
+
+	if mediaID != "" {
+		image, err = h.services.Media.GetImage(ctx, userID.(string), mediaID)
+
+		if err != nil {
+			h.log.Debugf("UpdateTweet:HTTP: %v", err.Error())
+			return response.WithError(c, err)
+		}
+	} else if image != nil {
+		mediaID, err = h.services.Media.StoreImage(ctx, userID.(string), image)
+
+		if err != nil {
+			h.log.Errorf("UpdateTweet: %v", err.Error())
+			return response.WithError(c, err)
+		}
+	}
+
+	revision, err := h.services.Revisions.RecordRevision(ctx, current)
+
+	if err != nil {
+		h.log.Debugf("UpdateTweet: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
 	tweet, err := h.services.Tweets.UpdateTweet(ctx, domain.UpdateTweetRequest{
 		UserID:  userID.(string),
 		Text:    text,
@@ -146,14 +302,34 @@ func (h *Handler) UpdateTweet(c *fiber.Ctx) error {
 
 	if err != nil {
 		h.log.Errorf("UpdateTweet:GRPC: %v", err.Error())
+
+		if err := h.services.Revisions.DeleteRevision(ctx, revision); err != nil {
+			h.log.Errorf("UpdateTweet: %v", err.Error())
+		}
+
 		st, _ := status.FromError(err)
 		return response.WithGRPCError(c, st.Code())
 	}
 
-	return c.Status(http.StatusOK).JSON(domain.TweetResponse{
-		TweetID: tweet.TweetID,
-		Text:    tweet.Text,
-	})
+	if tweet.UserID == "" {
+		tweet.UserID = current.UserID
+	}
+
+	if tweet.CreatedAt.IsZero() {
+		tweet.CreatedAt = current.CreatedAt
+	}
+
+	if mediaID != "" {
+		if err := h.services.Media.Attach(ctx, domain.TweetAttachment, tweet.TweetID, mediaID); err != nil {
+			h.log.Errorf("UpdateTweet: %v", err.Error())
+		}
+	}
+
+	if err := h.services.HydrateTweet(ctx, userID.(string), &tweet); err != nil {
+		h.log.Errorf("UpdateTweet: %v", err.Error())
+	}
+
+	return c.Status(http.StatusOK).JSON(tweet)
 
 }
 
@@ -172,8 +348,57 @@ func (h *Handler) DeleteTweet(c *fiber.Ctx) error {
 		return response.WithGRPCError(c, st.Code())
 	}
 
+	if err := h.services.Media.Detach(ctx, domain.TweetAttachment, tweetID); err != nil {
+		h.log.Errorf("DeleteTweet: %v", err.Error())
+	}
+
+	if err := h.services.Polls.DeletePoll(ctx, tweetID); err != nil {
+		h.log.Errorf("DeleteTweet: %v", err.Error())
+	}
+
+	if err := h.services.Revisions.DeleteHistory(ctx, tweetID); err != nil {
+		h.log.Errorf("DeleteTweet: %v", err.Error())
+	}
+
+	if err := h.services.Threads.DeleteLink(ctx, tweetID); err != nil {
+		h.log.Errorf("DeleteTweet: %v", err.Error())
+	}
+
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 	})
 
 }
+
+func (h *Handler) GetTweetHistory(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetTweetHistory")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	tweetID := c.Params("id")
+
+	tweet, err := h.services.Tweets.GetTweet(ctx, tweetID)
+
+	if err != nil {
+		h.log.Errorf("GetTweetHistory:GRPC: %v", err.Error())
+		st, _ := status.FromError(err)
+		return response.WithGRPCError(c, st.Code())
+	}
+
+	if err := h.services.CheckAccess(ctx, userID.(string), tweet.UserID); err != nil {
+		h.log.Debugf("GetTweetHistory: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	revisions, err := h.services.Revisions.GetHistory(ctx, tweetID)
+
+	if err != nil {
+		h.log.Errorf("GetTweetHistory: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": revisions,
+	})
+
+}
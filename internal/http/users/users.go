@@ -0,0 +1,274 @@
+package users
+
+import (
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+type Handler struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	services  *service.Services
+	validator *validator.Validate
+}
+
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, services *service.Services, validator *validator.Validate) *Handler {
+	return &Handler{log: log, tracer: tracer, services: services, validator: validator}
+}
+
+func (h *Handler) BlockUser(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.BlockUser")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	targetID := c.Params("id")
+
+	if _, err := uuid.Parse(targetID); err != nil {
+		h.log.Debugf("BlockUser:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	if _, err := h.services.Auth.GetUserByID(ctx, targetID); err != nil {
+		h.log.Errorf("BlockUser:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrUserNotFound)
+	}
+
+	if err := h.services.Relations.Block(ctx, userID.(string), targetID); err != nil {
+		h.log.Errorf("BlockUser: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) UnblockUser(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.UnblockUser")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	targetID := c.Params("id")
+
+	if _, err := uuid.Parse(targetID); err != nil {
+		h.log.Debugf("UnblockUser:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	if err := h.services.Relations.Unblock(ctx, userID.(string), targetID); err != nil {
+		h.log.Errorf("UnblockUser: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) MuteUser(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.MuteUser")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	targetID := c.Params("id")
+
+	if _, err := uuid.Parse(targetID); err != nil {
+		h.log.Debugf("MuteUser:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	if _, err := h.services.Auth.GetUserByID(ctx, targetID); err != nil {
+		h.log.Errorf("MuteUser:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrUserNotFound)
+	}
+
+	if err := h.services.Relations.Mute(ctx, userID.(string), targetID); err != nil {
+		h.log.Errorf("MuteUser: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) UnmuteUser(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.UnmuteUser")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	targetID := c.Params("id")
+
+	if _, err := uuid.Parse(targetID); err != nil {
+		h.log.Debugf("UnmuteUser:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	if err := h.services.Relations.Unmute(ctx, userID.(string), targetID); err != nil {
+		h.log.Errorf("UnmuteUser: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) GetSettings(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetSettings")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	settings, err := h.services.Accounts.GetSettings(ctx, userID.(string))
+
+	if err != nil {
+		h.log.Errorf("GetSettings: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(settings)
+}
+
+func (h *Handler) UpdateSettings(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.UpdateSettings")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.UpdateAccountSettingsInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("UpdateSettings:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	settings, err := h.services.Accounts.UpdateSettings(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("UpdateSettings: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(settings)
+}
+
+func (h *Handler) RecommendUsers(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.RecommendUsers")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	limit := c.QueryInt("limit")
+
+	users, err := h.services.Recommendations.RecommendUsers(ctx, userID.(string), limit)
+
+	if err != nil {
+		h.log.Errorf("RecommendUsers: %v", err.Error())
+		if st, ok := status.FromError(err); ok {
+			return response.WithGRPCError(c, st.Code())
+		}
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": users,
+	})
+}
+
+func (h *Handler) GetPresence(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetPresence")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	targetID := c.Params("id")
+
+	if _, err := uuid.Parse(targetID); err != nil {
+		h.log.Debugf("GetPresence:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	if err := h.services.CheckAccess(ctx, userID.(string), targetID); err != nil {
+		h.log.Debugf("GetPresence: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	presence, err := h.services.Presence.GetPresence(ctx, targetID)
+
+	if err != nil {
+		h.log.Errorf("GetPresence: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(presence)
+}
+
+func (h *Handler) CreateAPIKey(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.CreateAPIKey")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.CreateAPIKeyInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("CreateAPIKey:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	key, err := h.services.APIKeys.CreateAPIKey(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("CreateAPIKey: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(key)
+}
+
+func (h *Handler) GetAPIKeys(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetAPIKeys")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	keys, err := h.services.APIKeys.GetAPIKeys(ctx, userID.(string))
+
+	if err != nil {
+		h.log.Errorf("GetAPIKeys: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": keys,
+	})
+}
+
+func (h *Handler) RevokeAPIKey(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.RevokeAPIKey")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	keyID := c.Params("id")
+
+	if _, err := uuid.Parse(keyID); err != nil {
+		h.log.Debugf("RevokeAPIKey:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	if err := h.services.APIKeys.RevokeAPIKey(ctx, userID.(string), keyID); err != nil {
+		h.log.Errorf("RevokeAPIKey: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
@@ -0,0 +1,185 @@
+package webhooks
+
+import (
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Handler struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	services  *service.Services
+	validator *validator.Validate
+}
+
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, services *service.Services, validator *validator.Validate) *Handler {
+	return &Handler{log: log, tracer: tracer, services: services, validator: validator}
+}
+
+func (h *Handler) CreateWebhook(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.CreateWebhook")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	var input domain.CreateWebhookInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("CreateWebhook:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	webhook, err := h.services.Webhooks.CreateWebhook(ctx, userID.(string), input)
+
+	if err != nil {
+		h.log.Errorf("CreateWebhook: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(webhook)
+}
+
+func (h *Handler) GetWebhooks(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetWebhooks")
+	defer span.End()
+
+	userID := c.Locals("userID")
+
+	webhooks, err := h.services.Webhooks.GetWebhooks(ctx, userID.(string))
+
+	if err != nil {
+		h.log.Errorf("GetWebhooks: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": webhooks,
+	})
+}
+
+func (h *Handler) GetWebhook(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetWebhook")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	webhookID := c.Params("id")
+
+	if _, err := uuid.Parse(webhookID); err != nil {
+		h.log.Debugf("GetWebhook:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	webhook, err := h.services.Webhooks.GetWebhook(ctx, userID.(string), webhookID)
+
+	if err != nil {
+		h.log.Errorf("GetWebhook: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(webhook)
+}
+
+func (h *Handler) UpdateWebhook(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.UpdateWebhook")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	webhookID := c.Params("id")
+
+	if _, err := uuid.Parse(webhookID); err != nil {
+		h.log.Debugf("UpdateWebhook:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	var input domain.UpdateWebhookInput
+
+	if err := response.ReadRequest(c, h.validator, &input); err != nil {
+		h.log.Debugf("UpdateWebhook:HTTP: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	webhook, err := h.services.Webhooks.UpdateWebhook(ctx, userID.(string), webhookID, input)
+
+	if err != nil {
+		h.log.Errorf("UpdateWebhook: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(webhook)
+}
+
+func (h *Handler) DeleteWebhook(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.DeleteWebhook")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	webhookID := c.Params("id")
+
+	if _, err := uuid.Parse(webhookID); err != nil {
+		h.log.Debugf("DeleteWebhook:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	if err := h.services.Webhooks.DeleteWebhook(ctx, userID.(string), webhookID); err != nil {
+		h.log.Errorf("DeleteWebhook: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *Handler) GetDeliveries(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.GetDeliveries")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	webhookID := c.Params("id")
+
+	if _, err := uuid.Parse(webhookID); err != nil {
+		h.log.Debugf("GetDeliveries:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	deliveries, cursor, err := h.services.Webhooks.GetDeliveries(ctx, userID.(string), webhookID, c.Query("cursor"), c.QueryInt("limit"))
+
+	if err != nil {
+		h.log.Errorf("GetDeliveries: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data":   deliveries,
+		"cursor": cursor,
+	})
+}
+
+func (h *Handler) SendTestEvent(c *fiber.Ctx) error {
+	ctx, span := h.tracer.Start(c.UserContext(), "Gateway.SendTestEvent")
+	defer span.End()
+
+	userID := c.Locals("userID")
+	webhookID := c.Params("id")
+
+	if _, err := uuid.Parse(webhookID); err != nil {
+		h.log.Debugf("SendTestEvent:HTTP: %v", err.Error())
+		return response.WithError(c, response.ErrInvalidRequest)
+	}
+
+	delivery, err := h.services.Webhooks.SendTestEvent(ctx, userID.(string), webhookID)
+
+	if err != nil {
+		h.log.Errorf("SendTestEvent: %v", err.Error())
+		return response.WithError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(delivery)
+}
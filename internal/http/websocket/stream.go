@@ -0,0 +1,93 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/realtime"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"time"
+)
+
+// Stream serves the same events as websocket as text/event-stream for clients behind proxies
+// that break upgrades, Last-Event-ID header or since query resumes after given seq
+func (h *Handler) Stream(c *fiber.Ctx) error {
+	_, span := h.tracer.Start(c.UserContext(), "Gateway.Stream")
+	defer span.End()
+
+	userID := c.Locals("userID").(string)
+
+	since := c.Get("Last-Event-ID", c.Query("since"))
+
+	var seq any
+
+	if since != "" {
+		parsed, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			h.log.Debugf("Stream:HTTP: %v", err.Error())
+			return response.WithError(c, response.ErrInvalidRequest)
+		}
+		seq = parsed
+	}
+
+	sub, missed := h.subscribe(userID, seq)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.hub.Unsubscribe(sub)
+
+		ticker := time.NewTicker(h.cfg.StreamHeartbeat)
+		defer ticker.Stop()
+
+		for _, event := range missed {
+			if err := h.writeEvent(w, event); err != nil {
+				return
+			}
+		}
+
+		// tells client that stream is open even if there is nothing to replay
+		if _, err := fmt.Fprint(w, ": connected\n\n"); err != nil {
+			return
+		}
+
+		for {
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+
+				if err := h.writeEvent(w, event); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+func (h *Handler) writeEvent(w *bufio.Writer, event realtime.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		h.log.Errorf("cannot marshal stream event: %v", err)
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+	return err
+}
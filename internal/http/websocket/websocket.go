@@ -1,38 +1,109 @@
 package websocket
 
 import (
+	"context"
+	"encoding/json"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/realtime"
 	"github.com/Verce11o/yata/internal/service"
 	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"sync"
+	"strconv"
+	"time"
 )
 
-type WsClients map[string]*websocket.Conn
+// messages clients send over websocket
+const (
+	messagePing     = "ping"
+	messagePresence = "presence"
+	messageJoin     = "join"
+	messageLeave    = "leave"
+	messageTyping   = "typing"
+)
+
+type clientMessage struct {
+	Type    string `json:"type"`
+	TweetID string `json:"tweet_id"`
+}
 
 type Handler struct {
 	log      *zap.SugaredLogger
 	tracer   trace.Tracer
 	services *service.Services
-	Clients  WsClients
-	mu       sync.Mutex
+	hub      *realtime.Hub
+	cfg      config.Notifications
+	presence config.Presence
 }
 
-func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, services *service.Services) *Handler {
-	return &Handler{log: log, tracer: tracer, services: services, Clients: make(WsClients)}
+func NewHandler(log *zap.SugaredLogger, tracer trace.Tracer, services *service.Services, hub *realtime.Hub, cfg config.Notifications, presence config.Presence) *Handler {
+	return &Handler{log: log, tracer: tracer, services: services, hub: hub, cfg: cfg, presence: presence}
+}
+
+// Upgrade rejects plain HTTP requests to websocket routes
+func (h *Handler) Upgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+
+	if since := c.Query("since"); since != "" {
+		seq, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			h.log.Debugf("Upgrade:HTTP: %v", err.Error())
+			return response.WithError(c, response.ErrInvalidRequest)
+		}
+		c.Locals("since", seq)
+	}
+
+	return c.Next()
+}
+
+// subscribe replays events after since when client resumes, otherwise only live events are sent
+func (h *Handler) subscribe(userID string, since any) (*realtime.Subscription, []realtime.Event) {
+	seq, ok := since.(uint64)
+	if !ok {
+		return h.hub.Subscribe(userID), nil
+	}
+
+	return h.hub.SubscribeSince(userID, seq)
 }
 
 func (h *Handler) EstablishConnection(c *websocket.Conn) {
-	userID := c.Locals("userID")
+	userID := c.Locals("userID").(string)
+	sessionID := uuid.NewString()
+
+	sub, missed := h.subscribe(userID, c.Locals("since"))
+	written := make(chan struct{})
+
+	// only this goroutine writes to connection
+	go func() {
+		defer close(written)
+
+		for _, event := range missed {
+			if err := c.WriteJSON(event); err != nil {
+				h.log.Errorf("error sending ws message: %v", err)
+			}
+		}
 
-	h.mu.Lock()
-	h.Clients[userID.(string)] = c
-	h.mu.Unlock()
+		for event := range sub.Events() {
+			if err := c.WriteJSON(event); err != nil {
+				h.log.Errorf("error sending ws message: %v", err)
+			}
+		}
+	}()
+
+	// presence is best effort, connection works without it
+	_ = h.services.Presence.Heartbeat(context.Background(), sessionID, userID)
 
 	defer func() {
-		h.mu.Lock()
-		delete(h.Clients, userID.(string))
-		h.mu.Unlock()
+		h.hub.Unsubscribe(sub)
+		<-written
+
+		_ = h.services.Presence.Disconnect(context.Background(), sessionID, userID)
 
 		err := c.Close()
 		if err != nil {
@@ -41,5 +112,80 @@ func (h *Handler) EstablishConnection(c *websocket.Conn) {
 
 	}()
 
-	select {}
+	for {
+		// clients that stay silent longer than presence ttl are gone
+		if err := c.SetReadDeadline(time.Now().Add(h.presence.TTL)); err != nil {
+			return
+		}
+
+		_, data, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var message clientMessage
+
+		if err := json.Unmarshal(data, &message); err != nil {
+			h.sendError(sub, response.ErrInvalidRequest)
+			continue
+		}
+
+		h.handleMessage(sub, sessionID, userID, message)
+	}
+}
+
+func (h *Handler) handleMessage(sub *realtime.Subscription, sessionID, userID string, message clientMessage) {
+	ctx, span := h.tracer.Start(context.Background(), "Gateway.HandleMessage")
+	defer span.End()
+
+	switch message.Type {
+	case messagePing:
+		h.hub.Send(sub, realtime.Event{Type: realtime.EventPong})
+	case messagePresence:
+		_ = h.services.Presence.Heartbeat(ctx, sessionID, userID)
+	case messageJoin:
+		if err := h.checkThread(ctx, userID, message.TweetID); err != nil {
+			h.sendError(sub, err)
+			return
+		}
+		h.hub.Join(sub, realtime.ThreadTopic(message.TweetID))
+	case messageLeave:
+		h.hub.Leave(sub, realtime.ThreadTopic(message.TweetID))
+	case messageTyping:
+		topic := realtime.ThreadTopic(message.TweetID)
+
+		// typing is only relayed within thread connection has joined, so access is checked once
+		if !h.hub.Joined(sub, topic) {
+			h.sendError(sub, response.ErrInvalidRequest)
+			return
+		}
+
+		h.hub.Broadcast(topic, realtime.Event{
+			Type: realtime.EventTyping,
+			Data: domain.Typing{TweetID: message.TweetID, UserID: userID},
+		}, sub)
+	default:
+		h.sendError(sub, response.ErrInvalidRequest)
+	}
+}
+
+func (h *Handler) checkThread(ctx context.Context, userID, tweetID string) error {
+	if _, err := uuid.Parse(tweetID); err != nil {
+		return response.ErrInvalidRequest
+	}
+
+	tweet, err := h.services.Tweets.GetTweet(ctx, tweetID)
+	if err != nil {
+		h.log.Debugf("HandleMessage:GRPC: %v", err.Error())
+		return response.ErrInvalidRequest
+	}
+
+	return h.services.CheckAccess(ctx, userID, tweet.UserID)
+}
+
+func (h *Handler) sendError(sub *realtime.Subscription, err error) {
+	h.hub.Send(sub, realtime.Event{
+		Type: realtime.EventError,
+		Data: fiber.Map{"message": err.Error()},
+	})
 }
@@ -0,0 +1,42 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Encode makes opaque cursor from the last item of the page
+func Encode(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "," + id))
+}
+
+func Decode(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	return parsed, id, nil
+}
+
+// Before reports whether item goes after the cursor in newest first order
+func Before(createdAt time.Time, id string, cursorTime time.Time, cursorID string) bool {
+	if createdAt.Equal(cursorTime) {
+		return id < cursorID
+	}
+	return createdAt.Before(cursorTime)
+}
@@ -33,6 +33,29 @@ func PrepareImage(image *multipart.FileHeader) (string, []byte, string, error) {
 	return contentType, bytes, generateImageName(getExtension(contentType)), nil
 }
 
+// DetectMediaType sniffs content type of uploaded media by its first bytes
+func DetectMediaType(header []byte) (string, error) {
+	contentType := http.DetectContentType(header)
+
+	if !IsAllowedMime(contentType) {
+		return "", response.ErrInvalidImage
+	}
+
+	return contentType, nil
+}
+
+func IsAllowedMime(contentType string) bool {
+	return checkImageMime(contentType) || checkVideoMime(contentType)
+}
+
+func IsVideo(contentType string) bool {
+	return checkVideoMime(contentType)
+}
+
+func MediaName(mediaID, contentType string) string {
+	return fmt.Sprintf("%v.%v", mediaID, getExtension(contentType))
+}
+
 func checkImageMime(imageMime string) bool {
 	var imageMimeTypes = map[string]struct{}{
 		"image/gif":  {},
@@ -45,6 +68,17 @@ func checkImageMime(imageMime string) bool {
 	return ok
 }
 
+// videos are not accepted by PrepareImage, they can be attached only by media upload
+func checkVideoMime(videoMime string) bool {
+	var videoMimeTypes = map[string]struct{}{
+		"video/mp4":  {},
+		"video/webm": {},
+	}
+
+	_, ok := videoMimeTypes[videoMime]
+	return ok
+}
+
 func getExtension(contentType string) string {
 	return strings.Split(contentType, "/")[1]
 }
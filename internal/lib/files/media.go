@@ -0,0 +1,264 @@
+package files
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+)
+
+const placeholderWidth = 640
+
+type Metadata struct {
+	Type       domain.MediaType
+	DurationMs int64
+	Width      int
+	Height     int
+	Codec      string
+	// Thumbnail is png poster for animated media
+	Thumbnail []byte
+}
+
+// Limits bound resources spent on decoding media
+type Limits struct {
+	// MaxPixels is the largest canvas accepted for decoded images
+	MaxPixels int64
+	MaxFrames int
+	// MaxAnimationPixels bounds sum of all frame areas, since every gif frame is kept in memory
+	MaxAnimationPixels int64
+}
+
+// ProbeMedia reads dimensions, duration and codec of stored media
+func ProbeMedia(r io.ReaderAt, size int64, contentType string, limits Limits) (Metadata, error) {
+	if checkVideoMime(contentType) {
+		video, err := ProbeVideo(r, size, contentType)
+		if err != nil {
+			return Metadata{}, response.ErrInvalidVideo
+		}
+
+		thumbnail, err := videoPlaceholder(video.Width, video.Height)
+		if err != nil {
+			return Metadata{}, err
+		}
+
+		return Metadata{
+			Type:       domain.MediaTypeVideo,
+			DurationMs: video.DurationMs,
+			Width:      video.Width,
+			Height:     video.Height,
+			Codec:      video.Codec,
+			Thumbnail:  thumbnail,
+		}, nil
+	}
+
+	if contentType == "image/gif" {
+		return probeGIF(io.NewSectionReader(r, 0, size), limits)
+	}
+
+	meta := Metadata{Type: domain.MediaTypeImage}
+
+	// webp is not registered in image package, so its dimensions stay unknown
+	config, _, err := image.DecodeConfig(io.NewSectionReader(r, 0, size))
+	if err == nil {
+		meta.Width, meta.Height = config.Width, config.Height
+	}
+
+	return meta, nil
+}
+
+func probeGIF(r io.ReadSeeker, limits Limits) (Metadata, error) {
+	// header is checked before decoding, since DecodeAll allocates every frame
+	config, err := gif.DecodeConfig(r)
+	if err != nil {
+		return Metadata{}, response.ErrInvalidImage
+	}
+
+	if int64(config.Width)*int64(config.Height) > limits.MaxPixels {
+		return Metadata{}, response.ErrMediaTooLarge
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return Metadata{}, err
+	}
+
+	if err := checkGIFFrames(bufio.NewReader(r), limits); err != nil {
+		return Metadata{}, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return Metadata{}, err
+	}
+
+	animation, err := gif.DecodeAll(r)
+	if err != nil || len(animation.Image) == 0 {
+		return Metadata{}, response.ErrInvalidImage
+	}
+
+	meta := Metadata{
+		Type:   domain.MediaTypeImage,
+		Width:  animation.Config.Width,
+		Height: animation.Config.Height,
+	}
+
+	if len(animation.Image) == 1 {
+		return meta, nil
+	}
+
+	meta.Type = domain.MediaTypeGIF
+
+	for _, delay := range animation.Delay {
+		// delay is measured in 100ths of a second
+		meta.DurationMs += int64(delay) * 10
+	}
+
+	// first frame can be smaller than the canvas
+	poster := image.NewRGBA(image.Rect(0, 0, meta.Width, meta.Height))
+	draw.Draw(poster, animation.Image[0].Bounds(), animation.Image[0], animation.Image[0].Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, poster); err != nil {
+		return Metadata{}, err
+	}
+
+	meta.Thumbnail = buf.Bytes()
+
+	return meta, nil
+}
+
+// checkGIFFrames walks gif blocks without decompressing them and fails when frames exceed the limits
+func checkGIFFrames(r *bufio.Reader, limits Limits) error {
+	// header and logical screen descriptor
+	header := make([]byte, 13)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return response.ErrInvalidImage
+	}
+
+	if err := skipColorTable(r, header[10]); err != nil {
+		return err
+	}
+
+	var (
+		frames int
+		pixels int64
+	)
+
+	for {
+		separator, err := r.ReadByte()
+		if err != nil {
+			return response.ErrInvalidImage
+		}
+
+		switch separator {
+		case 0x21: // extension: label and data sub-blocks
+			if _, err := r.ReadByte(); err != nil {
+				return response.ErrInvalidImage
+			}
+
+			if err := skipSubBlocks(r); err != nil {
+				return err
+			}
+		case 0x2c: // image descriptor
+			descriptor := make([]byte, 9)
+			if _, err := io.ReadFull(r, descriptor); err != nil {
+				return response.ErrInvalidImage
+			}
+
+			// descriptor holds left, top, width and height as little endian uint16
+			width := int64(descriptor[4]) | int64(descriptor[5])<<8
+			height := int64(descriptor[6]) | int64(descriptor[7])<<8
+
+			frames++
+			pixels += width * height
+			if frames > limits.MaxFrames || pixels > limits.MaxAnimationPixels {
+				return response.ErrMediaTooLarge
+			}
+
+			if err := skipColorTable(r, descriptor[8]); err != nil {
+				return err
+			}
+
+			// lzw minimum code size precedes image data
+			if _, err := r.ReadByte(); err != nil {
+				return response.ErrInvalidImage
+			}
+
+			if err := skipSubBlocks(r); err != nil {
+				return err
+			}
+		case 0x3b: // trailer
+			return nil
+		default:
+			return response.ErrInvalidImage
+		}
+	}
+}
+
+// skipColorTable skips color table announced by packed fields of screen or image descriptor
+func skipColorTable(r *bufio.Reader, packed byte) error {
+	if packed&0x80 == 0 {
+		return nil
+	}
+
+	if _, err := r.Discard(3 << ((packed & 0x07) + 1)); err != nil {
+		return response.ErrInvalidImage
+	}
+
+	return nil
+}
+
+func skipSubBlocks(r *bufio.Reader) error {
+	for {
+		size, err := r.ReadByte()
+		if err != nil {
+			return response.ErrInvalidImage
+		}
+
+		if size == 0 {
+			return nil
+		}
+
+		if _, err := r.Discard(int(size)); err != nil {
+			return response.ErrInvalidImage
+		}
+	}
+}
+
+// videoPlaceholder draws play button of the video aspect ratio, since we don't decode video frames
+func videoPlaceholder(width, height int) ([]byte, error) {
+	placeholderHeight := placeholderWidth * 9 / 16
+	if width > 0 && height > 0 {
+		placeholderHeight = max(placeholderWidth*height/width, 1)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, placeholderWidth, placeholderHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{R: 32, G: 32, B: 32, A: 255}}, image.Point{}, draw.Src)
+
+	side := min(placeholderWidth, placeholderHeight) / 4
+	centerX, centerY := placeholderWidth/2, placeholderHeight/2
+	left := centerX - side/3
+
+	for y := centerY - side/2; y <= centerY+side/2; y++ {
+		// triangle pointing right: it narrows towards the top and bottom edges
+		halfHeight := side / 2
+		distance := max(y-centerY, centerY-y)
+		right := left + side*(halfHeight-distance)/max(halfHeight, 1)
+
+		for x := left; x <= right; x++ {
+			img.Set(x, y, color.RGBA{R: 224, G: 224, B: 224, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,522 @@
+package files
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+var errInvalidContainer = errors.New("invalid media container")
+
+// VideoMetadata contains what clients need to render a video before it is loaded
+type VideoMetadata struct {
+	DurationMs int64
+	Width      int
+	Height     int
+	Codec      string
+}
+
+// ProbeVideo parses container headers of supported video formats
+func ProbeVideo(r io.ReaderAt, size int64, contentType string) (VideoMetadata, error) {
+	switch contentType {
+	case "video/mp4":
+		return probeMP4(r, size)
+	case "video/webm":
+		return probeWebM(r, size)
+	}
+	return VideoMetadata{}, errInvalidContainer
+}
+
+// MP4 (ISO BMFF) boxes we are interested in: moov/mvhd for duration and
+// moov/trak/{tkhd,mdia/hdlr,mdia/minf/stbl/stsd} for the video track
+
+type mp4Box struct {
+	kind  string
+	start int64 // payload start
+	end   int64
+}
+
+func readMP4Boxes(r io.ReaderAt, start, end int64) ([]mp4Box, error) {
+	var boxes []mp4Box
+
+	header := make([]byte, 16)
+
+	for offset := start; offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return nil, errInvalidContainer
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		kind := string(header[4:8])
+		headerSize := int64(8)
+
+		switch size {
+		case 0:
+			// box extends to the end of the file
+			size = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return nil, errInvalidContainer
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+
+		if size < headerSize || offset+size > end {
+			return nil, errInvalidContainer
+		}
+
+		boxes = append(boxes, mp4Box{kind: kind, start: offset + headerSize, end: offset + size})
+		offset += size
+	}
+
+	return boxes, nil
+}
+
+func findMP4Box(r io.ReaderAt, parent mp4Box, path ...string) (mp4Box, bool) {
+	current := parent
+
+	for _, kind := range path {
+		boxes, err := readMP4Boxes(r, current.start, current.end)
+		if err != nil {
+			return mp4Box{}, false
+		}
+
+		found := false
+		for _, box := range boxes {
+			if box.kind == kind {
+				current, found = box, true
+				break
+			}
+		}
+
+		if !found {
+			return mp4Box{}, false
+		}
+	}
+
+	return current, true
+}
+
+func readBoxPayload(r io.ReaderAt, box mp4Box, limit int64) ([]byte, error) {
+	payload := make([]byte, min(box.end-box.start, limit))
+	if _, err := r.ReadAt(payload, box.start); err != nil {
+		return nil, errInvalidContainer
+	}
+	return payload, nil
+}
+
+func probeMP4(r io.ReaderAt, size int64) (VideoMetadata, error) {
+	var meta VideoMetadata
+
+	root := mp4Box{kind: "root", start: 0, end: size}
+
+	moov, ok := findMP4Box(r, root, "moov")
+	if !ok {
+		return meta, errInvalidContainer
+	}
+
+	mvhd, ok := findMP4Box(r, moov, "mvhd")
+	if !ok {
+		return meta, errInvalidContainer
+	}
+
+	payload, err := readBoxPayload(r, mvhd, 32)
+	if err != nil {
+		return meta, err
+	}
+
+	var timescale, duration uint64
+
+	switch {
+	case len(payload) >= 32 && payload[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(payload[20:24]))
+		duration = binary.BigEndian.Uint64(payload[24:32])
+	case len(payload) >= 20:
+		timescale = uint64(binary.BigEndian.Uint32(payload[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(payload[16:20]))
+	default:
+		return meta, errInvalidContainer
+	}
+
+	// fragmented files leave mvhd duration empty and keep the whole length in mvex/mehd
+	if !knownMP4Duration(payload[0], duration) {
+		if duration, ok = readMP4FragmentDuration(r, moov); !ok {
+			return meta, errInvalidContainer
+		}
+	}
+
+	if meta.DurationMs, ok = durationMs(duration, timescale); !ok {
+		return meta, errInvalidContainer
+	}
+
+	boxes, err := readMP4Boxes(r, moov.start, moov.end)
+	if err != nil {
+		return meta, err
+	}
+
+	for _, trak := range boxes {
+		if trak.kind != "trak" {
+			continue
+		}
+
+		hdlr, ok := findMP4Box(r, trak, "mdia", "hdlr")
+		if !ok {
+			continue
+		}
+
+		payload, err := readBoxPayload(r, hdlr, 12)
+		if err != nil || len(payload) < 12 || string(payload[8:12]) != "vide" {
+			continue
+		}
+
+		if tkhd, ok := findMP4Box(r, trak, "tkhd"); ok {
+			payload, err := readBoxPayload(r, tkhd, 92)
+			if err == nil && len(payload) > 0 {
+				// width and height are the last two 16.16 fixed point numbers of the box
+				offset := 76
+				if payload[0] == 1 {
+					offset = 88
+				}
+				if len(payload) >= offset+8 {
+					meta.Width = int(binary.BigEndian.Uint32(payload[offset:offset+4]) >> 16)
+					meta.Height = int(binary.BigEndian.Uint32(payload[offset+4:offset+8]) >> 16)
+				}
+			}
+		}
+
+		if stsd, ok := findMP4Box(r, trak, "mdia", "minf", "stbl", "stsd"); ok {
+			payload, err := readBoxPayload(r, stsd, 16)
+			if err == nil && len(payload) >= 16 {
+				meta.Codec = string(payload[12:16])
+			}
+		}
+
+		return meta, nil
+	}
+
+	// container without video track
+	return meta, errInvalidContainer
+}
+
+func readMP4FragmentDuration(r io.ReaderAt, moov mp4Box) (uint64, bool) {
+	mehd, ok := findMP4Box(r, moov, "mvex", "mehd")
+	if !ok {
+		return 0, false
+	}
+
+	payload, err := readBoxPayload(r, mehd, 12)
+	if err != nil {
+		return 0, false
+	}
+
+	var duration uint64
+
+	switch {
+	case len(payload) >= 12 && payload[0] == 1:
+		duration = binary.BigEndian.Uint64(payload[4:12])
+	case len(payload) >= 8:
+		duration = uint64(binary.BigEndian.Uint32(payload[4:8]))
+	default:
+		return 0, false
+	}
+
+	return duration, knownMP4Duration(payload[0], duration)
+}
+
+// knownMP4Duration reports whether duration is set, all ones of the field size mean unknown
+func knownMP4Duration(version byte, duration uint64) bool {
+	if version == 1 {
+		return duration != 0 && duration != math.MaxUint64
+	}
+	return duration != 0 && duration != math.MaxUint32
+}
+
+// durationMs converts duration in timescale units without overflowing, unknown or empty durations are invalid
+func durationMs(duration, timescale uint64) (int64, bool) {
+	if timescale == 0 || duration == 0 {
+		return 0, false
+	}
+
+	seconds := duration / timescale
+	if seconds > math.MaxInt64/1000-1 {
+		return 0, false
+	}
+
+	ms := int64(seconds*1000 + duration%timescale*1000/timescale)
+	if ms <= 0 {
+		return 0, false
+	}
+
+	return ms, true
+}
+
+// WebM (Matroska) elements we are interested in
+const (
+	ebmlHeaderID    = 0x1A45DFA3
+	segmentID       = 0x18538067
+	infoID          = 0x1549A966
+	timecodeScaleID = 0x2AD7B1
+	durationID      = 0x4489
+	tracksID        = 0x1654AE6B
+	trackEntryID    = 0xAE
+	trackTypeID     = 0x83
+	codecID         = 0x86
+	videoID         = 0xE0
+	pixelWidthID    = 0xB0
+	pixelHeightID   = 0xBA
+
+	videoTrackType = 1
+)
+
+type ebmlElement struct {
+	id    uint64
+	start int64 // payload start
+	end   int64
+}
+
+// readVint reads EBML variable size integer. Element IDs keep their length marker
+func readVint(r io.ReaderAt, offset int64, keepMarker bool) (uint64, int64, bool, error) {
+	first := make([]byte, 1)
+	if _, err := r.ReadAt(first, offset); err != nil {
+		return 0, 0, false, errInvalidContainer
+	}
+
+	length := int64(1)
+	for mask := byte(0x80); mask != 0 && first[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+
+	if length > 8 {
+		return 0, 0, false, errInvalidContainer
+	}
+
+	data := make([]byte, length)
+	if _, err := r.ReadAt(data, offset); err != nil {
+		return 0, 0, false, errInvalidContainer
+	}
+
+	if !keepMarker {
+		data[0] &= byte(0xFF >> length)
+	}
+
+	var value uint64
+	unknown := true
+
+	for i, b := range data {
+		value = value<<8 | uint64(b)
+		if (i == 0 && b != byte(0xFF>>length)) || (i > 0 && b != 0xFF) {
+			unknown = false
+		}
+	}
+
+	return value, length, unknown, nil
+}
+
+func readEBMLElements(r io.ReaderAt, start, end int64) ([]ebmlElement, error) {
+	var elements []ebmlElement
+
+	for offset := start; offset < end; {
+		id, idLength, _, err := readVint(r, offset, true)
+		if err != nil {
+			return nil, err
+		}
+
+		size, sizeLength, unknown, err := readVint(r, offset+idLength, false)
+		if err != nil {
+			return nil, err
+		}
+
+		payloadStart := offset + idLength + sizeLength
+		payloadEnd := payloadStart + int64(size)
+
+		if unknown || payloadEnd > end {
+			// live streams have segments of unknown size
+			payloadEnd = end
+		}
+
+		elements = append(elements, ebmlElement{id: id, start: payloadStart, end: payloadEnd})
+		offset = payloadEnd
+	}
+
+	return elements, nil
+}
+
+func readEBMLPayload(r io.ReaderAt, element ebmlElement) ([]byte, error) {
+	if element.end-element.start > 1024 {
+		return nil, errInvalidContainer
+	}
+
+	payload := make([]byte, element.end-element.start)
+	if _, err := r.ReadAt(payload, element.start); err != nil {
+		return nil, errInvalidContainer
+	}
+	return payload, nil
+}
+
+func readEBMLUint(r io.ReaderAt, element ebmlElement) (uint64, error) {
+	payload, err := readEBMLPayload(r, element)
+	if err != nil || len(payload) > 8 {
+		return 0, errInvalidContainer
+	}
+
+	var value uint64
+	for _, b := range payload {
+		value = value<<8 | uint64(b)
+	}
+	return value, nil
+}
+
+func readEBMLFloat(r io.ReaderAt, element ebmlElement) (float64, error) {
+	payload, err := readEBMLPayload(r, element)
+	if err != nil {
+		return 0, err
+	}
+
+	switch len(payload) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(payload))), nil
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(payload)), nil
+	}
+	return 0, errInvalidContainer
+}
+
+func probeWebM(r io.ReaderAt, size int64) (VideoMetadata, error) {
+	var meta VideoMetadata
+
+	top, err := readEBMLElements(r, 0, size)
+	if err != nil || len(top) == 0 || top[0].id != ebmlHeaderID {
+		return meta, errInvalidContainer
+	}
+
+	var segment *ebmlElement
+	for i := range top {
+		if top[i].id == segmentID {
+			segment = &top[i]
+			break
+		}
+	}
+
+	if segment == nil {
+		return meta, errInvalidContainer
+	}
+
+	timecodeScale := uint64(1000000)
+	var duration float64
+	foundVideo := false
+
+	// only headers of segment children are read, so clusters are skipped without parsing
+	children, err := readEBMLElements(r, segment.start, segment.end)
+	if err != nil {
+		return meta, err
+	}
+
+	for _, element := range children {
+		switch element.id {
+		case infoID:
+			fields, err := readEBMLElements(r, element.start, element.end)
+			if err != nil {
+				return meta, err
+			}
+			for _, field := range fields {
+				switch field.id {
+				case timecodeScaleID:
+					if timecodeScale, err = readEBMLUint(r, field); err != nil {
+						return meta, err
+					}
+				case durationID:
+					if duration, err = readEBMLFloat(r, field); err != nil {
+						return meta, err
+					}
+				}
+			}
+		case tracksID:
+			entries, err := readEBMLElements(r, element.start, element.end)
+			if err != nil {
+				return meta, err
+			}
+			for _, entry := range entries {
+				if entry.id != trackEntryID || foundVideo {
+					continue
+				}
+				if meta, foundVideo, err = readWebMTrack(r, entry, meta); err != nil {
+					return meta, err
+				}
+			}
+		}
+
+		if duration > 0 && foundVideo {
+			break
+		}
+	}
+
+	if !foundVideo {
+		return meta, errInvalidContainer
+	}
+
+	// duration is measured in timecode scale units (nanoseconds), missing duration can't be checked against limits
+	ms := duration * float64(timecodeScale) / float64(time.Millisecond)
+	if math.IsNaN(ms) || ms < 1 || ms >= math.MaxInt64 {
+		return meta, errInvalidContainer
+	}
+
+	meta.DurationMs = int64(ms)
+
+	return meta, nil
+}
+
+func readWebMTrack(r io.ReaderAt, entry ebmlElement, meta VideoMetadata) (VideoMetadata, bool, error) {
+	fields, err := readEBMLElements(r, entry.start, entry.end)
+	if err != nil {
+		return meta, false, err
+	}
+
+	var trackType uint64
+	var codec string
+	var width, height uint64
+
+	for _, field := range fields {
+		switch field.id {
+		case trackTypeID:
+			if trackType, err = readEBMLUint(r, field); err != nil {
+				return meta, false, err
+			}
+		case codecID:
+			payload, err := readEBMLPayload(r, field)
+			if err != nil {
+				return meta, false, err
+			}
+			codec = string(payload)
+		case videoID:
+			settings, err := readEBMLElements(r, field.start, field.end)
+			if err != nil {
+				return meta, false, err
+			}
+			for _, setting := range settings {
+				switch setting.id {
+				case pixelWidthID:
+					if width, err = readEBMLUint(r, setting); err != nil {
+						return meta, false, err
+					}
+				case pixelHeightID:
+					if height, err = readEBMLUint(r, setting); err != nil {
+						return meta, false, err
+					}
+				}
+			}
+		}
+	}
+
+	if trackType != videoTrackType {
+		return meta, false, nil
+	}
+
+	meta.Codec = codec
+	meta.Width = int(width)
+	meta.Height = int(height)
+
+	return meta, true, nil
+}
@@ -2,17 +2,69 @@ package response
 
 import (
 	"errors"
+	"github.com/Verce11o/yata/internal/lib/cursor"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"net/http"
 )
 
 var (
-	ErrInvalidRequest   = errors.New("invalid request")
-	ErrUserNotFound     = errors.New("user not found")
-	ErrInvalidImage     = errors.New("invalid image")
-	ErrInvalidCode      = errors.New("invalid code or expired")
-	ErrPasswordMismatch = errors.New("password mismatch")
+	ErrInvalidRequest         = errors.New("invalid request")
+	ErrUserNotFound           = errors.New("user not found")
+	ErrInvalidImage           = errors.New("invalid image")
+	ErrInvalidVideo           = errors.New("invalid video")
+	ErrMediaTooLong           = errors.New("media is too long")
+	ErrInvalidCode            = errors.New("invalid code or expired")
+	ErrPasswordMismatch       = errors.New("password mismatch")
+	ErrUploadNotFound         = errors.New("upload not found or expired")
+	ErrInvalidOffset          = errors.New("invalid upload offset")
+	ErrUploadIncomplete       = errors.New("upload is incomplete")
+	ErrMediaTooLarge          = errors.New("media is too large")
+	ErrMediaNotFound          = errors.New("media not found")
+	ErrInvalidSignature       = errors.New("invalid or expired signature")
+	ErrInvalidPublishAt       = errors.New("publish_at must be in the future")
+	ErrScheduledTweetNotFound = errors.New("scheduled tweet not found")
+	ErrScheduledTweetLocked   = errors.New("scheduled tweet is no longer pending")
+	ErrDraftNotFound          = errors.New("draft not found")
+	ErrDraftVersionConflict   = errors.New("draft was changed by another request")
+	ErrDraftLocked            = errors.New("draft is being published")
+	ErrEmptyTweet             = errors.New("tweet text is required")
+	ErrPollNotFound           = errors.New("poll not found")
+	ErrPollClosed             = errors.New("poll is closed")
+	ErrAlreadyVoted           = errors.New("already voted")
+	ErrInvalidPollOption      = errors.New("invalid poll option")
+	ErrInvalidPoll            = errors.New("poll must have 2 to 4 options and last from 5 minutes to 7 days")
+	ErrEditWindowClosed       = errors.New("tweet can no longer be edited")
+	ErrReplyTargetNotFound    = errors.New("tweet to reply to not found")
+	ErrBlocked                = errors.New("you are blocked by this user")
+	ErrSelfRelation           = errors.New("cannot block or mute yourself")
+	ErrFollowRequestNotFound  = errors.New("follow request not found")
+	ErrPrivateAccount         = errors.New("this account is private")
+	ErrInvalidPreferences     = errors.New("invalid time zone or quiet hours, expected HH:MM")
+	ErrNotificationNotFound   = errors.New("notification not found")
+	ErrConversationNotFound   = errors.New("conversation not found")
+	ErrMessagesNotAllowed     = errors.New("user does not accept messages from you")
+	ErrMessageNotFound        = errors.New("message not found")
+	ErrWebhookNotFound        = errors.New("webhook not found")
+	ErrWebhookURLNotAllowed   = errors.New("webhook url must resolve to a public address")
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+	ErrTooManyAPIKeys         = errors.New("too many api keys")
+	ErrInvalidAPIKey          = errors.New("invalid api key")
+	ErrInsufficientScope      = errors.New("api key does not have required scope")
+	ErrSessionRequired        = errors.New("this endpoint cannot be used with api key")
+	ErrOIDCProviderNotFound   = errors.New("sign in provider not found")
+	ErrInvalidOIDCState       = errors.New("invalid or expired sign in state")
+	ErrOIDCFailed             = errors.New("cannot sign in with provider")
+	ErrOIDCEmailNotVerified   = errors.New("provider email is not verified")
+	ErrOIDCEmailMismatch      = errors.New("provider email does not match account email")
+	ErrOIDCAccountExists      = errors.New("account with this email already exists, sign in and link provider")
+	ErrIdentityAlreadyLinked  = errors.New("provider account is already linked")
+	ErrTwoFactorEnabled       = errors.New("two factor authentication is already enabled")
+	ErrTwoFactorNotEnabled    = errors.New("two factor authentication is not enabled")
+	ErrTwoFactorSetupNotFound = errors.New("two factor setup is not started")
+	ErrInvalidTwoFactorCode   = errors.New("invalid two factor code")
+	ErrInvalidChallenge       = errors.New("invalid or expired login challenge")
+	ErrEmailNotVerified       = errors.New("verify your email to do this")
 )
 
 func mapErrorWithCode(err error) int {
@@ -21,25 +73,137 @@ func mapErrorWithCode(err error) int {
 		return http.StatusBadRequest
 	case errors.Is(err, ErrInvalidImage):
 		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidVideo):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrMediaTooLong):
+		return http.StatusBadRequest
 	case errors.Is(err, ErrPasswordMismatch):
 		return http.StatusBadRequest
 	case errors.Is(err, ErrInvalidCode):
 		return http.StatusBadRequest
+	case errors.Is(err, cursor.ErrInvalidCursor):
+		return http.StatusBadRequest
 	case errors.Is(err, fiber.ErrUpgradeRequired):
 		return http.StatusUpgradeRequired
 	case errors.Is(err, ErrUserNotFound):
 		return http.StatusNotFound
+	case errors.Is(err, ErrUploadNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrMediaNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidOffset):
+		return http.StatusConflict
+	case errors.Is(err, ErrUploadIncomplete):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrMediaTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrInvalidSignature):
+		return http.StatusForbidden
+	case errors.Is(err, ErrInvalidPublishAt):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrScheduledTweetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrScheduledTweetLocked):
+		return http.StatusConflict
+	case errors.Is(err, ErrDraftNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrDraftVersionConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrDraftLocked):
+		return http.StatusConflict
+	case errors.Is(err, ErrEmptyTweet):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrPollNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrPollClosed):
+		return http.StatusConflict
+	case errors.Is(err, ErrAlreadyVoted):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidPollOption):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidPoll):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrEditWindowClosed):
+		return http.StatusForbidden
+	case errors.Is(err, ErrReplyTargetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrBlocked):
+		return http.StatusForbidden
+	case errors.Is(err, ErrSelfRelation):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrFollowRequestNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrPrivateAccount):
+		return http.StatusForbidden
+	case errors.Is(err, ErrInvalidPreferences):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNotificationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConversationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrMessagesNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, ErrMessageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrWebhookNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrWebhookURLNotAllowed):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrTooManyAPIKeys):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidAPIKey):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrInsufficientScope):
+		return http.StatusForbidden
+	case errors.Is(err, ErrSessionRequired):
+		return http.StatusForbidden
+	case errors.Is(err, ErrOIDCProviderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidOIDCState):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrOIDCFailed):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrOIDCEmailNotVerified):
+		return http.StatusForbidden
+	case errors.Is(err, ErrOIDCEmailMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, ErrOIDCAccountExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrIdentityAlreadyLinked):
+		return http.StatusConflict
+	case errors.Is(err, ErrTwoFactorEnabled):
+		return http.StatusConflict
+	case errors.Is(err, ErrTwoFactorNotEnabled):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrTwoFactorSetupNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidTwoFactorCode):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrInvalidChallenge):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrEmailNotVerified):
+		return http.StatusForbidden
 	}
 
 	return http.StatusInternalServerError
 }
 
+// CodeEmailNotVerified lets clients tell unverified account apart from other forbidden requests
+const CodeEmailNotVerified = "email_not_verified"
+
 // WithError responds to request with provided error
 func WithError(c *fiber.Ctx, err error) error {
-
-	return c.Status(mapErrorWithCode(err)).JSON(fiber.Map{
+	body := fiber.Map{
 		"message": err.Error(),
-	})
+	}
+
+	if errors.Is(err, ErrEmailNotVerified) {
+		body["code"] = CodeEmailNotVerified
+	}
+
+	return c.Status(mapErrorWithCode(err)).JSON(body)
 }
 
 // ReadRequest parses and validates request
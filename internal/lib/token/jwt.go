@@ -1,6 +1,9 @@
 package token
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
 
 type tokenClaims struct {
 	jwt.RegisteredClaims
@@ -24,3 +27,18 @@ func ParseToken(token string, secret string) (string, error) {
 
 	return claims.UserID, nil
 }
+
+// GenerateToken issues token in the same format as auth service does on login
+func GenerateToken(userID string, secret string, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		UserID: userID,
+	})
+
+	return token.SignedString([]byte(secret))
+}
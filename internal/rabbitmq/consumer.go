@@ -1,7 +1,11 @@
 package rabbitmq
 
 import (
-	"github.com/Verce11o/yata/internal/http/websocket"
+	"context"
+	"encoding/json"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/realtime"
+	"github.com/Verce11o/yata/internal/service"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/otel/trace"
@@ -12,10 +16,11 @@ type NotificationConsumer struct {
 	AmqpConn *amqp.Connection
 	log      *zap.SugaredLogger
 	trace    trace.Tracer
+	services *service.Services
 }
 
-func NewNotificationConsumer(amqpConn *amqp.Connection, log *zap.SugaredLogger, trace trace.Tracer) *NotificationConsumer {
-	return &NotificationConsumer{AmqpConn: amqpConn, log: log, trace: trace}
+func NewNotificationConsumer(amqpConn *amqp.Connection, log *zap.SugaredLogger, trace trace.Tracer, services *service.Services) *NotificationConsumer {
+	return &NotificationConsumer{AmqpConn: amqpConn, log: log, trace: trace, services: services}
 }
 
 func (c *NotificationConsumer) createChannel(exchangeName, queueName, bindingKey string) *amqp.Channel {
@@ -68,7 +73,7 @@ func (c *NotificationConsumer) createChannel(exchangeName, queueName, bindingKey
 
 }
 
-func (c *NotificationConsumer) StartConsumer(queueName, consumerTag, exchangeName, bindingKey string, clients websocket.WsClients) error {
+func (c *NotificationConsumer) StartConsumer(queueName, consumerTag, exchangeName, bindingKey string, hub *realtime.Hub) error {
 	ch := c.createChannel(exchangeName, queueName, bindingKey)
 	defer ch.Close()
 
@@ -88,47 +93,100 @@ func (c *NotificationConsumer) StartConsumer(queueName, consumerTag, exchangeNam
 
 	for i := 0; i < 5; i++ {
 		i := i
-		go c.worker(i, deliveries, clients)
+		go c.worker(i, deliveries, hub)
 	}
 	chanErr := <-ch.NotifyClose(make(chan *amqp.Error))
 	c.log.Infof("Notify close: %v", chanErr)
 
+	if chanErr == nil {
+		return nil
+	}
+
 	return chanErr
 
 }
 
-func (c *NotificationConsumer) worker(index int, messages <-chan amqp.Delivery, clients websocket.WsClients) {
-	//for message := range messages {
-	//	c.log.Infof("Worker #%d: %v", index, string(message.Body))
-	//
-	//	var request domain.IncomingNewTweetNotification // under change
-	//
-	//	err := json.Unmarshal(message.Body, &request)
-	//
-	//	if err != nil {
-	//		c.log.Errorf("failed to unmarshal request: %v", err)
-	//	}
-	//
-	//	// get all user subscribers
-	//
-	//	conn, ok := clients[request.FromUserID]
-	//
-	//	if ok {
-	//		// mark notification as read
-	//	}
-	//
-	//	err = conn.WriteJSON(request)
-	//
-	//	if err != nil {
-	//		c.log.Errorf("error sending notification: %v", err.Error())
-	//	}
-	//
-	//	err = message.Ack(false)
-	//
-	//	if err != nil {
-	//		c.log.Errorf("failed to acknowledge delivery: %v", err)
-	//	}
-	//
-	//}
+func (c *NotificationConsumer) worker(index int, messages <-chan amqp.Delivery, hub *realtime.Hub) {
+	for message := range messages {
+		c.log.Debugf("Worker #%d: %v", index, string(message.Body))
+
+		var notification domain.Notification
+
+		if err := json.Unmarshal(message.Body, &notification); err != nil {
+			c.log.Errorf("failed to unmarshal notification: %v", err)
+
+			if err := message.Nack(false, false); err != nil {
+				c.log.Errorf("failed to reject delivery: %v", err)
+			}
+			continue
+		}
+
+		c.services.Notifications.InvalidateUnreadCount(notification.UserID)
+
+		if c.shouldPush(notification) {
+			hub.Publish(notification.UserID, realtime.Event{
+				Type: realtime.EventNotification,
+				Data: notification,
+			})
+		}
+
+		if notification.Type == domain.MentionNotification {
+			c.dispatchMention(notification)
+		}
+
+		if err := message.Ack(false); err != nil {
+			c.log.Errorf("failed to acknowledge delivery: %v", err)
+		}
+	}
 	c.log.Info("Channel closed")
 }
+
+// shouldPush drops notifications from hidden users and ones recipient opted out of,
+// push is best effort, so on errors notification is only left in the list
+func (c *NotificationConsumer) shouldPush(notification domain.Notification) bool {
+	ctx, span := c.trace.Start(context.Background(), "Consumer.ShouldPush")
+	defer span.End()
+
+	visible, err := c.services.FilterNotifications(ctx, notification.UserID, []domain.Notification{notification})
+	if err != nil {
+		c.log.Errorf("failed to filter notification: %v", err)
+		return false
+	}
+
+	if len(visible) == 0 {
+		return false
+	}
+
+	allowed, err := c.services.Notifications.AllowNotification(ctx, notification, domain.ChannelPush)
+	if err != nil {
+		c.log.Errorf("failed to check notification preferences: %v", err)
+		return false
+	}
+
+	return allowed
+}
+
+// dispatchMention queues mention webhooks, mentions from hidden users are dropped like pushes
+func (c *NotificationConsumer) dispatchMention(notification domain.Notification) {
+	ctx, span := c.trace.Start(context.Background(), "Consumer.DispatchMention")
+	defer span.End()
+
+	visible, err := c.services.FilterNotifications(ctx, notification.UserID, []domain.Notification{notification})
+	if err != nil {
+		c.log.Errorf("failed to filter notification: %v", err)
+		return
+	}
+
+	if len(visible) == 0 {
+		return
+	}
+
+	err = c.services.Webhooks.Dispatch(ctx, notification.UserID, domain.WebhookEventMention, domain.MentionEvent{
+		TweetID: notification.TargetID,
+		UserID:  notification.SenderID,
+	})
+
+	if err != nil {
+		c.log.Errorf("failed to dispatch mention webhook: %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	EventNotification = "notification"
+	// EventResync tells client that events were lost and it has to reload the list, Seq is the latest one
+	EventResync      = "resync"
+	EventTyping      = "typing"
+	EventMessage     = "message"
+	EventReadReceipt = "read_receipt"
+	EventPong        = "pong"
+	EventError       = "error"
+
+	subscriptionBuffer = 32
+)
+
+// Event is pushed to every live connection of a user, Seq grows by one for each published event of the user,
+// events sent to a single connection or to a topic are not numbered. Numbering of a new history starts
+// from current time in microseconds, so seq of dropped history or of previous gateway run is never reused
+type Event struct {
+	Seq  uint64 `json:"seq,omitempty"`
+	Type string `json:"type"`
+	Data any    `json:"data,omitempty"`
+}
+
+type Subscription struct {
+	userID string
+	events chan Event
+	topics map[string]struct{}
+}
+
+// Events is closed once subscription is removed from hub
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// history keeps the latest events of a user in a ring
+type history struct {
+	// start is seq before the first event of this history, client resuming from earlier seq missed events
+	start  uint64
+	seq    uint64
+	events []Event
+	next   int
+	// publishedAt is when the last event was added, history of offline user is dropped once it is older than replay window
+	publishedAt time.Time
+}
+
+func (r *history) add(event Event) {
+	if len(r.events) < cap(r.events) {
+		r.events = append(r.events, event)
+		return
+	}
+
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+}
+
+// since returns events after seq oldest first, false means some of them are not kept anymore
+func (r *history) since(seq uint64) ([]Event, bool) {
+	// seq of dropped history, from before gateway restart or of another replica
+	if seq < r.start || seq > r.seq {
+		return nil, false
+	}
+
+	if seq == r.seq {
+		return nil, true
+	}
+
+	ordered := append(append(make([]Event, 0, len(r.events)), r.events[r.next:]...), r.events[:r.next]...)
+
+	if len(ordered) == 0 || seq+1 < ordered[0].Seq {
+		return nil, false
+	}
+
+	return ordered[seq+1-ordered[0].Seq:], true
+}
+
+// Hub is in-process registry of live connections, one user may have several of them
+type Hub struct {
+	mu           sync.Mutex
+	subscribers  map[string]map[*Subscription]struct{}
+	topics       map[string]map[*Subscription]struct{}
+	histories    map[string]*history
+	bufferSize   int
+	replayWindow time.Duration
+	sweptAt      time.Time
+	// dropped is the highest seq of dropped histories, new ones start after it
+	dropped uint64
+	closed  bool
+}
+
+// NewHub keeps up to bufferSize events per user, events of users without connections are kept for replayWindow
+func NewHub(bufferSize int, replayWindow time.Duration) *Hub {
+	return &Hub{
+		subscribers:  make(map[string]map[*Subscription]struct{}),
+		topics:       make(map[string]map[*Subscription]struct{}),
+		histories:    make(map[string]*history),
+		bufferSize:   bufferSize,
+		replayWindow: replayWindow,
+		sweptAt:      time.Now(),
+	}
+}
+
+func (h *Hub) Subscribe(userID string) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.subscribe(userID)
+}
+
+// SubscribeSince subscribes and returns kept events after seq, if some were lost
+// single resync event is returned instead
+func (h *Hub) SubscribeSince(userID string, seq uint64) (*Subscription, []Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := h.subscribe(userID)
+
+	// nothing was published since the window expired or ever, history is not created until something is
+	r, ok := h.histories[userID]
+	if !ok {
+		r = &history{}
+	}
+
+	missed, ok := r.since(seq)
+	if !ok {
+		return sub, []Event{{Seq: r.seq, Type: EventResync}}
+	}
+
+	return sub, missed
+}
+
+func (h *Hub) subscribe(userID string) *Subscription {
+	sub := &Subscription{userID: userID, events: make(chan Event, subscriptionBuffer), topics: make(map[string]struct{})}
+
+	if h.closed {
+		close(sub.events)
+		return sub
+	}
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+
+	return sub
+}
+
+func (h *Hub) history(userID string, now time.Time) *history {
+	r, ok := h.histories[userID]
+	if !ok {
+		start := max(uint64(now.UnixMicro()), h.dropped)
+		r = &history{start: start, seq: start, events: make([]Event, 0, h.bufferSize)}
+		h.histories[userID] = r
+	}
+
+	return r
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs, ok := h.subscribers[sub.userID]
+	if !ok {
+		return
+	}
+
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.events)
+
+	for topic := range sub.topics {
+		h.leave(sub, topic)
+	}
+
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.userID)
+	}
+
+	h.sweep(time.Now())
+}
+
+// sweep drops histories of users without connections whose last event is older than replay window,
+// it walks all histories, so it runs at most once per window
+func (h *Hub) sweep(now time.Time) {
+	if now.Sub(h.sweptAt) < h.replayWindow {
+		return
+	}
+
+	h.sweptAt = now
+
+	for userID, r := range h.histories {
+		if _, online := h.subscribers[userID]; !online && now.Sub(r.publishedAt) >= h.replayWindow {
+			h.dropped = max(h.dropped, r.seq)
+			delete(h.histories, userID)
+		}
+	}
+}
+
+// Publish numbers event and sends it to all connections of user, slow connections which buffer is full
+// miss the event and can get it back by reconnecting, returns false if none got it
+func (h *Hub) Publish(userID string, event Event) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.sweep(now)
+
+	r := h.history(userID, now)
+	r.seq++
+	r.publishedAt = now
+	event.Seq = r.seq
+
+	if h.bufferSize > 0 {
+		r.add(event)
+	}
+
+	delivered := false
+
+	for sub := range h.subscribers[userID] {
+		select {
+		case sub.events <- event:
+			delivered = true
+		default:
+		}
+	}
+
+	return delivered
+}
+
+// Close ends all subscriptions, so long-lived streams do not block shutdown
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for userID, subs := range h.subscribers {
+		for sub := range subs {
+			close(sub.events)
+		}
+		delete(h.subscribers, userID)
+	}
+
+	h.topics = make(map[string]map[*Subscription]struct{})
+	h.closed = true
+}
+
+// Send pushes event to a single connection, e.g. a reply to client's message
+func (h *Hub) Send(sub *Subscription, event Event) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub.userID][sub]; !ok {
+		return false
+	}
+
+	select {
+	case sub.events <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// Join subscribes connection to topic until it leaves it or disconnects
+func (h *Hub) Join(sub *Subscription, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub.userID][sub]; !ok {
+		return
+	}
+
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*Subscription]struct{})
+	}
+	h.topics[topic][sub] = struct{}{}
+	sub.topics[topic] = struct{}{}
+}
+
+func (h *Hub) Leave(sub *Subscription, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leave(sub, topic)
+}
+
+func (h *Hub) leave(sub *Subscription, topic string) {
+	delete(sub.topics, topic)
+
+	subs, ok := h.topics[topic]
+	if !ok {
+		return
+	}
+
+	delete(subs, sub)
+
+	if len(subs) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+func (h *Hub) Joined(sub *Subscription, topic string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, ok := sub.topics[topic]
+	return ok
+}
+
+// Broadcast sends event to every connection in topic except sender, topics are local to the replica
+func (h *Hub) Broadcast(topic string, event Event, except *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.topics[topic] {
+		if sub == except {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// ThreadTopic is joined by connections that have tweet's comment thread open
+func ThreadTopic(tweetID string) string {
+	return "thread:" + tweetID
+}
@@ -0,0 +1,107 @@
+package recommendation
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryGraph is a Graph kept in memory, its results are always sorted so
+// recommendations built from it are reproducible
+type MemoryGraph struct {
+	mu        sync.RWMutex
+	following map[string]map[string]struct{}
+	followers map[string]int
+	posts     map[string]int
+}
+
+func NewMemoryGraph() *MemoryGraph {
+	return &MemoryGraph{
+		following: make(map[string]map[string]struct{}),
+		followers: make(map[string]int),
+		posts:     make(map[string]int),
+	}
+}
+
+func (g *MemoryGraph) Follow(followerID, followeeID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.following[followerID] == nil {
+		g.following[followerID] = make(map[string]struct{})
+	}
+
+	if _, ok := g.following[followerID][followeeID]; ok {
+		return
+	}
+
+	g.following[followerID][followeeID] = struct{}{}
+	g.followers[followeeID]++
+}
+
+func (g *MemoryGraph) SetRecentPosts(userID string, count int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.posts[userID] = count
+}
+
+func (g *MemoryGraph) Following(ctx context.Context, userID string) ([]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	result := make([]string, 0, len(g.following[userID]))
+	for followeeID := range g.following[userID] {
+		result = append(result, followeeID)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func (g *MemoryGraph) FollowerCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	result := make(map[string]int, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = g.followers[userID]
+	}
+
+	return result, nil
+}
+
+func (g *MemoryGraph) RecentPosts(ctx context.Context) (map[string]int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	result := make(map[string]int, len(g.posts))
+	for userID, count := range g.posts {
+		result[userID] = count
+	}
+
+	return result, nil
+}
+
+func (g *MemoryGraph) MostFollowed(ctx context.Context, limit int) ([]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	result := make([]string, 0, len(g.followers))
+	for userID := range g.followers {
+		result = append(result, userID)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if g.followers[result[i]] != g.followers[result[j]] {
+			return g.followers[result[i]] > g.followers[result[j]]
+		}
+		return result[i] < result[j]
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
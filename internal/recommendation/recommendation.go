@@ -0,0 +1,158 @@
+package recommendation
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// Graph is the social data recommendations are built from
+type Graph interface {
+	// Following returns users the user follows
+	Following(ctx context.Context, userID string) ([]string, error)
+	FollowerCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+	// RecentPosts returns number of recent posts of recently active users
+	RecentPosts(ctx context.Context) (map[string]int, error)
+	// MostFollowed returns users with the most followers, used when user has no graph yet
+	MostFollowed(ctx context.Context, limit int) ([]string, error)
+}
+
+// Candidate keeps signals collected for a user that may be recommended
+type Candidate struct {
+	UserID string
+	// MutualFollows is how many of the user's followees follow the candidate
+	MutualFollows int
+	RecentPosts   int
+	Followers     int
+}
+
+type Scorer interface {
+	Score(candidate Candidate) float64
+}
+
+// WeightedScorer sums signals with weights, activity and popularity are
+// log-scaled so a few very popular accounts do not push out friends of friends
+type WeightedScorer struct {
+	Mutual     float64
+	Activity   float64
+	Popularity float64
+}
+
+func DefaultScorer() WeightedScorer {
+	return WeightedScorer{Mutual: 3, Activity: 1, Popularity: 0.5}
+}
+
+func (w WeightedScorer) Score(candidate Candidate) float64 {
+	return w.Mutual*float64(candidate.MutualFollows) +
+		w.Activity*math.Log1p(float64(candidate.RecentPosts)) +
+		w.Popularity*math.Log1p(float64(candidate.Followers))
+}
+
+type Recommendation struct {
+	Candidate
+	Score float64
+}
+
+type Recommender struct {
+	graph  Graph
+	scorer Scorer
+}
+
+func NewRecommender(graph Graph, scorer Scorer) *Recommender {
+	return &Recommender{graph: graph, scorer: scorer}
+}
+
+// Recommend ranks users the user does not follow yet. Ties are broken by user id,
+// so the result only depends on the graph.
+func (r *Recommender) Recommend(ctx context.Context, userID string, exclude map[string]struct{}, limit int) ([]Recommendation, error) {
+	following, err := r.graph.Following(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool, len(following)+len(exclude)+1)
+	skip[userID] = true
+	for _, followeeID := range following {
+		skip[followeeID] = true
+	}
+	for excludedID := range exclude {
+		skip[excludedID] = true
+	}
+
+	candidates := make(map[string]*Candidate)
+
+	candidate := func(id string) *Candidate {
+		if c, ok := candidates[id]; ok {
+			return c
+		}
+		c := &Candidate{UserID: id}
+		candidates[id] = c
+		return c
+	}
+
+	for _, followeeID := range following {
+		secondHop, err := r.graph.Following(ctx, followeeID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range secondHop {
+			if !skip[id] {
+				candidate(id).MutualFollows++
+			}
+		}
+	}
+
+	posts, err := r.graph.RecentPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, count := range posts {
+		if !skip[id] {
+			candidate(id).RecentPosts = count
+		}
+	}
+
+	if len(candidates) < limit {
+		popular, err := r.graph.MostFollowed(ctx, limit+len(skip))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range popular {
+			if !skip[id] {
+				candidate(id)
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for id := range candidates {
+		ids = append(ids, id)
+	}
+
+	followers, err := r.graph.FollowerCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Recommendation, 0, len(candidates))
+	for _, c := range candidates {
+		c.Followers = followers[c.UserID]
+		result = append(result, Recommendation{Candidate: *c, Score: r.scorer.Score(*c)})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].UserID < result[j].UserID
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
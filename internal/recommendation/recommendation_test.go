@@ -0,0 +1,157 @@
+package recommendation
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func userIDs(result []Recommendation) []string {
+	ids := make([]string, 0, len(result))
+	for _, item := range result {
+		ids = append(ids, item.UserID)
+	}
+	return ids
+}
+
+func TestWeightedScorer(t *testing.T) {
+	scorer := WeightedScorer{Mutual: 3, Activity: 1, Popularity: 0.5}
+
+	tests := []struct {
+		name      string
+		candidate Candidate
+		want      float64
+	}{
+		{name: "no signals", candidate: Candidate{}, want: 0},
+		{name: "mutual follows are linear", candidate: Candidate{MutualFollows: 2}, want: 6},
+		{name: "activity is log scaled", candidate: Candidate{RecentPosts: 9}, want: math.Log1p(9)},
+		{name: "popularity is log scaled", candidate: Candidate{Followers: 99}, want: 0.5 * math.Log1p(99)},
+		{
+			name:      "signals are summed",
+			candidate: Candidate{MutualFollows: 1, RecentPosts: 3, Followers: 7},
+			want:      3 + math.Log1p(3) + 0.5*math.Log1p(7),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scorer.Score(tt.candidate); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendRanksFriendsOfFriendsFirst(t *testing.T) {
+	graph := NewMemoryGraph()
+
+	graph.Follow("alice", "bob")
+	graph.Follow("alice", "carol")
+
+	// dave is followed by both of alice's followees, erin by one
+	graph.Follow("bob", "dave")
+	graph.Follow("carol", "dave")
+	graph.Follow("bob", "erin")
+
+	// frank has the most followers and posts, but nobody alice follows knows him
+	for _, id := range []string{"u1", "u2", "u3", "u4", "u5"} {
+		graph.Follow(id, "frank")
+	}
+	graph.SetRecentPosts("frank", 3)
+
+	result, err := NewRecommender(graph, DefaultScorer()).Recommend(context.Background(), "alice", nil, 10)
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+
+	want := []string{"dave", "erin", "frank"}
+	if got := userIDs(result); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Recommend() = %v, want %v", got, want)
+	}
+
+	dave := result[0]
+	if dave.MutualFollows != 2 || dave.Followers != 2 || dave.RecentPosts != 0 {
+		t.Errorf("dave signals = %+v, want 2 mutual follows and 2 followers", dave.Candidate)
+	}
+
+	for i := 1; i < len(result); i++ {
+		if result[i-1].Score < result[i].Score {
+			t.Errorf("result is not sorted by score: %v before %v", result[i-1], result[i])
+		}
+	}
+}
+
+func TestRecommendExcludesFollowedAndHiddenUsers(t *testing.T) {
+	graph := NewMemoryGraph()
+
+	graph.Follow("alice", "bob")
+	graph.Follow("bob", "alice")
+	graph.Follow("bob", "carol")
+	graph.Follow("bob", "blocked")
+	graph.Follow("bob", "muted")
+	graph.Follow("bob", "dave")
+	graph.SetRecentPosts("bob", 5)
+	graph.SetRecentPosts("blocked", 50)
+
+	// blocked and muted users are passed in by the caller
+	exclude := map[string]struct{}{
+		"blocked": {},
+		"muted":   {},
+	}
+
+	result, err := NewRecommender(graph, DefaultScorer()).Recommend(context.Background(), "alice", exclude, 10)
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+
+	want := []string{"carol", "dave"}
+	if got := userIDs(result); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Recommend() = %v, want %v", got, want)
+	}
+}
+
+func TestRecommendBreaksTiesByUserID(t *testing.T) {
+	graph := NewMemoryGraph()
+
+	graph.Follow("alice", "bob")
+	for _, id := range []string{"zed", "kim", "amy", "max"} {
+		graph.Follow("bob", id)
+	}
+
+	recommender := NewRecommender(graph, DefaultScorer())
+
+	// result must not depend on map iteration order
+	for i := 0; i < 20; i++ {
+		result, err := recommender.Recommend(context.Background(), "alice", nil, 3)
+		if err != nil {
+			t.Fatalf("Recommend() error = %v", err)
+		}
+
+		want := []string{"amy", "kim", "max"}
+		if got := userIDs(result); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Recommend() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRecommendFallsBackToMostFollowed(t *testing.T) {
+	graph := NewMemoryGraph()
+
+	graph.Follow("u1", "popular")
+	graph.Follow("u2", "popular")
+	graph.Follow("u3", "popular")
+	graph.Follow("u1", "known")
+	graph.Follow("u2", "known")
+	graph.Follow("u1", "newcomer")
+
+	result, err := NewRecommender(graph, DefaultScorer()).Recommend(context.Background(), "new-user", nil, 2)
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+
+	want := []string{"popular", "known"}
+	if got := userIDs(result); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Recommend() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"sync"
+	"time"
+)
+
+type AccountSettingsRepository struct {
+	mu       sync.RWMutex
+	settings map[string]domain.AccountSettings
+}
+
+func NewAccountSettingsRepository() *AccountSettingsRepository {
+	return &AccountSettingsRepository{settings: make(map[string]domain.AccountSettings)}
+}
+
+func (r *AccountSettingsRepository) GetSettings(ctx context.Context, userIDs []string) (map[string]domain.AccountSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]domain.AccountSettings, len(userIDs))
+
+	for _, userID := range userIDs {
+		if settings, ok := r.settings[userID]; ok {
+			result[userID] = settings
+		}
+	}
+
+	return result, nil
+}
+
+func (r *AccountSettingsRepository) UpdateSettings(ctx context.Context, settings domain.AccountSettings) (domain.AccountSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings.UpdatedAt = time.Now().UTC()
+	r.settings[settings.UserID] = settings
+
+	return settings, nil
+}
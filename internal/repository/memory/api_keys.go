@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+type apiKey struct {
+	domain.APIKey
+	hash string
+}
+
+type APIKeyRepository struct {
+	mu   sync.Mutex
+	keys map[string]*apiKey
+}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{keys: make(map[string]*apiKey)}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key domain.APIKey, hash string) (domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key.Key = ""
+	key.Scopes = slices.Clone(key.Scopes)
+	key.CreatedAt = time.Now().UTC()
+
+	r.keys[key.KeyID] = &apiKey{APIKey: key, hash: hash}
+
+	return copyAPIKey(key), nil
+}
+
+func (r *APIKeyRepository) GetAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]domain.APIKey, 0)
+
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, copyAPIKey(key.APIKey))
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) CountActiveAPIKeys(ctx context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+
+	for _, key := range r.keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.keys {
+		if key.hash == hash && key.RevokedAt == nil {
+			return copyAPIKey(key.APIKey), nil
+		}
+	}
+
+	return domain.APIKey{}, response.ErrAPIKeyNotFound
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[keyID]
+	if !ok || key.UserID != userID || key.RevokedAt != nil {
+		return response.ErrAPIKeyNotFound
+	}
+
+	now := time.Now().UTC()
+	key.RevokedAt = &now
+
+	return nil
+}
+
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, keyID string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[keyID]
+	if !ok {
+		return response.ErrAPIKeyNotFound
+	}
+
+	usedAt = usedAt.UTC()
+	key.LastUsedAt = &usedAt
+
+	return nil
+}
+
+func copyAPIKey(key domain.APIKey) domain.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+
+	if key.LastUsedAt != nil {
+		lastUsedAt := *key.LastUsedAt
+		key.LastUsedAt = &lastUsedAt
+	}
+
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		key.RevokedAt = &revokedAt
+	}
+
+	return key
+}
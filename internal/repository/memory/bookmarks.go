@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"sort"
+	"sync"
+	"time"
+)
+
+type BookmarkRepository struct {
+	mu        sync.RWMutex
+	bookmarks map[string]map[string]domain.Bookmark
+}
+
+func NewBookmarkRepository() *BookmarkRepository {
+	return &BookmarkRepository{bookmarks: make(map[string]map[string]domain.Bookmark)}
+}
+
+func (r *BookmarkRepository) AddBookmark(ctx context.Context, userID, tweetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.bookmarks[userID] == nil {
+		r.bookmarks[userID] = make(map[string]domain.Bookmark)
+	}
+
+	if _, ok := r.bookmarks[userID][tweetID]; ok {
+		return nil
+	}
+
+	r.bookmarks[userID][tweetID] = domain.Bookmark{
+		UserID:    userID,
+		TweetID:   tweetID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	return nil
+}
+
+func (r *BookmarkRepository) DeleteBookmark(ctx context.Context, userID, tweetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.bookmarks[userID], tweetID)
+	return nil
+}
+
+func (r *BookmarkRepository) GetBookmarks(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Bookmark, string, error) {
+	var cursorTime time.Time
+	var cursorID string
+
+	if pageCursor != "" {
+		var err error
+		if cursorTime, cursorID, err = cursor.Decode(pageCursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	r.mu.RLock()
+	bookmarks := make([]domain.Bookmark, 0, len(r.bookmarks[userID]))
+	for _, bookmark := range r.bookmarks[userID] {
+		if pageCursor == "" || cursor.Before(bookmark.CreatedAt, bookmark.TweetID, cursorTime, cursorID) {
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(bookmarks, func(i, j int) bool {
+		return cursor.Before(bookmarks[j].CreatedAt, bookmarks[j].TweetID, bookmarks[i].CreatedAt, bookmarks[i].TweetID)
+	})
+
+	if len(bookmarks) <= limit {
+		return bookmarks, "", nil
+	}
+
+	bookmarks = bookmarks[:limit]
+	last := bookmarks[len(bookmarks)-1]
+
+	return bookmarks, cursor.Encode(last.CreatedAt, last.TweetID), nil
+}
+
+func (r *BookmarkRepository) GetBookmarkedTweets(ctx context.Context, userID string, tweetIDs []string) (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]bool, len(tweetIDs))
+
+	for _, tweetID := range tweetIDs {
+		if _, ok := r.bookmarks[userID][tweetID]; ok {
+			result[tweetID] = true
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"sort"
+	"sync"
+	"time"
+)
+
+type ConversationRepository struct {
+	mu            sync.RWMutex
+	conversations map[string]domain.Conversation
+	direct        map[string]string
+	messages      map[string][]domain.Message
+	// messageConversations maps message id to its conversation
+	messageConversations map[string]string
+}
+
+func NewConversationRepository() *ConversationRepository {
+	return &ConversationRepository{
+		conversations:        make(map[string]domain.Conversation),
+		direct:               make(map[string]string),
+		messages:             make(map[string][]domain.Message),
+		messageConversations: make(map[string]string),
+	}
+}
+
+func (r *ConversationRepository) CreateConversation(ctx context.Context, conversation domain.Conversation, directKey string) (domain.Conversation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if directKey != "" {
+		if conversationID, ok := r.direct[directKey]; ok {
+			return copyConversation(r.conversations[conversationID]), nil
+		}
+		r.direct[directKey] = conversation.ConversationID
+	}
+
+	now := time.Now().UTC()
+	conversation.CreatedAt = now
+	conversation.LastMessageAt = now
+
+	r.conversations[conversation.ConversationID] = copyConversation(conversation)
+
+	return conversation, nil
+}
+
+func (r *ConversationRepository) GetDirectConversation(ctx context.Context, directKey string) (domain.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversationID, ok := r.direct[directKey]
+	if !ok {
+		return domain.Conversation{}, response.ErrConversationNotFound
+	}
+
+	return copyConversation(r.conversations[conversationID]), nil
+}
+
+func (r *ConversationRepository) GetConversation(ctx context.Context, conversationID string) (domain.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversation, ok := r.conversations[conversationID]
+	if !ok {
+		return domain.Conversation{}, response.ErrConversationNotFound
+	}
+
+	return copyConversation(conversation), nil
+}
+
+func (r *ConversationRepository) GetConversations(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Conversation, string, error) {
+	r.mu.RLock()
+	conversations := make([]domain.Conversation, 0)
+	for _, conversation := range r.conversations {
+		for _, member := range conversation.Members {
+			if member.UserID == userID {
+				conversations = append(conversations, copyConversation(conversation))
+				break
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	if pageCursor != "" {
+		cursorTime, cursorID, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		filtered := conversations[:0]
+		for _, conversation := range conversations {
+			if cursor.Before(conversation.LastMessageAt, conversation.ConversationID, cursorTime, cursorID) {
+				filtered = append(filtered, conversation)
+			}
+		}
+		conversations = filtered
+	}
+
+	sort.Slice(conversations, func(i, j int) bool {
+		return cursor.Before(conversations[j].LastMessageAt, conversations[j].ConversationID, conversations[i].LastMessageAt, conversations[i].ConversationID)
+	})
+
+	if len(conversations) <= limit {
+		return conversations, "", nil
+	}
+
+	conversations = conversations[:limit]
+	last := conversations[len(conversations)-1]
+
+	return conversations, cursor.Encode(last.LastMessageAt, last.ConversationID), nil
+}
+
+func (r *ConversationRepository) CreateMessage(ctx context.Context, message domain.Message) (domain.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conversation, ok := r.conversations[message.ConversationID]
+	if !ok {
+		return domain.Message{}, response.ErrConversationNotFound
+	}
+
+	message.CreatedAt = time.Now().UTC()
+
+	r.messages[message.ConversationID] = append(r.messages[message.ConversationID], message)
+	r.messageConversations[message.MessageID] = message.ConversationID
+
+	conversation.LastMessageAt = message.CreatedAt
+	r.conversations[message.ConversationID] = conversation
+
+	return message, nil
+}
+
+func (r *ConversationRepository) GetMessages(ctx context.Context, conversationID, pageCursor string, limit int) ([]domain.Message, string, error) {
+	r.mu.RLock()
+	stored := r.messages[conversationID]
+	messages := make([]domain.Message, 0, len(stored))
+	// messages are stored oldest first
+	for i := len(stored) - 1; i >= 0; i-- {
+		messages = append(messages, stored[i])
+	}
+	r.mu.RUnlock()
+
+	if pageCursor != "" {
+		cursorTime, cursorID, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		filtered := messages[:0]
+		for _, message := range messages {
+			if cursor.Before(message.CreatedAt, message.MessageID, cursorTime, cursorID) {
+				filtered = append(filtered, message)
+			}
+		}
+		messages = filtered
+	}
+
+	if len(messages) <= limit {
+		return messages, "", nil
+	}
+
+	messages = messages[:limit]
+	last := messages[len(messages)-1]
+
+	return messages, cursor.Encode(last.CreatedAt, last.MessageID), nil
+}
+
+func (r *ConversationRepository) MarkRead(ctx context.Context, conversationID, userID, messageID string) (time.Time, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.messageConversations[messageID] != conversationID {
+		return time.Time{}, false, response.ErrMessageNotFound
+	}
+
+	conversation := r.conversations[conversationID]
+	message := r.message(conversationID, messageID)
+
+	for i, member := range conversation.Members {
+		if member.UserID != userID {
+			continue
+		}
+
+		if member.LastReadMessageID != "" {
+			lastRead := r.message(conversationID, member.LastReadMessageID)
+			if !cursor.Before(lastRead.CreatedAt, lastRead.MessageID, message.CreatedAt, message.MessageID) {
+				return time.Time{}, false, nil
+			}
+		}
+
+		readAt := time.Now().UTC()
+		conversation.Members[i].LastReadMessageID = messageID
+		conversation.Members[i].LastReadAt = &readAt
+
+		return readAt, true, nil
+	}
+
+	return time.Time{}, false, response.ErrConversationNotFound
+}
+
+func (r *ConversationRepository) message(conversationID, messageID string) domain.Message {
+	for _, message := range r.messages[conversationID] {
+		if message.MessageID == messageID {
+			return message
+		}
+	}
+
+	return domain.Message{}
+}
+
+func copyConversation(conversation domain.Conversation) domain.Conversation {
+	members := make([]domain.ConversationMember, len(conversation.Members))
+	copy(members, conversation.Members)
+	conversation.Members = members
+
+	return conversation
+}
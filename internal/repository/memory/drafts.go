@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"sort"
+	"sync"
+	"time"
+)
+
+type draft struct {
+	domain.Draft
+	lockedUntil time.Time
+}
+
+type DraftRepository struct {
+	mu     sync.Mutex
+	drafts map[string]*draft
+}
+
+func NewDraftRepository() *DraftRepository {
+	return &DraftRepository{drafts: make(map[string]*draft)}
+}
+
+func (r *DraftRepository) CreateDraft(ctx context.Context, d domain.Draft) (domain.Draft, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	d.Version = 1
+	d.CreatedAt = now
+	d.UpdatedAt = now
+
+	r.drafts[d.DraftID] = &draft{Draft: d}
+
+	return d, nil
+}
+
+func (r *DraftRepository) GetDraft(ctx context.Context, userID, draftID string) (domain.Draft, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, err := r.get(userID, draftID)
+	if err != nil {
+		return domain.Draft{}, err
+	}
+
+	// draft is already a tweet, it only waits to be removed
+	if d.PublishedTweetID != "" {
+		return domain.Draft{}, response.ErrDraftNotFound
+	}
+
+	return d.Draft, nil
+}
+
+func (r *DraftRepository) GetDrafts(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Draft, string, error) {
+	var cursorTime time.Time
+	var cursorID string
+
+	if pageCursor != "" {
+		var err error
+		if cursorTime, cursorID, err = cursor.Decode(pageCursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	r.mu.Lock()
+	drafts := make([]domain.Draft, 0)
+	for _, d := range r.drafts {
+		if d.UserID != userID || d.PublishedTweetID != "" {
+			continue
+		}
+		if pageCursor == "" || cursor.Before(d.CreatedAt, d.DraftID, cursorTime, cursorID) {
+			drafts = append(drafts, d.Draft)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return cursor.Before(drafts[j].CreatedAt, drafts[j].DraftID, drafts[i].CreatedAt, drafts[i].DraftID)
+	})
+
+	if len(drafts) <= limit {
+		return drafts, "", nil
+	}
+
+	drafts = drafts[:limit]
+	last := drafts[len(drafts)-1]
+
+	return drafts, cursor.Encode(last.CreatedAt, last.DraftID), nil
+}
+
+func (r *DraftRepository) UpdateDraft(ctx context.Context, d domain.Draft) (domain.Draft, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.unlocked(d.UserID, d.DraftID)
+	if err != nil {
+		return domain.Draft{}, err
+	}
+
+	if current.PublishedTweetID != "" {
+		return domain.Draft{}, response.ErrDraftNotFound
+	}
+
+	if current.Version != d.Version {
+		return current.Draft, response.ErrDraftVersionConflict
+	}
+
+	current.Text = d.Text
+	current.MediaIDs = d.MediaIDs
+	current.Version++
+	current.UpdatedAt = time.Now().UTC()
+
+	return current.Draft, nil
+}
+
+func (r *DraftRepository) DeleteDraft(ctx context.Context, userID, draftID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.get(userID, draftID); err != nil {
+		return err
+	}
+
+	delete(r.drafts, draftID)
+
+	return nil
+}
+
+func (r *DraftRepository) LockDraft(ctx context.Context, userID, draftID string, until time.Time) (domain.Draft, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, err := r.unlocked(userID, draftID)
+	if err != nil {
+		return domain.Draft{}, err
+	}
+
+	d.lockedUntil = until
+
+	return d.Draft, nil
+}
+
+func (r *DraftRepository) UnlockDraft(ctx context.Context, userID, draftID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, err := r.get(userID, draftID)
+	if err != nil {
+		return err
+	}
+
+	d.lockedUntil = time.Time{}
+
+	return nil
+}
+
+func (r *DraftRepository) SetPublishedTweet(ctx context.Context, userID, draftID, tweetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, err := r.get(userID, draftID)
+	if err != nil {
+		return err
+	}
+
+	d.PublishedTweetID = tweetID
+
+	return nil
+}
+
+func (r *DraftRepository) get(userID, draftID string) (*draft, error) {
+	d, ok := r.drafts[draftID]
+	if !ok || d.UserID != userID {
+		return nil, response.ErrDraftNotFound
+	}
+
+	return d, nil
+}
+
+func (r *DraftRepository) unlocked(userID, draftID string) (*draft, error) {
+	d, err := r.get(userID, draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.lockedUntil.After(time.Now()) {
+		return nil, response.ErrDraftLocked
+	}
+
+	return d, nil
+}
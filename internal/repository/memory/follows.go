@@ -0,0 +1,286 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"sort"
+	"sync"
+	"time"
+)
+
+type FollowRepository struct {
+	mu        sync.RWMutex
+	follows   map[string]map[string]domain.Follow
+	followers map[string]map[string]domain.Follow
+}
+
+func NewFollowRepository() *FollowRepository {
+	return &FollowRepository{
+		follows:   make(map[string]map[string]domain.Follow),
+		followers: make(map[string]map[string]domain.Follow),
+	}
+}
+
+func (r *FollowRepository) AddFollow(ctx context.Context, followerID, followeeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.follows[followerID] == nil {
+		r.follows[followerID] = make(map[string]domain.Follow)
+	}
+
+	if _, ok := r.follows[followerID][followeeID]; ok {
+		return nil
+	}
+
+	if r.followers[followeeID] == nil {
+		r.followers[followeeID] = make(map[string]domain.Follow)
+	}
+
+	follow := domain.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	r.follows[followerID][followeeID] = follow
+	r.followers[followeeID][followerID] = follow
+
+	return nil
+}
+
+func (r *FollowRepository) DeleteFollow(ctx context.Context, followerID, followeeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.follows[followerID], followeeID)
+	delete(r.followers[followeeID], followerID)
+	return nil
+}
+
+func (r *FollowRepository) GetFollowedUsers(ctx context.Context, followerID string, followeeIDs []string) (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]bool, len(followeeIDs))
+
+	for _, followeeID := range followeeIDs {
+		if _, ok := r.follows[followerID][followeeID]; ok {
+			result[followeeID] = true
+		}
+	}
+
+	return result, nil
+}
+
+func (r *FollowRepository) GetFollowingUsers(ctx context.Context, followeeID string, followerIDs []string) (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]bool, len(followerIDs))
+
+	for _, followerID := range followerIDs {
+		if _, ok := r.followers[followeeID][followerID]; ok {
+			result[followerID] = true
+		}
+	}
+
+	return result, nil
+}
+
+func (r *FollowRepository) GetFollowers(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Follow, string, error) {
+	r.mu.RLock()
+	follows := make([]domain.Follow, 0, len(r.followers[userID]))
+	for _, follow := range r.followers[userID] {
+		follows = append(follows, follow)
+	}
+	r.mu.RUnlock()
+
+	return pageFollows(follows, followerKey, pageCursor, limit)
+}
+
+func (r *FollowRepository) GetFollowing(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Follow, string, error) {
+	r.mu.RLock()
+	follows := make([]domain.Follow, 0, len(r.follows[userID]))
+	for _, follow := range r.follows[userID] {
+		follows = append(follows, follow)
+	}
+	r.mu.RUnlock()
+
+	return pageFollows(follows, followeeKey, pageCursor, limit)
+}
+
+func (r *FollowRepository) GetMutuals(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Follow, string, error) {
+	r.mu.RLock()
+	follows := make([]domain.Follow, 0)
+	for followerID, follow := range r.followers[userID] {
+		if _, ok := r.follows[userID][followerID]; ok {
+			follows = append(follows, follow)
+		}
+	}
+	r.mu.RUnlock()
+
+	return pageFollows(follows, followerKey, pageCursor, limit)
+}
+
+func (r *FollowRepository) GetFollowerCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]int, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = len(r.followers[userID])
+	}
+
+	return result, nil
+}
+
+func (r *FollowRepository) GetMostFollowed(ctx context.Context, limit int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]string, 0, len(r.followers))
+	for userID, followers := range r.followers {
+		if len(followers) > 0 {
+			result = append(result, userID)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if len(r.followers[result[i]]) != len(r.followers[result[j]]) {
+			return len(r.followers[result[i]]) > len(r.followers[result[j]])
+		}
+		return result[i] < result[j]
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func followerKey(follow domain.Follow) string {
+	return follow.FollowerID
+}
+
+func followeeKey(follow domain.Follow) string {
+	return follow.FolloweeID
+}
+
+// pageFollows returns follows newest first, key picks the user follows are listed by
+func pageFollows(follows []domain.Follow, key func(domain.Follow) string, pageCursor string, limit int) ([]domain.Follow, string, error) {
+	if pageCursor != "" {
+		cursorTime, cursorID, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		filtered := follows[:0]
+		for _, follow := range follows {
+			if cursor.Before(follow.CreatedAt, key(follow), cursorTime, cursorID) {
+				filtered = append(filtered, follow)
+			}
+		}
+		follows = filtered
+	}
+
+	sort.Slice(follows, func(i, j int) bool {
+		return cursor.Before(follows[j].CreatedAt, key(follows[j]), follows[i].CreatedAt, key(follows[i]))
+	})
+
+	if len(follows) <= limit {
+		return follows, "", nil
+	}
+
+	follows = follows[:limit]
+	last := follows[len(follows)-1]
+
+	return follows, cursor.Encode(last.CreatedAt, key(last)), nil
+}
+
+type FollowRequestRepository struct {
+	mu       sync.RWMutex
+	requests map[string]map[string]domain.FollowRequest
+}
+
+func NewFollowRequestRepository() *FollowRequestRepository {
+	return &FollowRequestRepository{requests: make(map[string]map[string]domain.FollowRequest)}
+}
+
+func (r *FollowRequestRepository) CreateFollowRequest(ctx context.Context, requesterID, targetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.requests[targetID] == nil {
+		r.requests[targetID] = make(map[string]domain.FollowRequest)
+	}
+
+	if _, ok := r.requests[targetID][requesterID]; ok {
+		return nil
+	}
+
+	r.requests[targetID][requesterID] = domain.FollowRequest{
+		RequesterID: requesterID,
+		TargetID:    targetID,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	return nil
+}
+
+func (r *FollowRequestRepository) HasFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.requests[targetID][requesterID]
+	return ok, nil
+}
+
+func (r *FollowRequestRepository) GetFollowRequests(ctx context.Context, targetID, pageCursor string, limit int) ([]domain.FollowRequest, string, error) {
+	var cursorTime time.Time
+	var cursorID string
+
+	if pageCursor != "" {
+		var err error
+		if cursorTime, cursorID, err = cursor.Decode(pageCursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	r.mu.RLock()
+	requests := make([]domain.FollowRequest, 0, len(r.requests[targetID]))
+	for _, request := range r.requests[targetID] {
+		if pageCursor == "" || cursor.Before(request.CreatedAt, request.RequesterID, cursorTime, cursorID) {
+			requests = append(requests, request)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(requests, func(i, j int) bool {
+		return cursor.Before(requests[j].CreatedAt, requests[j].RequesterID, requests[i].CreatedAt, requests[i].RequesterID)
+	})
+
+	if len(requests) <= limit {
+		return requests, "", nil
+	}
+
+	requests = requests[:limit]
+	last := requests[len(requests)-1]
+
+	return requests, cursor.Encode(last.CreatedAt, last.RequesterID), nil
+}
+
+func (r *FollowRequestRepository) DeleteFollowRequest(ctx context.Context, requesterID, targetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.requests[targetID][requesterID]; !ok {
+		return response.ErrFollowRequestNotFound
+	}
+
+	delete(r.requests[targetID], requesterID)
+	return nil
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type lease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+// LeaseRepository is enough for a single replica, every holder in the process competes for the same map
+type LeaseRepository struct {
+	mu     sync.Mutex
+	leases map[string]lease
+}
+
+func NewLeaseRepository() *LeaseRepository {
+	return &LeaseRepository{leases: make(map[string]lease)}
+}
+
+func (r *LeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	current, ok := r.leases[name]
+	if ok && current.holder != holder && current.expiresAt.After(now) {
+		return false, nil
+	}
+
+	r.leases[name] = lease{holder: holder, expiresAt: now.Add(ttl)}
+
+	return true, nil
+}
+
+func (r *LeaseRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.leases[name].holder == holder {
+		delete(r.leases, name)
+	}
+
+	return nil
+}
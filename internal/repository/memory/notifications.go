@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"sync"
+)
+
+type NotificationRepository struct {
+	mu            sync.RWMutex
+	notifications map[string][]domain.Notification
+}
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{notifications: make(map[string][]domain.Notification)}
+}
+
+func (r *NotificationRepository) CreateNotifications(ctx context.Context, notifications []domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, notification := range notifications {
+		r.notifications[notification.UserID] = append(r.notifications[notification.UserID], notification)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) GetNotifications(ctx context.Context, userID string) ([]domain.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]domain.Notification(nil), r.notifications[userID]...), nil
+}
+
+func (r *NotificationRepository) MarkNotificationAsRead(ctx context.Context, userID, notificationID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.notifications[userID] {
+		if r.notifications[userID][i].NotificationID == notificationID {
+			r.notifications[userID][i].Read = true
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *NotificationRepository) ReadAllNotifications(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.notifications[userID] {
+		r.notifications[userID][i].Read = true
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"sync"
+	"time"
+)
+
+type identityKey struct {
+	provider string
+	subject  string
+}
+
+type OIDCRepository struct {
+	mu         sync.Mutex
+	states     map[string]domain.OIDCState
+	identities map[identityKey]domain.Identity
+}
+
+func NewOIDCRepository() *OIDCRepository {
+	return &OIDCRepository{
+		states:     make(map[string]domain.OIDCState),
+		identities: make(map[identityKey]domain.Identity),
+	}
+}
+
+func (r *OIDCRepository) CreateState(ctx context.Context, state domain.OIDCState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	for key, existing := range r.states {
+		if !existing.ExpiresAt.After(now) {
+			delete(r.states, key)
+		}
+	}
+
+	r.states[state.State] = state
+
+	return nil
+}
+
+func (r *OIDCRepository) ConsumeState(ctx context.Context, state string, now time.Time) (domain.OIDCState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.states[state]
+	if !ok {
+		return domain.OIDCState{}, response.ErrInvalidOIDCState
+	}
+
+	delete(r.states, state)
+
+	if !stored.ExpiresAt.After(now) {
+		return domain.OIDCState{}, response.ErrInvalidOIDCState
+	}
+
+	return stored, nil
+}
+
+func (r *OIDCRepository) GetIdentity(ctx context.Context, provider, subject string) (domain.Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	identity, ok := r.identities[identityKey{provider: provider, subject: subject}]
+	if !ok {
+		return domain.Identity{}, response.ErrUserNotFound
+	}
+
+	return identity, nil
+}
+
+func (r *OIDCRepository) CreateIdentity(ctx context.Context, identity domain.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := identityKey{provider: identity.Provider, subject: identity.Subject}
+
+	if _, ok := r.identities[key]; ok {
+		return response.ErrIdentityAlreadyLinked
+	}
+
+	for _, existing := range r.identities {
+		if existing.UserID == identity.UserID && existing.Provider == identity.Provider {
+			return response.ErrIdentityAlreadyLinked
+		}
+	}
+
+	identity.CreatedAt = time.Now().UTC()
+	r.identities[key] = identity
+
+	return nil
+}
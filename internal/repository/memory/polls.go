@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"sort"
+	"sync"
+	"time"
+)
+
+type poll struct {
+	domain.PollRecord
+	voters      map[string]int
+	notified    bool
+	lockedUntil time.Time
+}
+
+type PollRepository struct {
+	mu    sync.RWMutex
+	polls map[string]*poll
+}
+
+func NewPollRepository() *PollRepository {
+	return &PollRepository{polls: make(map[string]*poll)}
+}
+
+func (r *PollRepository) CreatePoll(ctx context.Context, record domain.PollRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record.Votes = make([]int, len(record.Options))
+	record.CreatedAt = time.Now().UTC()
+
+	r.polls[record.TweetID] = &poll{PollRecord: record, voters: make(map[string]int)}
+
+	return nil
+}
+
+func (r *PollRepository) GetPolls(ctx context.Context, tweetIDs []string) (map[string]domain.PollRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]domain.PollRecord, len(tweetIDs))
+
+	for _, tweetID := range tweetIDs {
+		if p, ok := r.polls[tweetID]; ok {
+			record := p.PollRecord
+			record.Votes = append([]int(nil), p.Votes...)
+			result[tweetID] = record
+		}
+	}
+
+	return result, nil
+}
+
+func (r *PollRepository) GetVotes(ctx context.Context, userID string, tweetIDs []string) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]int)
+
+	for _, tweetID := range tweetIDs {
+		p, ok := r.polls[tweetID]
+		if !ok {
+			continue
+		}
+		if option, ok := p.voters[userID]; ok {
+			result[tweetID] = option
+		}
+	}
+
+	return result, nil
+}
+
+func (r *PollRepository) Vote(ctx context.Context, tweetID, userID string, option int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.polls[tweetID]
+	if !ok {
+		return response.ErrPollNotFound
+	}
+
+	if !p.ClosesAt.After(time.Now()) {
+		return response.ErrPollClosed
+	}
+
+	if _, ok := p.voters[userID]; ok {
+		return response.ErrAlreadyVoted
+	}
+
+	if option < 0 || option >= len(p.Options) {
+		return response.ErrInvalidPollOption
+	}
+
+	p.voters[userID] = option
+	p.Votes[option]++
+
+	return nil
+}
+
+func (r *PollRepository) GetVoters(ctx context.Context, tweetID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.polls[tweetID]
+	if !ok {
+		return nil, response.ErrPollNotFound
+	}
+
+	voters := make([]string, 0, len(p.voters))
+	for userID := range p.voters {
+		voters = append(voters, userID)
+	}
+	sort.Strings(voters)
+
+	return voters, nil
+}
+
+func (r *PollRepository) ClaimClosedPolls(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.PollRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]domain.PollRecord, 0)
+
+	for _, p := range r.polls {
+		if len(result) == limit {
+			break
+		}
+		if p.notified || p.ClosesAt.After(now) || p.lockedUntil.After(now) {
+			continue
+		}
+		p.lockedUntil = now.Add(claimTTL)
+		result = append(result, p.PollRecord)
+	}
+
+	return result, nil
+}
+
+func (r *PollRepository) MarkPollNotified(ctx context.Context, tweetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.polls[tweetID]; ok {
+		p.notified = true
+	}
+
+	return nil
+}
+
+func (r *PollRepository) DeletePoll(ctx context.Context, tweetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.polls, tweetID)
+	return nil
+}
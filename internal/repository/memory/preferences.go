@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"sync"
+	"time"
+)
+
+type NotificationPreferencesRepository struct {
+	mu          sync.RWMutex
+	preferences map[string]domain.NotificationPreferences
+}
+
+func NewNotificationPreferencesRepository() *NotificationPreferencesRepository {
+	return &NotificationPreferencesRepository{preferences: make(map[string]domain.NotificationPreferences)}
+}
+
+func (r *NotificationPreferencesRepository) GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	preferences, ok := r.preferences[userID]
+	if !ok {
+		return domain.NotificationPreferences{}, false, nil
+	}
+
+	return copyPreferences(preferences), true, nil
+}
+
+func (r *NotificationPreferencesRepository) UpdatePreferences(ctx context.Context, preferences domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	preferences.UpdatedAt = time.Now().UTC()
+	r.preferences[preferences.UserID] = copyPreferences(preferences)
+
+	return preferences, nil
+}
+
+func copyPreferences(preferences domain.NotificationPreferences) domain.NotificationPreferences {
+	types := make(map[string]domain.ChannelPreferences, len(preferences.Types))
+	for notificationType, channels := range preferences.Types {
+		types[notificationType] = channels
+	}
+	preferences.Types = types
+
+	return preferences
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"sync"
+	"time"
+)
+
+type presenceSession struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// PresenceRepository is enough for a single replica, other replicas would not see its sessions
+type PresenceRepository struct {
+	mu       sync.Mutex
+	sessions map[string]presenceSession
+	lastSeen map[string]time.Time
+}
+
+func NewPresenceRepository() *PresenceRepository {
+	return &PresenceRepository{sessions: make(map[string]presenceSession), lastSeen: make(map[string]time.Time)}
+}
+
+func (r *PresenceRepository) TouchSession(ctx context.Context, sessionID, userID string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	for id, session := range r.sessions {
+		if session.expiresAt.Before(now) {
+			delete(r.sessions, id)
+		}
+	}
+
+	r.sessions[sessionID] = presenceSession{userID: userID, expiresAt: now.Add(ttl)}
+	r.lastSeen[userID] = now
+
+	return nil
+}
+
+func (r *PresenceRepository) EndSession(ctx context.Context, sessionID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions, sessionID)
+	r.lastSeen[userID] = time.Now().UTC()
+
+	return nil
+}
+
+func (r *PresenceRepository) GetPresence(ctx context.Context, userID string) (domain.Presence, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	presence := domain.Presence{UserID: userID, Status: domain.PresenceOffline}
+
+	if lastSeen, ok := r.lastSeen[userID]; ok {
+		presence.LastSeenAt = &lastSeen
+	}
+
+	now := time.Now()
+
+	for _, session := range r.sessions {
+		if session.userID == userID && session.expiresAt.After(now) {
+			presence.Status = domain.PresenceOnline
+			break
+		}
+	}
+
+	return presence, nil
+}
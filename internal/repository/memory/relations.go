@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"sync"
+)
+
+type RelationRepository struct {
+	mu       sync.RWMutex
+	outgoing map[domain.RelationType]map[string]map[string]struct{}
+	incoming map[domain.RelationType]map[string]map[string]struct{}
+}
+
+func NewRelationRepository() *RelationRepository {
+	return &RelationRepository{
+		outgoing: make(map[domain.RelationType]map[string]map[string]struct{}),
+		incoming: make(map[domain.RelationType]map[string]map[string]struct{}),
+	}
+}
+
+func (r *RelationRepository) AddRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	addEdge(r.outgoing, relation, userID, targetID)
+	addEdge(r.incoming, relation, targetID, userID)
+
+	return nil
+}
+
+func (r *RelationRepository) DeleteRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.outgoing[relation][userID], targetID)
+	delete(r.incoming[relation][targetID], userID)
+
+	return nil
+}
+
+func (r *RelationRepository) HasRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.outgoing[relation][userID][targetID]
+	return ok, nil
+}
+
+func (r *RelationRepository) GetRelated(ctx context.Context, userID string, relation domain.RelationType) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return userIDs(r.outgoing[relation][userID]), nil
+}
+
+func (r *RelationRepository) GetRelatedBy(ctx context.Context, targetID string, relation domain.RelationType) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return userIDs(r.incoming[relation][targetID]), nil
+}
+
+func addEdge(index map[domain.RelationType]map[string]map[string]struct{}, relation domain.RelationType, from, to string) {
+	if index[relation] == nil {
+		index[relation] = make(map[string]map[string]struct{})
+	}
+
+	if index[relation][from] == nil {
+		index[relation][from] = make(map[string]struct{})
+	}
+
+	index[relation][from][to] = struct{}{}
+}
+
+func userIDs(set map[string]struct{}) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+	return result
+}
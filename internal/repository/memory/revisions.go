@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"sync"
+)
+
+type TweetRevisionRepository struct {
+	mu        sync.RWMutex
+	revisions map[string][]domain.TweetRevision
+}
+
+func NewTweetRevisionRepository() *TweetRevisionRepository {
+	return &TweetRevisionRepository{revisions: make(map[string][]domain.TweetRevision)}
+}
+
+func (r *TweetRevisionRepository) AddRevision(ctx context.Context, revision domain.TweetRevision) (domain.TweetRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revisions := r.revisions[revision.TweetID]
+
+	revision.Revision = 1
+	if len(revisions) > 0 {
+		revision.Revision = revisions[len(revisions)-1].Revision + 1
+	}
+
+	r.revisions[revision.TweetID] = append(revisions, revision)
+
+	return revision, nil
+}
+
+func (r *TweetRevisionRepository) DeleteRevision(ctx context.Context, tweetID string, revision int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revisions := r.revisions[tweetID]
+
+	for i := range revisions {
+		if revisions[i].Revision == revision {
+			r.revisions[tweetID] = append(revisions[:i:i], revisions[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (r *TweetRevisionRepository) GetRevisions(ctx context.Context, tweetID string) ([]domain.TweetRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append(make([]domain.TweetRevision, 0), r.revisions[tweetID]...), nil
+}
+
+func (r *TweetRevisionRepository) GetEditStats(ctx context.Context, tweetIDs []string) (map[string]domain.EditStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]domain.EditStats, len(tweetIDs))
+
+	for _, tweetID := range tweetIDs {
+		revisions := r.revisions[tweetID]
+		if len(revisions) == 0 {
+			continue
+		}
+
+		result[tweetID] = domain.EditStats{
+			Count:    len(revisions),
+			EditedAt: revisions[len(revisions)-1].ReplacedAt,
+		}
+	}
+
+	return result, nil
+}
+
+func (r *TweetRevisionRepository) DeleteRevisions(ctx context.Context, tweetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.revisions, tweetID)
+	return nil
+}
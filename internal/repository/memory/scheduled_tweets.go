@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"sort"
+	"sync"
+	"time"
+)
+
+type scheduledTweet struct {
+	domain.ScheduledTweet
+	lockedUntil time.Time
+}
+
+type ScheduledTweetRepository struct {
+	mu     sync.Mutex
+	tweets map[string]*scheduledTweet
+}
+
+func NewScheduledTweetRepository() *ScheduledTweetRepository {
+	return &ScheduledTweetRepository{tweets: make(map[string]*scheduledTweet)}
+}
+
+func (r *ScheduledTweetRepository) CreateScheduledTweet(ctx context.Context, tweet domain.ScheduledTweet) (domain.ScheduledTweet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	tweet.Status = domain.ScheduledTweetPending
+	tweet.CreatedAt = now
+	tweet.UpdatedAt = now
+
+	r.tweets[tweet.ScheduledID] = &scheduledTweet{ScheduledTweet: tweet}
+
+	return tweet, nil
+}
+
+func (r *ScheduledTweetRepository) GetScheduledTweet(ctx context.Context, userID, scheduledID string) (domain.ScheduledTweet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tweet, ok := r.tweets[scheduledID]
+	if !ok || tweet.UserID != userID {
+		return domain.ScheduledTweet{}, response.ErrScheduledTweetNotFound
+	}
+
+	return tweet.ScheduledTweet, nil
+}
+
+func (r *ScheduledTweetRepository) GetScheduledTweets(ctx context.Context, userID, pageCursor string, limit int) ([]domain.ScheduledTweet, string, error) {
+	var cursorTime time.Time
+	var cursorID string
+
+	if pageCursor != "" {
+		var err error
+		if cursorTime, cursorID, err = cursor.Decode(pageCursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	r.mu.Lock()
+	tweets := make([]domain.ScheduledTweet, 0)
+	for _, tweet := range r.tweets {
+		if tweet.UserID != userID {
+			continue
+		}
+		if pageCursor == "" || cursor.Before(tweet.CreatedAt, tweet.ScheduledID, cursorTime, cursorID) {
+			tweets = append(tweets, tweet.ScheduledTweet)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(tweets, func(i, j int) bool {
+		return cursor.Before(tweets[j].CreatedAt, tweets[j].ScheduledID, tweets[i].CreatedAt, tweets[i].ScheduledID)
+	})
+
+	if len(tweets) <= limit {
+		return tweets, "", nil
+	}
+
+	tweets = tweets[:limit]
+	last := tweets[len(tweets)-1]
+
+	return tweets, cursor.Encode(last.CreatedAt, last.ScheduledID), nil
+}
+
+func (r *ScheduledTweetRepository) UpdateScheduledTweet(ctx context.Context, tweet domain.ScheduledTweet) (domain.ScheduledTweet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.pending(tweet.UserID, tweet.ScheduledID)
+	if err != nil {
+		return domain.ScheduledTweet{}, err
+	}
+
+	current.Text = tweet.Text
+	current.MediaID = tweet.MediaID
+	current.PublishAt = tweet.PublishAt
+	current.UpdatedAt = time.Now().UTC()
+
+	return current.ScheduledTweet, nil
+}
+
+func (r *ScheduledTweetRepository) DeleteScheduledTweet(ctx context.Context, userID, scheduledID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.pending(userID, scheduledID); err != nil {
+		return err
+	}
+
+	delete(r.tweets, scheduledID)
+
+	return nil
+}
+
+func (r *ScheduledTweetRepository) ClaimDueTweets(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.ScheduledTweet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := make([]*scheduledTweet, 0)
+
+	for _, tweet := range r.tweets {
+		if tweet.Status != domain.ScheduledTweetPending && tweet.Status != domain.ScheduledTweetPublishing {
+			continue
+		}
+		if tweet.PublishAt.After(now) || tweet.lockedUntil.After(now) {
+			continue
+		}
+		due = append(due, tweet)
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].PublishAt.Before(due[j].PublishAt)
+	})
+
+	result := make([]domain.ScheduledTweet, 0, min(len(due), limit))
+
+	for _, tweet := range due[:min(len(due), limit)] {
+		tweet.Status = domain.ScheduledTweetPublishing
+		tweet.Attempts++
+		tweet.lockedUntil = now.Add(claimTTL)
+		tweet.UpdatedAt = now.UTC()
+		result = append(result, tweet.ScheduledTweet)
+	}
+
+	return result, nil
+}
+
+func (r *ScheduledTweetRepository) MarkPublished(ctx context.Context, scheduledID, tweetID string) error {
+	return r.update(scheduledID, func(tweet *scheduledTweet) {
+		tweet.Status = domain.ScheduledTweetPublished
+		tweet.TweetID = tweetID
+		tweet.LastError = ""
+	})
+}
+
+func (r *ScheduledTweetRepository) RetryLater(ctx context.Context, scheduledID, lastError string, retryAt time.Time) error {
+	return r.update(scheduledID, func(tweet *scheduledTweet) {
+		tweet.Status = domain.ScheduledTweetPending
+		tweet.LastError = lastError
+		tweet.lockedUntil = retryAt
+	})
+}
+
+func (r *ScheduledTweetRepository) MarkFailed(ctx context.Context, scheduledID, lastError string) error {
+	return r.update(scheduledID, func(tweet *scheduledTweet) {
+		tweet.Status = domain.ScheduledTweetFailed
+		tweet.LastError = lastError
+	})
+}
+
+func (r *ScheduledTweetRepository) update(scheduledID string, apply func(tweet *scheduledTweet)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tweet, ok := r.tweets[scheduledID]
+	if !ok {
+		return response.ErrScheduledTweetNotFound
+	}
+
+	apply(tweet)
+	tweet.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (r *ScheduledTweetRepository) pending(userID, scheduledID string) (*scheduledTweet, error) {
+	tweet, ok := r.tweets[scheduledID]
+	if !ok || tweet.UserID != userID {
+		return nil, response.ErrScheduledTweetNotFound
+	}
+
+	if tweet.Status != domain.ScheduledTweetPending {
+		return nil, response.ErrScheduledTweetLocked
+	}
+
+	return tweet, nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type ThreadRepository struct {
+	mu      sync.RWMutex
+	links   map[string]domain.ThreadLink
+	replies map[string][]string
+}
+
+func NewThreadRepository() *ThreadRepository {
+	return &ThreadRepository{links: make(map[string]domain.ThreadLink), replies: make(map[string][]string)}
+}
+
+func (r *ThreadRepository) AddReply(ctx context.Context, link domain.ThreadLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now().UTC()
+	}
+
+	r.links[link.TweetID] = link
+	r.replies[link.InReplyToTweetID] = append(r.replies[link.InReplyToTweetID], link.TweetID)
+
+	return nil
+}
+
+func (r *ThreadRepository) GetLinks(ctx context.Context, tweetIDs []string) (map[string]domain.ThreadLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]domain.ThreadLink)
+
+	for _, tweetID := range tweetIDs {
+		if link, ok := r.links[tweetID]; ok {
+			result[tweetID] = link
+		}
+	}
+
+	return result, nil
+}
+
+func (r *ThreadRepository) GetReplies(ctx context.Context, tweetID string) ([]domain.ThreadLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	replies := make([]domain.ThreadLink, 0, len(r.replies[tweetID]))
+
+	for _, replyID := range r.replies[tweetID] {
+		if link, ok := r.links[replyID]; ok {
+			replies = append(replies, link)
+		}
+	}
+
+	sort.SliceStable(replies, func(i, j int) bool {
+		return replies[i].CreatedAt.Before(replies[j].CreatedAt)
+	})
+
+	return replies, nil
+}
+
+func (r *ThreadRepository) DeleteLink(ctx context.Context, tweetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link, ok := r.links[tweetID]
+	if !ok {
+		return nil
+	}
+
+	delete(r.links, tweetID)
+
+	replies := r.replies[link.InReplyToTweetID]
+	for i := range replies {
+		if replies[i] == tweetID {
+			r.replies[link.InReplyToTweetID] = append(replies[:i:i], replies[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
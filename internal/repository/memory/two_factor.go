@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"sync"
+	"time"
+)
+
+type TwoFactorRepository struct {
+	mu         sync.Mutex
+	settings   map[string]domain.TwoFactor
+	codes      map[string]map[string]bool
+	challenges map[string]domain.TwoFactorChallenge
+}
+
+func NewTwoFactorRepository() *TwoFactorRepository {
+	return &TwoFactorRepository{
+		settings:   make(map[string]domain.TwoFactor),
+		codes:      make(map[string]map[string]bool),
+		challenges: make(map[string]domain.TwoFactorChallenge),
+	}
+}
+
+func (r *TwoFactorRepository) GetTwoFactor(ctx context.Context, userID string) (domain.TwoFactor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, ok := r.settings[userID]
+	if !ok {
+		return domain.TwoFactor{}, response.ErrTwoFactorSetupNotFound
+	}
+
+	return settings, nil
+}
+
+func (r *TwoFactorRepository) SaveSetup(ctx context.Context, userID, secret string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.settings[userID].Enabled {
+		return response.ErrTwoFactorEnabled
+	}
+
+	r.settings[userID] = domain.TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) EnableTwoFactor(ctx context.Context, userID string, step int64, codeHashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, ok := r.settings[userID]
+	if !ok {
+		return response.ErrTwoFactorSetupNotFound
+	}
+
+	if settings.Enabled {
+		return response.ErrTwoFactorEnabled
+	}
+
+	settings.Enabled = true
+	settings.LastUsedStep = step
+	r.settings[userID] = settings
+
+	r.replaceCodes(userID, codeHashes)
+
+	return nil
+}
+
+func (r *TwoFactorRepository) DeleteTwoFactor(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.settings[userID]; !ok {
+		return response.ErrTwoFactorNotEnabled
+	}
+
+	delete(r.settings, userID)
+	delete(r.codes, userID)
+
+	return nil
+}
+
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID string, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, ok := r.settings[userID]
+	if !ok || settings.LastUsedStep >= step {
+		return response.ErrInvalidTwoFactorCode
+	}
+
+	settings.LastUsedStep = step
+	r.settings[userID] = settings
+
+	return nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.settings[userID].Enabled {
+		return response.ErrTwoFactorNotEnabled
+	}
+
+	r.replaceCodes(userID, codeHashes)
+
+	return nil
+}
+
+func (r *TwoFactorRepository) replaceCodes(userID string, codeHashes []string) {
+	codes := make(map[string]bool, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes[hash] = false
+	}
+
+	r.codes[userID] = codes
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used, ok := r.codes[userID][codeHash]
+	if !ok || used {
+		return response.ErrInvalidTwoFactorCode
+	}
+
+	r.codes[userID][codeHash] = true
+
+	return nil
+}
+
+func (r *TwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+
+	for _, used := range r.codes[userID] {
+		if !used {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, challenge domain.TwoFactorChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	for hash, existing := range r.challenges {
+		if !existing.ExpiresAt.After(now) {
+			delete(r.challenges, hash)
+		}
+	}
+
+	r.challenges[challenge.TokenHash] = challenge
+
+	return nil
+}
+
+func (r *TwoFactorRepository) AttemptChallenge(ctx context.Context, tokenHash string, now time.Time, maxAttempts int) (domain.TwoFactorChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	challenge, ok := r.challenges[tokenHash]
+	if !ok || !challenge.ExpiresAt.After(now) || challenge.Attempts >= maxAttempts {
+		return domain.TwoFactorChallenge{}, response.ErrInvalidChallenge
+	}
+
+	challenge.Attempts++
+	r.challenges[tokenHash] = challenge
+
+	return challenge, nil
+}
+
+func (r *TwoFactorRepository) DeleteChallenge(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.challenges, tokenHash)
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+type webhookDelivery struct {
+	domain.WebhookDelivery
+	lockedUntil time.Time
+}
+
+type WebhookRepository struct {
+	mu         sync.Mutex
+	webhooks   map[string]domain.Webhook
+	deliveries map[string]*webhookDelivery
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		webhooks:   make(map[string]domain.Webhook),
+		deliveries: make(map[string]*webhookDelivery),
+	}
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	webhook.Events = slices.Clone(webhook.Events)
+
+	r.webhooks[webhook.WebhookID] = webhook
+
+	return webhook, nil
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, webhookID string) (domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[webhookID]
+	if !ok {
+		return domain.Webhook{}, response.ErrWebhookNotFound
+	}
+
+	webhook.Events = slices.Clone(webhook.Events)
+
+	return webhook, nil
+}
+
+func (r *WebhookRepository) GetWebhooks(ctx context.Context, userID string) ([]domain.Webhook, error) {
+	return r.filter(func(webhook domain.Webhook) bool {
+		return webhook.UserID == userID
+	}), nil
+}
+
+func (r *WebhookRepository) GetSubscribedWebhooks(ctx context.Context, userID, event string) ([]domain.Webhook, error) {
+	return r.filter(func(webhook domain.Webhook) bool {
+		return webhook.UserID == userID && webhook.Enabled && slices.Contains(webhook.Events, event)
+	}), nil
+}
+
+func (r *WebhookRepository) filter(match func(domain.Webhook) bool) []domain.Webhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]domain.Webhook, 0)
+
+	for _, webhook := range r.webhooks {
+		if match(webhook) {
+			webhook.Events = slices.Clone(webhook.Events)
+			result = append(result, webhook)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result
+}
+
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.webhooks[webhook.WebhookID]
+	if !ok || current.UserID != webhook.UserID {
+		return domain.Webhook{}, response.ErrWebhookNotFound
+	}
+
+	current.URL = webhook.URL
+	current.Events = slices.Clone(webhook.Events)
+	current.Enabled = webhook.Enabled
+	current.Failures = webhook.Failures
+	current.UpdatedAt = time.Now().UTC()
+
+	r.webhooks[webhook.WebhookID] = current
+
+	return current, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[webhookID]
+	if !ok || webhook.UserID != userID {
+		return response.ErrWebhookNotFound
+	}
+
+	delete(r.webhooks, webhookID)
+
+	for id, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID {
+			delete(r.deliveries, id)
+		}
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, webhookID string, succeeded bool, disableAfter int) (domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[webhookID]
+	if !ok {
+		return domain.Webhook{}, response.ErrWebhookNotFound
+	}
+
+	if succeeded {
+		webhook.Failures = 0
+	} else {
+		webhook.Failures++
+		if webhook.Failures >= disableAfter {
+			webhook.Enabled = false
+		}
+	}
+
+	webhook.UpdatedAt = time.Now().UTC()
+	r.webhooks[webhookID] = webhook
+
+	return webhook, nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[delivery.WebhookID]; !ok {
+		return domain.WebhookDelivery{}, response.ErrWebhookNotFound
+	}
+
+	now := time.Now().UTC()
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	r.deliveries[delivery.DeliveryID] = &webhookDelivery{WebhookDelivery: delivery}
+
+	return delivery, nil
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := make([]*webhookDelivery, 0)
+
+	for _, delivery := range r.deliveries {
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.lockedUntil.After(now) {
+			continue
+		}
+		due = append(due, delivery)
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+
+	result := make([]domain.WebhookDelivery, 0, min(len(due), limit))
+
+	for _, delivery := range due[:min(len(due), limit)] {
+		delivery.Attempts++
+		delivery.lockedUntil = now.Add(claimTTL)
+		delivery.UpdatedAt = now.UTC()
+		result = append(result, delivery.WebhookDelivery)
+	}
+
+	return result, nil
+}
+
+func (r *WebhookRepository) RetryDelivery(ctx context.Context, deliveryID string, responseStatus int, lastError string, retryAt time.Time) error {
+	return r.update(deliveryID, func(delivery *webhookDelivery) {
+		delivery.ResponseStatus = responseStatus
+		delivery.LastError = lastError
+		delivery.lockedUntil = retryAt
+	})
+}
+
+func (r *WebhookRepository) FinishDelivery(ctx context.Context, deliveryID string, status domain.WebhookDeliveryStatus, responseStatus int, lastError string) error {
+	return r.update(deliveryID, func(delivery *webhookDelivery) {
+		delivery.Status = status
+		delivery.ResponseStatus = responseStatus
+		delivery.LastError = lastError
+	})
+}
+
+func (r *WebhookRepository) update(deliveryID string, apply func(delivery *webhookDelivery)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery, ok := r.deliveries[deliveryID]
+	if !ok {
+		return response.ErrWebhookNotFound
+	}
+
+	apply(delivery)
+	delivery.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID, pageCursor string, limit int) ([]domain.WebhookDelivery, string, error) {
+	r.mu.Lock()
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery.WebhookDelivery)
+		}
+	}
+	r.mu.Unlock()
+
+	if pageCursor != "" {
+		cursorTime, cursorID, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		filtered := deliveries[:0]
+		for _, delivery := range deliveries {
+			if cursor.Before(delivery.CreatedAt, delivery.DeliveryID, cursorTime, cursorID) {
+				filtered = append(filtered, delivery)
+			}
+		}
+		deliveries = filtered
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return cursor.Before(deliveries[j].CreatedAt, deliveries[j].DeliveryID, deliveries[i].CreatedAt, deliveries[i].DeliveryID)
+	})
+
+	if len(deliveries) <= limit {
+		return deliveries, "", nil
+	}
+
+	deliveries = deliveries[:limit]
+	last := deliveries[len(deliveries)-1]
+
+	return deliveries, cursor.Encode(last.CreatedAt, last.DeliveryID), nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type AccountSettingsRepository struct {
+	db *sqlx.DB
+}
+
+func NewAccountSettingsRepository(db *sqlx.DB) *AccountSettingsRepository {
+	return &AccountSettingsRepository{db: db}
+}
+
+func (r *AccountSettingsRepository) GetSettings(ctx context.Context, userIDs []string) (map[string]domain.AccountSettings, error) {
+	result := make(map[string]domain.AccountSettings, len(userIDs))
+
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT user_id, is_private, allow_messages, updated_at FROM account_settings WHERE user_id IN (?)`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []domain.AccountSettings
+
+	if err := r.db.SelectContext(ctx, &settings, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, item := range settings {
+		result[item.UserID] = item
+	}
+
+	return result, nil
+}
+
+func (r *AccountSettingsRepository) UpdateSettings(ctx context.Context, settings domain.AccountSettings) (domain.AccountSettings, error) {
+	q := `INSERT INTO account_settings (user_id, is_private, allow_messages) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET is_private = excluded.is_private, allow_messages = excluded.allow_messages, updated_at = now()
+		RETURNING user_id, is_private, allow_messages, updated_at`
+
+	var updated domain.AccountSettings
+
+	if err := r.db.QueryRowxContext(ctx, q, settings.UserID, settings.IsPrivate, settings.AllowMessages).StructScan(&updated); err != nil {
+		return domain.AccountSettings{}, err
+	}
+
+	return updated, nil
+}
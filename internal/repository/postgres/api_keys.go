@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const apiKeyColumns = `id, user_id, name, prefix, scopes, last_used_at, revoked_at, created_at`
+
+type apiKeyRow struct {
+	KeyID      string     `db:"id"`
+	UserID     string     `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	Scopes     string     `db:"scopes"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+func (k apiKeyRow) toDomain() (domain.APIKey, error) {
+	var scopes []string
+	if err := json.Unmarshal([]byte(k.Scopes), &scopes); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	return domain.APIKey{
+		KeyID:      k.KeyID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}, nil
+}
+
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key domain.APIKey, hash string) (domain.APIKey, error) {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	q := `INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns
+
+	return r.getOne(ctx, q, key.KeyID, key.UserID, key.Name, key.Prefix, hash, string(scopes))
+}
+
+func (r *APIKeyRepository) GetAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	q := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = $1 ORDER BY created_at`
+
+	var rows []apiKeyRow
+
+	if err := r.db.SelectContext(ctx, &rows, q, userID); err != nil {
+		return nil, err
+	}
+
+	keys := make([]domain.APIKey, 0, len(rows))
+
+	for _, row := range rows {
+		key, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) CountActiveAPIKeys(ctx context.Context, userID string) (int, error) {
+	q := `SELECT count(*) FROM api_keys WHERE user_id = $1 AND revoked_at IS NULL`
+
+	var count int
+
+	err := r.db.GetContext(ctx, &count, q, userID)
+
+	return count, err
+}
+
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	q := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`
+
+	return r.getOne(ctx, q, hash)
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	q := `UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, q, keyID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return response.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, keyID string, usedAt time.Time) error {
+	q := `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`
+
+	_, err := r.db.ExecContext(ctx, q, usedAt, keyID)
+
+	return err
+}
+
+func (r *APIKeyRepository) getOne(ctx context.Context, q string, args ...any) (domain.APIKey, error) {
+	var row apiKeyRow
+
+	err := r.db.QueryRowxContext(ctx, q, args...).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIKey{}, response.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	return row.toDomain()
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/jmoiron/sqlx"
+)
+
+type BookmarkRepository struct {
+	db *sqlx.DB
+}
+
+func NewBookmarkRepository(db *sqlx.DB) *BookmarkRepository {
+	return &BookmarkRepository{db: db}
+}
+
+func (r *BookmarkRepository) AddBookmark(ctx context.Context, userID, tweetID string) error {
+	q := `INSERT INTO bookmarks (user_id, tweet_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, q, userID, tweetID)
+	return err
+}
+
+func (r *BookmarkRepository) DeleteBookmark(ctx context.Context, userID, tweetID string) error {
+	q := `DELETE FROM bookmarks WHERE user_id = $1 AND tweet_id = $2`
+
+	_, err := r.db.ExecContext(ctx, q, userID, tweetID)
+	return err
+}
+
+func (r *BookmarkRepository) GetBookmarks(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Bookmark, string, error) {
+	var bookmarks []domain.Bookmark
+
+	if pageCursor == "" {
+		q := `SELECT user_id, tweet_id, created_at FROM bookmarks WHERE user_id = $1
+			ORDER BY created_at DESC, tweet_id DESC LIMIT $2`
+
+		if err := r.db.SelectContext(ctx, &bookmarks, q, userID, limit); err != nil {
+			return nil, "", err
+		}
+	} else {
+		createdAt, tweetID, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		q := `SELECT user_id, tweet_id, created_at FROM bookmarks WHERE user_id = $1 AND (created_at, tweet_id) < ($2, $3)
+			ORDER BY created_at DESC, tweet_id DESC LIMIT $4`
+
+		if err := r.db.SelectContext(ctx, &bookmarks, q, userID, createdAt, tweetID, limit); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var nextCursor string
+	if len(bookmarks) == limit {
+		last := bookmarks[len(bookmarks)-1]
+		nextCursor = cursor.Encode(last.CreatedAt, last.TweetID)
+	}
+
+	return bookmarks, nextCursor, nil
+}
+
+func (r *BookmarkRepository) GetBookmarkedTweets(ctx context.Context, userID string, tweetIDs []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(tweetIDs))
+
+	if len(tweetIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT tweet_id FROM bookmarks WHERE user_id = ? AND tweet_id IN (?)`, userID, tweetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarked []string
+
+	if err := r.db.SelectContext(ctx, &bookmarked, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, tweetID := range bookmarked {
+		result[tweetID] = true
+	}
+
+	return result, nil
+}
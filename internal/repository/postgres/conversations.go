@@ -0,0 +1,295 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const conversationColumns = `c.conversation_id, c.creator_id, c.is_group, c.created_at, c.last_message_at`
+
+type conversationRow struct {
+	ConversationID string    `db:"conversation_id"`
+	CreatorID      string    `db:"creator_id"`
+	IsGroup        bool      `db:"is_group"`
+	CreatedAt      time.Time `db:"created_at"`
+	LastMessageAt  time.Time `db:"last_message_at"`
+}
+
+type memberRow struct {
+	ConversationID    string         `db:"conversation_id"`
+	UserID            string         `db:"user_id"`
+	LastReadMessageID sql.NullString `db:"last_read_message_id"`
+	LastReadAt        sql.NullTime   `db:"last_read_at"`
+}
+
+func (m memberRow) toDomain() domain.ConversationMember {
+	member := domain.ConversationMember{UserID: m.UserID}
+
+	if m.LastReadMessageID.Valid {
+		member.LastReadMessageID = m.LastReadMessageID.String
+	}
+
+	if m.LastReadAt.Valid {
+		readAt := m.LastReadAt.Time
+		member.LastReadAt = &readAt
+	}
+
+	return member
+}
+
+type ConversationRepository struct {
+	db *sqlx.DB
+}
+
+func NewConversationRepository(db *sqlx.DB) *ConversationRepository {
+	return &ConversationRepository{db: db}
+}
+
+// CreateConversation returns already existing conversation for the same direct key
+func (r *ConversationRepository) CreateConversation(ctx context.Context, conversation domain.Conversation, directKey string) (domain.Conversation, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+	defer tx.Rollback()
+
+	var key sql.NullString
+	if directKey != "" {
+		key = sql.NullString{String: directKey, Valid: true}
+	}
+
+	q := `INSERT INTO conversations (conversation_id, creator_id, is_group, direct_key) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (direct_key) DO NOTHING
+		RETURNING conversation_id`
+
+	var conversationID string
+
+	err = tx.QueryRowxContext(ctx, q, conversation.ConversationID, conversation.CreatorID, conversation.IsGroup, key).Scan(&conversationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.GetDirectConversation(ctx, directKey)
+	}
+
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+
+	for _, member := range conversation.Members {
+		q := `INSERT INTO conversation_members (conversation_id, user_id) VALUES ($1, $2)`
+
+		if _, err := tx.ExecContext(ctx, q, conversationID, member.UserID); err != nil {
+			return domain.Conversation{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Conversation{}, err
+	}
+
+	return r.GetConversation(ctx, conversationID)
+}
+
+func (r *ConversationRepository) GetDirectConversation(ctx context.Context, directKey string) (domain.Conversation, error) {
+	q := `SELECT ` + conversationColumns + ` FROM conversations c WHERE c.direct_key = $1`
+
+	return r.getConversation(ctx, q, directKey)
+}
+
+func (r *ConversationRepository) GetConversation(ctx context.Context, conversationID string) (domain.Conversation, error) {
+	q := `SELECT ` + conversationColumns + ` FROM conversations c WHERE c.conversation_id = $1`
+
+	return r.getConversation(ctx, q, conversationID)
+}
+
+func (r *ConversationRepository) getConversation(ctx context.Context, q string, arg string) (domain.Conversation, error) {
+	var row conversationRow
+
+	err := r.db.QueryRowxContext(ctx, q, arg).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Conversation{}, response.ErrConversationNotFound
+	}
+
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+
+	conversations, err := r.withMembers(ctx, []conversationRow{row})
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+
+	return conversations[0], nil
+}
+
+func (r *ConversationRepository) GetConversations(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Conversation, string, error) {
+	base := `SELECT ` + conversationColumns + ` FROM conversations c
+		JOIN conversation_members m ON m.conversation_id = c.conversation_id
+		WHERE m.user_id = $1`
+
+	var rows []conversationRow
+
+	if pageCursor == "" {
+		q := base + ` ORDER BY c.last_message_at DESC, c.conversation_id DESC LIMIT $2`
+
+		if err := r.db.SelectContext(ctx, &rows, q, userID, limit); err != nil {
+			return nil, "", err
+		}
+	} else {
+		lastMessageAt, id, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		q := base + ` AND (c.last_message_at, c.conversation_id) < ($2, $3)
+			ORDER BY c.last_message_at DESC, c.conversation_id DESC LIMIT $4`
+
+		if err := r.db.SelectContext(ctx, &rows, q, userID, lastMessageAt, id, limit); err != nil {
+			return nil, "", err
+		}
+	}
+
+	conversations, err := r.withMembers(ctx, rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(conversations) == limit {
+		last := conversations[len(conversations)-1]
+		nextCursor = cursor.Encode(last.LastMessageAt, last.ConversationID)
+	}
+
+	return conversations, nextCursor, nil
+}
+
+func (r *ConversationRepository) withMembers(ctx context.Context, rows []conversationRow) ([]domain.Conversation, error) {
+	conversations := make([]domain.Conversation, 0, len(rows))
+
+	if len(rows) == 0 {
+		return conversations, nil
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ConversationID)
+	}
+
+	q, args, err := sqlx.In(`SELECT conversation_id, user_id, last_read_message_id, last_read_at
+		FROM conversation_members WHERE conversation_id IN (?) ORDER BY user_id`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []memberRow
+
+	if err := r.db.SelectContext(ctx, &members, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	byConversation := make(map[string][]domain.ConversationMember, len(rows))
+	for _, member := range members {
+		byConversation[member.ConversationID] = append(byConversation[member.ConversationID], member.toDomain())
+	}
+
+	for _, row := range rows {
+		conversations = append(conversations, domain.Conversation{
+			ConversationID: row.ConversationID,
+			CreatorID:      row.CreatorID,
+			IsGroup:        row.IsGroup,
+			Members:        byConversation[row.ConversationID],
+			CreatedAt:      row.CreatedAt,
+			LastMessageAt:  row.LastMessageAt,
+		})
+	}
+
+	return conversations, nil
+}
+
+func (r *ConversationRepository) CreateMessage(ctx context.Context, message domain.Message) (domain.Message, error) {
+	q := `WITH touched AS (
+			UPDATE conversations SET last_message_at = now() WHERE conversation_id = $2
+		)
+		INSERT INTO messages (message_id, conversation_id, sender_id, text) VALUES ($1, $2, $3, $4)
+		RETURNING message_id, conversation_id, sender_id, text, created_at`
+
+	var created domain.Message
+
+	if err := r.db.QueryRowxContext(ctx, q, message.MessageID, message.ConversationID, message.SenderID, message.Text).StructScan(&created); err != nil {
+		return domain.Message{}, err
+	}
+
+	return created, nil
+}
+
+func (r *ConversationRepository) GetMessages(ctx context.Context, conversationID, pageCursor string, limit int) ([]domain.Message, string, error) {
+	base := `SELECT message_id, conversation_id, sender_id, text, created_at FROM messages WHERE conversation_id = $1`
+
+	var messages []domain.Message
+
+	if pageCursor == "" {
+		q := base + ` ORDER BY created_at DESC, message_id DESC LIMIT $2`
+
+		if err := r.db.SelectContext(ctx, &messages, q, conversationID, limit); err != nil {
+			return nil, "", err
+		}
+	} else {
+		createdAt, id, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		q := base + ` AND (created_at, message_id) < ($2, $3) ORDER BY created_at DESC, message_id DESC LIMIT $4`
+
+		if err := r.db.SelectContext(ctx, &messages, q, conversationID, createdAt, id, limit); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var nextCursor string
+	if len(messages) == limit {
+		last := messages[len(messages)-1]
+		nextCursor = cursor.Encode(last.CreatedAt, last.MessageID)
+	}
+
+	return messages, nextCursor, nil
+}
+
+// MarkRead only moves read receipt forward, false means it already points to the same or a later message
+func (r *ConversationRepository) MarkRead(ctx context.Context, conversationID, userID, messageID string) (time.Time, bool, error) {
+	var exists bool
+
+	q := `SELECT EXISTS(SELECT 1 FROM messages WHERE message_id = $1 AND conversation_id = $2)`
+
+	if err := r.db.GetContext(ctx, &exists, q, messageID, conversationID); err != nil {
+		return time.Time{}, false, err
+	}
+
+	if !exists {
+		return time.Time{}, false, response.ErrMessageNotFound
+	}
+
+	q = `UPDATE conversation_members m SET last_read_message_id = msg.message_id, last_read_at = now()
+		FROM messages msg
+		WHERE m.conversation_id = $1 AND m.user_id = $2 AND msg.message_id = $3
+			AND (m.last_read_message_id IS NULL OR (msg.created_at, msg.message_id) >
+				(SELECT r.created_at, r.message_id FROM messages r WHERE r.message_id = m.last_read_message_id))
+		RETURNING m.last_read_at`
+
+	var readAt time.Time
+
+	err := r.db.QueryRowxContext(ctx, q, conversationID, userID, messageID).Scan(&readAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return readAt, true, nil
+}
@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+	"strings"
+	"time"
+)
+
+const draftColumns = `id, user_id, text, media_ids, version, published_tweet_id, created_at, updated_at`
+
+type draftRow struct {
+	DraftID          string    `db:"id"`
+	UserID           string    `db:"user_id"`
+	Text             string    `db:"text"`
+	MediaIDs         string    `db:"media_ids"`
+	Version          int64     `db:"version"`
+	PublishedTweetID string    `db:"published_tweet_id"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+func (d draftRow) toDomain() domain.Draft {
+	mediaIDs := make([]string, 0)
+	if d.MediaIDs != "" {
+		mediaIDs = strings.Split(d.MediaIDs, ",")
+	}
+
+	return domain.Draft{
+		DraftID:          d.DraftID,
+		UserID:           d.UserID,
+		Text:             d.Text,
+		MediaIDs:         mediaIDs,
+		Version:          d.Version,
+		PublishedTweetID: d.PublishedTweetID,
+		CreatedAt:        d.CreatedAt,
+		UpdatedAt:        d.UpdatedAt,
+	}
+}
+
+type DraftRepository struct {
+	db *sqlx.DB
+}
+
+func NewDraftRepository(db *sqlx.DB) *DraftRepository {
+	return &DraftRepository{db: db}
+}
+
+func (r *DraftRepository) CreateDraft(ctx context.Context, draft domain.Draft) (domain.Draft, error) {
+	q := `INSERT INTO drafts (id, user_id, text, media_ids) VALUES ($1, $2, $3, $4) RETURNING ` + draftColumns
+
+	var row draftRow
+
+	err := r.db.QueryRowxContext(ctx, q, draft.DraftID, draft.UserID, draft.Text, strings.Join(draft.MediaIDs, ",")).StructScan(&row)
+	if err != nil {
+		return domain.Draft{}, err
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *DraftRepository) GetDraft(ctx context.Context, userID, draftID string) (domain.Draft, error) {
+	q := `SELECT ` + draftColumns + ` FROM drafts WHERE id = $1 AND user_id = $2 AND published_tweet_id = ''`
+
+	var row draftRow
+
+	err := r.db.QueryRowxContext(ctx, q, draftID, userID).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Draft{}, response.ErrDraftNotFound
+	}
+
+	if err != nil {
+		return domain.Draft{}, err
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *DraftRepository) GetDrafts(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Draft, string, error) {
+	var rows []draftRow
+
+	if pageCursor == "" {
+		q := `SELECT ` + draftColumns + ` FROM drafts WHERE user_id = $1 AND published_tweet_id = ''
+			ORDER BY created_at DESC, id DESC LIMIT $2`
+
+		if err := r.db.SelectContext(ctx, &rows, q, userID, limit); err != nil {
+			return nil, "", err
+		}
+	} else {
+		createdAt, draftID, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		q := `SELECT ` + draftColumns + ` FROM drafts WHERE user_id = $1 AND published_tweet_id = '' AND (created_at, id) < ($2, $3)
+			ORDER BY created_at DESC, id DESC LIMIT $4`
+
+		if err := r.db.SelectContext(ctx, &rows, q, userID, createdAt, draftID, limit); err != nil {
+			return nil, "", err
+		}
+	}
+
+	drafts := make([]domain.Draft, 0, len(rows))
+	for _, row := range rows {
+		drafts = append(drafts, row.toDomain())
+	}
+
+	var nextCursor string
+	if len(drafts) == limit {
+		last := drafts[len(drafts)-1]
+		nextCursor = cursor.Encode(last.CreatedAt, last.DraftID)
+	}
+
+	return drafts, nextCursor, nil
+}
+
+func (r *DraftRepository) UpdateDraft(ctx context.Context, draft domain.Draft) (domain.Draft, error) {
+	q := `UPDATE drafts SET text = $1, media_ids = $2, version = version + 1, updated_at = now()
+		WHERE id = $3 AND user_id = $4 AND version = $5 AND published_tweet_id = ''
+		AND (locked_until IS NULL OR locked_until <= now())
+		RETURNING ` + draftColumns
+
+	var row draftRow
+
+	err := r.db.QueryRowxContext(ctx, q, draft.Text, strings.Join(draft.MediaIDs, ","), draft.DraftID, draft.UserID,
+		draft.Version).StructScan(&row)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.updateConflict(ctx, draft.UserID, draft.DraftID)
+	}
+
+	if err != nil {
+		return domain.Draft{}, err
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *DraftRepository) DeleteDraft(ctx context.Context, userID, draftID string) error {
+	q := `DELETE FROM drafts WHERE id = $1 AND user_id = $2`
+
+	res, err := r.db.ExecContext(ctx, q, draftID, userID)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return response.ErrDraftNotFound
+	}
+
+	return nil
+}
+
+func (r *DraftRepository) LockDraft(ctx context.Context, userID, draftID string, until time.Time) (domain.Draft, error) {
+	q := `UPDATE drafts SET locked_until = $1
+		WHERE id = $2 AND user_id = $3 AND (locked_until IS NULL OR locked_until <= now())
+		RETURNING ` + draftColumns
+
+	var row draftRow
+
+	err := r.db.QueryRowxContext(ctx, q, until, draftID, userID).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := r.getAny(ctx, userID, draftID); err != nil {
+			return domain.Draft{}, err
+		}
+		return domain.Draft{}, response.ErrDraftLocked
+	}
+
+	if err != nil {
+		return domain.Draft{}, err
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *DraftRepository) UnlockDraft(ctx context.Context, userID, draftID string) error {
+	q := `UPDATE drafts SET locked_until = NULL WHERE id = $1 AND user_id = $2`
+
+	_, err := r.db.ExecContext(ctx, q, draftID, userID)
+	return err
+}
+
+func (r *DraftRepository) SetPublishedTweet(ctx context.Context, userID, draftID, tweetID string) error {
+	q := `UPDATE drafts SET published_tweet_id = $1 WHERE id = $2 AND user_id = $3`
+
+	_, err := r.db.ExecContext(ctx, q, tweetID, draftID, userID)
+	return err
+}
+
+func (r *DraftRepository) updateConflict(ctx context.Context, userID, draftID string) (domain.Draft, error) {
+	q := `SELECT ` + draftColumns + `, locked_until IS NOT NULL AND locked_until > now() AS locked FROM drafts
+		WHERE id = $1 AND user_id = $2 AND published_tweet_id = ''`
+
+	var current struct {
+		draftRow
+		Locked bool `db:"locked"`
+	}
+
+	err := r.db.QueryRowxContext(ctx, q, draftID, userID).StructScan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Draft{}, response.ErrDraftNotFound
+	}
+
+	if err != nil {
+		return domain.Draft{}, err
+	}
+
+	if current.Locked {
+		return domain.Draft{}, response.ErrDraftLocked
+	}
+
+	return current.toDomain(), response.ErrDraftVersionConflict
+}
+
+func (r *DraftRepository) getAny(ctx context.Context, userID, draftID string) (domain.Draft, error) {
+	q := `SELECT ` + draftColumns + ` FROM drafts WHERE id = $1 AND user_id = $2`
+
+	var row draftRow
+
+	err := r.db.QueryRowxContext(ctx, q, draftID, userID).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Draft{}, response.ErrDraftNotFound
+	}
+
+	return row.toDomain(), err
+}
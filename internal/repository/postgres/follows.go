@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+)
+
+type FollowRepository struct {
+	db *sqlx.DB
+}
+
+func NewFollowRepository(db *sqlx.DB) *FollowRepository {
+	return &FollowRepository{db: db}
+}
+
+func (r *FollowRepository) AddFollow(ctx context.Context, followerID, followeeID string) error {
+	q := `INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, q, followerID, followeeID)
+	return err
+}
+
+func (r *FollowRepository) DeleteFollow(ctx context.Context, followerID, followeeID string) error {
+	q := `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`
+
+	_, err := r.db.ExecContext(ctx, q, followerID, followeeID)
+	return err
+}
+
+func (r *FollowRepository) GetFollowedUsers(ctx context.Context, followerID string, followeeIDs []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(followeeIDs))
+
+	if len(followeeIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT followee_id FROM follows WHERE follower_id = ? AND followee_id IN (?)`, followerID, followeeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var followed []string
+
+	if err := r.db.SelectContext(ctx, &followed, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, followeeID := range followed {
+		result[followeeID] = true
+	}
+
+	return result, nil
+}
+
+func (r *FollowRepository) GetFollowingUsers(ctx context.Context, followeeID string, followerIDs []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(followerIDs))
+
+	if len(followerIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT follower_id FROM follows WHERE followee_id = ? AND follower_id IN (?)`, followeeID, followerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var following []string
+
+	if err := r.db.SelectContext(ctx, &following, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, followerID := range following {
+		result[followerID] = true
+	}
+
+	return result, nil
+}
+
+func (r *FollowRepository) GetFollowers(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Follow, string, error) {
+	q := `SELECT f.follower_id, f.followee_id, f.created_at FROM follows f WHERE f.followee_id = $1`
+
+	return r.page(ctx, q, "f.follower_id", userID, pageCursor, limit, followerKey)
+}
+
+func (r *FollowRepository) GetFollowing(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Follow, string, error) {
+	q := `SELECT f.follower_id, f.followee_id, f.created_at FROM follows f WHERE f.follower_id = $1`
+
+	return r.page(ctx, q, "f.followee_id", userID, pageCursor, limit, followeeKey)
+}
+
+func (r *FollowRepository) GetMutuals(ctx context.Context, userID, pageCursor string, limit int) ([]domain.Follow, string, error) {
+	q := `SELECT f.follower_id, f.followee_id, f.created_at FROM follows f
+		JOIN follows b ON b.follower_id = f.followee_id AND b.followee_id = f.follower_id
+		WHERE f.followee_id = $1`
+
+	return r.page(ctx, q, "f.follower_id", userID, pageCursor, limit, followerKey)
+}
+
+func (r *FollowRepository) GetFollowerCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	result := make(map[string]int, len(userIDs))
+
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT followee_id, count(*) AS followers FROM follows WHERE followee_id IN (?) GROUP BY followee_id`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		UserID    string `db:"followee_id"`
+		Followers int    `db:"followers"`
+	}
+
+	if err := r.db.SelectContext(ctx, &counts, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, count := range counts {
+		result[count.UserID] = count.Followers
+	}
+
+	return result, nil
+}
+
+func (r *FollowRepository) GetMostFollowed(ctx context.Context, limit int) ([]string, error) {
+	q := `SELECT followee_id FROM follows GROUP BY followee_id ORDER BY count(*) DESC, followee_id LIMIT $1`
+
+	users := make([]string, 0)
+	err := r.db.SelectContext(ctx, &users, q, limit)
+	return users, err
+}
+
+// page appends keyset pagination to base query selecting follows of one user aliased as f
+func (r *FollowRepository) page(ctx context.Context, base, keyColumn, userID, pageCursor string, limit int, key func(domain.Follow) string) ([]domain.Follow, string, error) {
+	var follows []domain.Follow
+
+	if pageCursor == "" {
+		q := base + ` ORDER BY f.created_at DESC, ` + keyColumn + ` DESC LIMIT $2`
+
+		if err := r.db.SelectContext(ctx, &follows, q, userID, limit); err != nil {
+			return nil, "", err
+		}
+	} else {
+		createdAt, id, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		q := base + ` AND (f.created_at, ` + keyColumn + `) < ($2, $3)
+			ORDER BY f.created_at DESC, ` + keyColumn + ` DESC LIMIT $4`
+
+		if err := r.db.SelectContext(ctx, &follows, q, userID, createdAt, id, limit); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var nextCursor string
+	if len(follows) == limit {
+		last := follows[len(follows)-1]
+		nextCursor = cursor.Encode(last.CreatedAt, key(last))
+	}
+
+	return follows, nextCursor, nil
+}
+
+func followerKey(follow domain.Follow) string {
+	return follow.FollowerID
+}
+
+func followeeKey(follow domain.Follow) string {
+	return follow.FolloweeID
+}
+
+type FollowRequestRepository struct {
+	db *sqlx.DB
+}
+
+func NewFollowRequestRepository(db *sqlx.DB) *FollowRequestRepository {
+	return &FollowRequestRepository{db: db}
+}
+
+func (r *FollowRequestRepository) CreateFollowRequest(ctx context.Context, requesterID, targetID string) error {
+	q := `INSERT INTO follow_requests (requester_id, target_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, q, requesterID, targetID)
+	return err
+}
+
+func (r *FollowRequestRepository) HasFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error) {
+	q := `SELECT EXISTS(SELECT 1 FROM follow_requests WHERE requester_id = $1 AND target_id = $2)`
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, q, requesterID, targetID)
+	return exists, err
+}
+
+func (r *FollowRequestRepository) GetFollowRequests(ctx context.Context, targetID, pageCursor string, limit int) ([]domain.FollowRequest, string, error) {
+	var requests []domain.FollowRequest
+
+	if pageCursor == "" {
+		q := `SELECT requester_id, target_id, created_at FROM follow_requests WHERE target_id = $1
+			ORDER BY created_at DESC, requester_id DESC LIMIT $2`
+
+		if err := r.db.SelectContext(ctx, &requests, q, targetID, limit); err != nil {
+			return nil, "", err
+		}
+	} else {
+		createdAt, requesterID, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		q := `SELECT requester_id, target_id, created_at FROM follow_requests WHERE target_id = $1 AND (created_at, requester_id) < ($2, $3)
+			ORDER BY created_at DESC, requester_id DESC LIMIT $4`
+
+		if err := r.db.SelectContext(ctx, &requests, q, targetID, createdAt, requesterID, limit); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var nextCursor string
+	if len(requests) == limit {
+		last := requests[len(requests)-1]
+		nextCursor = cursor.Encode(last.CreatedAt, last.RequesterID)
+	}
+
+	return requests, nextCursor, nil
+}
+
+func (r *FollowRequestRepository) DeleteFollowRequest(ctx context.Context, requesterID, targetID string) error {
+	q := `DELETE FROM follow_requests WHERE requester_id = $1 AND target_id = $2`
+
+	res, err := r.db.ExecContext(ctx, q, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return response.ErrFollowRequestNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type LeaseRepository struct {
+	db *sqlx.DB
+}
+
+func NewLeaseRepository(db *sqlx.DB) *LeaseRepository {
+	return &LeaseRepository{db: db}
+}
+
+func (r *LeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	q := `INSERT INTO leases (name, holder, expires_at) VALUES ($1, $2, now() + $3::bigint * interval '1 millisecond')
+		ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+		WHERE leases.holder = excluded.holder OR leases.expires_at < now()
+		RETURNING holder`
+
+	var current string
+
+	err := r.db.QueryRowxContext(ctx, q, name, holder, ttl.Milliseconds()).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return current == holder, nil
+}
+
+func (r *LeaseRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	q := `DELETE FROM leases WHERE name = $1 AND holder = $2`
+
+	_, err := r.db.ExecContext(ctx, q, name, holder)
+	return err
+}
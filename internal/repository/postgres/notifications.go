@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type notificationRow struct {
+	NotificationID string    `db:"id"`
+	UserID         string    `db:"user_id"`
+	SenderID       string    `db:"sender_id"`
+	Type           string    `db:"type"`
+	TargetID       string    `db:"target_id"`
+	Read           bool      `db:"read"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+type NotificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) CreateNotifications(ctx context.Context, notifications []domain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	rows := make([]notificationRow, 0, len(notifications))
+
+	for _, notification := range notifications {
+		rows = append(rows, notificationRow{
+			NotificationID: notification.NotificationID,
+			UserID:         notification.UserID,
+			SenderID:       notification.SenderID,
+			Type:           notification.Type,
+			TargetID:       notification.TargetID,
+			Read:           notification.Read,
+			CreatedAt:      notification.CreatedAt,
+		})
+	}
+
+	q := `INSERT INTO notifications (id, user_id, sender_id, type, target_id, read, created_at)
+		VALUES (:id, :user_id, :sender_id, :type, :target_id, :read, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, q, rows)
+	return err
+}
+
+func (r *NotificationRepository) GetNotifications(ctx context.Context, userID string) ([]domain.Notification, error) {
+	q := `SELECT id, user_id, sender_id, type, target_id, read, created_at FROM notifications
+		WHERE user_id = $1 ORDER BY created_at DESC`
+
+	var rows []notificationRow
+
+	if err := r.db.SelectContext(ctx, &rows, q, userID); err != nil {
+		return nil, err
+	}
+
+	notifications := make([]domain.Notification, 0, len(rows))
+
+	for _, row := range rows {
+		notifications = append(notifications, domain.Notification{
+			NotificationID: row.NotificationID,
+			UserID:         row.UserID,
+			SenderID:       row.SenderID,
+			Read:           row.Read,
+			CreatedAt:      row.CreatedAt,
+			Type:           row.Type,
+			TargetID:       row.TargetID,
+		})
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) MarkNotificationAsRead(ctx context.Context, userID, notificationID string) (bool, error) {
+	q := `UPDATE notifications SET read = true WHERE id = $1 AND user_id = $2`
+
+	res, err := r.db.ExecContext(ctx, q, notificationID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *NotificationRepository) ReadAllNotifications(ctx context.Context, userID string) error {
+	q := `UPDATE notifications SET read = true WHERE user_id = $1 AND NOT read`
+
+	_, err := r.db.ExecContext(ctx, q, userID)
+	return err
+}
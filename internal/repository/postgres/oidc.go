@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type oidcStateRow struct {
+	State        string         `db:"state"`
+	Provider     string         `db:"provider"`
+	Nonce        string         `db:"nonce"`
+	CodeVerifier string         `db:"code_verifier"`
+	LinkUserID   sql.NullString `db:"link_user_id"`
+	ExpiresAt    time.Time      `db:"expires_at"`
+}
+
+type OIDCRepository struct {
+	db *sqlx.DB
+}
+
+func NewOIDCRepository(db *sqlx.DB) *OIDCRepository {
+	return &OIDCRepository{db: db}
+}
+
+func (r *OIDCRepository) CreateState(ctx context.Context, state domain.OIDCState) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM oidc_states WHERE expires_at <= now()`); err != nil {
+		return err
+	}
+
+	q := `INSERT INTO oidc_states (state, provider, nonce, code_verifier, link_user_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.db.ExecContext(ctx, q, state.State, state.Provider, state.Nonce, state.CodeVerifier,
+		sql.NullString{String: state.LinkUserID, Valid: state.LinkUserID != ""}, state.ExpiresAt)
+
+	return err
+}
+
+func (r *OIDCRepository) ConsumeState(ctx context.Context, state string, now time.Time) (domain.OIDCState, error) {
+	q := `DELETE FROM oidc_states WHERE state = $1
+		RETURNING state, provider, nonce, code_verifier, link_user_id, expires_at`
+
+	var row oidcStateRow
+
+	err := r.db.QueryRowxContext(ctx, q, state).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.OIDCState{}, response.ErrInvalidOIDCState
+	}
+
+	if err != nil {
+		return domain.OIDCState{}, err
+	}
+
+	if !row.ExpiresAt.After(now) {
+		return domain.OIDCState{}, response.ErrInvalidOIDCState
+	}
+
+	return domain.OIDCState{
+		State:        row.State,
+		Provider:     row.Provider,
+		Nonce:        row.Nonce,
+		CodeVerifier: row.CodeVerifier,
+		LinkUserID:   row.LinkUserID.String,
+		ExpiresAt:    row.ExpiresAt,
+	}, nil
+}
+
+func (r *OIDCRepository) GetIdentity(ctx context.Context, provider, subject string) (domain.Identity, error) {
+	q := `SELECT provider, subject, user_id, email, created_at FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	var identity domain.Identity
+
+	err := r.db.GetContext(ctx, &identity, q, provider, subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Identity{}, response.ErrUserNotFound
+	}
+
+	return identity, err
+}
+
+func (r *OIDCRepository) CreateIdentity(ctx context.Context, identity domain.Identity) error {
+	q := `INSERT INTO user_identities (provider, subject, user_id, email) VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, q, identity.Provider, identity.Subject, identity.UserID, identity.Email)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return response.ErrIdentityAlreadyLinked
+	}
+
+	return nil
+}
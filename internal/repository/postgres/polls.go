@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type pollRow struct {
+	TweetID   string    `db:"tweet_id"`
+	AuthorID  string    `db:"author_id"`
+	Options   string    `db:"options"`
+	ClosesAt  time.Time `db:"closes_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (p pollRow) toDomain() (domain.PollRecord, error) {
+	var options []string
+	if err := json.Unmarshal([]byte(p.Options), &options); err != nil {
+		return domain.PollRecord{}, err
+	}
+
+	return domain.PollRecord{
+		TweetID:   p.TweetID,
+		AuthorID:  p.AuthorID,
+		Options:   options,
+		Votes:     make([]int, len(options)),
+		ClosesAt:  p.ClosesAt,
+		CreatedAt: p.CreatedAt,
+	}, nil
+}
+
+type PollRepository struct {
+	db *sqlx.DB
+}
+
+func NewPollRepository(db *sqlx.DB) *PollRepository {
+	return &PollRepository{db: db}
+}
+
+func (r *PollRepository) CreatePoll(ctx context.Context, poll domain.PollRecord) error {
+	options, err := json.Marshal(poll.Options)
+	if err != nil {
+		return err
+	}
+
+	q := `INSERT INTO polls (tweet_id, author_id, options, closes_at) VALUES ($1, $2, $3, $4)`
+
+	_, err = r.db.ExecContext(ctx, q, poll.TweetID, poll.AuthorID, string(options), poll.ClosesAt)
+	return err
+}
+
+func (r *PollRepository) GetPolls(ctx context.Context, tweetIDs []string) (map[string]domain.PollRecord, error) {
+	result := make(map[string]domain.PollRecord, len(tweetIDs))
+
+	if len(tweetIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT tweet_id, author_id, options, closes_at, created_at FROM polls WHERE tweet_id IN (?)`, tweetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []pollRow
+
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	for _, row := range rows {
+		poll, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result[poll.TweetID] = poll
+	}
+
+	q, args, err = sqlx.In(`SELECT tweet_id, option, count(*) AS votes FROM poll_votes WHERE tweet_id IN (?)
+		GROUP BY tweet_id, option`, tweetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var tallies []struct {
+		TweetID string `db:"tweet_id"`
+		Option  int    `db:"option"`
+		Votes   int    `db:"votes"`
+	}
+
+	if err := r.db.SelectContext(ctx, &tallies, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, tally := range tallies {
+		poll, ok := result[tally.TweetID]
+		if ok && tally.Option < len(poll.Votes) {
+			poll.Votes[tally.Option] = tally.Votes
+		}
+	}
+
+	return result, nil
+}
+
+func (r *PollRepository) GetVotes(ctx context.Context, userID string, tweetIDs []string) (map[string]int, error) {
+	result := make(map[string]int)
+
+	if len(tweetIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT tweet_id, option FROM poll_votes WHERE user_id = ? AND tweet_id IN (?)`, userID, tweetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var votes []struct {
+		TweetID string `db:"tweet_id"`
+		Option  int    `db:"option"`
+	}
+
+	if err := r.db.SelectContext(ctx, &votes, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, vote := range votes {
+		result[vote.TweetID] = vote.Option
+	}
+
+	return result, nil
+}
+
+func (r *PollRepository) Vote(ctx context.Context, tweetID, userID string, option int) error {
+	var row pollRow
+
+	err := r.db.QueryRowxContext(ctx, `SELECT tweet_id, author_id, options, closes_at, created_at FROM polls WHERE tweet_id = $1`,
+		tweetID).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return response.ErrPollNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	poll, err := row.toDomain()
+	if err != nil {
+		return err
+	}
+
+	if option < 0 || option >= len(poll.Options) {
+		return response.ErrInvalidPollOption
+	}
+
+	// closing time is checked by the insert itself, so vote can't slip in after poll is closed
+	q := `INSERT INTO poll_votes (tweet_id, user_id, option)
+		SELECT $1, $2, $3 FROM polls WHERE tweet_id = $1 AND closes_at > now()
+		ON CONFLICT DO NOTHING`
+
+	res, err := r.db.ExecContext(ctx, q, tweetID, userID, option)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 1 {
+		return nil
+	}
+
+	if !poll.ClosesAt.After(time.Now()) {
+		return response.ErrPollClosed
+	}
+
+	return response.ErrAlreadyVoted
+}
+
+func (r *PollRepository) GetVoters(ctx context.Context, tweetID string) ([]string, error) {
+	var voters []string
+
+	q := `SELECT user_id FROM poll_votes WHERE tweet_id = $1 ORDER BY user_id`
+
+	if err := r.db.SelectContext(ctx, &voters, q, tweetID); err != nil {
+		return nil, err
+	}
+
+	return voters, nil
+}
+
+// ClaimClosedPolls uses SKIP LOCKED so concurrent replicas never claim the same poll
+func (r *PollRepository) ClaimClosedPolls(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.PollRecord, error) {
+	q := `UPDATE polls SET locked_until = $1
+		WHERE tweet_id IN (
+			SELECT tweet_id FROM polls
+			WHERE NOT closed_notified AND closes_at <= $2 AND (locked_until IS NULL OR locked_until <= $2)
+			ORDER BY closes_at LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING tweet_id, author_id, options, closes_at, created_at`
+
+	var rows []pollRow
+
+	if err := r.db.SelectContext(ctx, &rows, q, now.Add(claimTTL), now, limit); err != nil {
+		return nil, err
+	}
+
+	polls := make([]domain.PollRecord, 0, len(rows))
+
+	for _, row := range rows {
+		poll, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, poll)
+	}
+
+	return polls, nil
+}
+
+func (r *PollRepository) MarkPollNotified(ctx context.Context, tweetID string) error {
+	q := `UPDATE polls SET closed_notified = true, locked_until = NULL WHERE tweet_id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, tweetID)
+	return err
+}
+
+func (r *PollRepository) DeletePoll(ctx context.Context, tweetID string) error {
+	q := `DELETE FROM polls WHERE tweet_id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, tweetID)
+	return err
+}
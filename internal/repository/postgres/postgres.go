@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/Verce11o/yata/internal/config"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"log"
+)
+
+func NewPostgresConnection(cfg config.PostgresConfig) *sqlx.DB {
+	db, err := sqlx.Connect("pgx", fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name))
+	if err != nil {
+		log.Fatalf("err while connection to postgres: %v", err.Error())
+	}
+
+	return db
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const preferencesColumns = `user_id, types, quiet_enabled, quiet_start, quiet_end, time_zone, updated_at`
+
+type preferencesRow struct {
+	UserID       string    `db:"user_id"`
+	Types        string    `db:"types"`
+	QuietEnabled bool      `db:"quiet_enabled"`
+	QuietStart   string    `db:"quiet_start"`
+	QuietEnd     string    `db:"quiet_end"`
+	TimeZone     string    `db:"time_zone"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (p preferencesRow) toDomain() (domain.NotificationPreferences, error) {
+	types := make(map[string]domain.ChannelPreferences)
+	if err := json.Unmarshal([]byte(p.Types), &types); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	return domain.NotificationPreferences{
+		UserID: p.UserID,
+		Types:  types,
+		QuietHours: domain.QuietHours{
+			Enabled: p.QuietEnabled,
+			Start:   p.QuietStart,
+			End:     p.QuietEnd,
+		},
+		TimeZone:  p.TimeZone,
+		UpdatedAt: p.UpdatedAt,
+	}, nil
+}
+
+type NotificationPreferencesRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationPreferencesRepository(db *sqlx.DB) *NotificationPreferencesRepository {
+	return &NotificationPreferencesRepository{db: db}
+}
+
+func (r *NotificationPreferencesRepository) GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, bool, error) {
+	q := `SELECT ` + preferencesColumns + ` FROM notification_preferences WHERE user_id = $1`
+
+	var row preferencesRow
+
+	err := r.db.QueryRowxContext(ctx, q, userID).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NotificationPreferences{}, false, nil
+	}
+
+	if err != nil {
+		return domain.NotificationPreferences{}, false, err
+	}
+
+	preferences, err := row.toDomain()
+	if err != nil {
+		return domain.NotificationPreferences{}, false, err
+	}
+
+	return preferences, true, nil
+}
+
+func (r *NotificationPreferencesRepository) UpdatePreferences(ctx context.Context, preferences domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	types, err := json.Marshal(preferences.Types)
+	if err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	q := `INSERT INTO notification_preferences (user_id, types, quiet_enabled, quiet_start, quiet_end, time_zone)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET types = excluded.types, quiet_enabled = excluded.quiet_enabled,
+			quiet_start = excluded.quiet_start, quiet_end = excluded.quiet_end, time_zone = excluded.time_zone, updated_at = now()
+		RETURNING ` + preferencesColumns
+
+	var row preferencesRow
+
+	err = r.db.QueryRowxContext(ctx, q, preferences.UserID, string(types), preferences.QuietHours.Enabled,
+		preferences.QuietHours.Start, preferences.QuietHours.End, preferences.TimeZone).StructScan(&row)
+	if err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	return row.toDomain()
+}
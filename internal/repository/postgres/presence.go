@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type presenceRow struct {
+	Online     bool         `db:"online"`
+	LastSeenAt sql.NullTime `db:"last_seen_at"`
+}
+
+type PresenceRepository struct {
+	db *sqlx.DB
+}
+
+func NewPresenceRepository(db *sqlx.DB) *PresenceRepository {
+	return &PresenceRepository{db: db}
+}
+
+// TouchSession also drops expired sessions of the user left by replicas that went away
+func (r *PresenceRepository) TouchSession(ctx context.Context, sessionID, userID string, ttl time.Duration) error {
+	q := `WITH expired AS (
+			DELETE FROM presence_sessions WHERE user_id = $2 AND session_id <> $1 AND expires_at < now()
+		), seen AS (
+			INSERT INTO user_presence (user_id, last_seen_at) VALUES ($2, now())
+			ON CONFLICT (user_id) DO UPDATE SET last_seen_at = excluded.last_seen_at
+		)
+		INSERT INTO presence_sessions (session_id, user_id, expires_at) VALUES ($1, $2, now() + $3::bigint * interval '1 millisecond')
+		ON CONFLICT (session_id) DO UPDATE SET expires_at = excluded.expires_at`
+
+	_, err := r.db.ExecContext(ctx, q, sessionID, userID, ttl.Milliseconds())
+	return err
+}
+
+func (r *PresenceRepository) EndSession(ctx context.Context, sessionID, userID string) error {
+	q := `WITH ended AS (
+			DELETE FROM presence_sessions WHERE session_id = $1
+		)
+		INSERT INTO user_presence (user_id, last_seen_at) VALUES ($2, now())
+		ON CONFLICT (user_id) DO UPDATE SET last_seen_at = excluded.last_seen_at`
+
+	_, err := r.db.ExecContext(ctx, q, sessionID, userID)
+	return err
+}
+
+func (r *PresenceRepository) GetPresence(ctx context.Context, userID string) (domain.Presence, error) {
+	q := `SELECT EXISTS (SELECT 1 FROM presence_sessions WHERE user_id = $1 AND expires_at > now()) AS online,
+		(SELECT last_seen_at FROM user_presence WHERE user_id = $1) AS last_seen_at`
+
+	var row presenceRow
+
+	if err := r.db.QueryRowxContext(ctx, q, userID).StructScan(&row); err != nil {
+		return domain.Presence{}, err
+	}
+
+	presence := domain.Presence{UserID: userID, Status: domain.PresenceOffline}
+
+	if row.Online {
+		presence.Status = domain.PresenceOnline
+	}
+
+	if row.LastSeenAt.Valid {
+		lastSeen := row.LastSeenAt.Time
+		presence.LastSeenAt = &lastSeen
+	}
+
+	return presence, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type RelationRepository struct {
+	db *sqlx.DB
+}
+
+func NewRelationRepository(db *sqlx.DB) *RelationRepository {
+	return &RelationRepository{db: db}
+}
+
+func (r *RelationRepository) AddRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) error {
+	q := `INSERT INTO user_relations (user_id, target_id, relation) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, q, userID, targetID, relation)
+	return err
+}
+
+func (r *RelationRepository) DeleteRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) error {
+	q := `DELETE FROM user_relations WHERE user_id = $1 AND target_id = $2 AND relation = $3`
+
+	_, err := r.db.ExecContext(ctx, q, userID, targetID, relation)
+	return err
+}
+
+func (r *RelationRepository) HasRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) (bool, error) {
+	q := `SELECT EXISTS(SELECT 1 FROM user_relations WHERE user_id = $1 AND target_id = $2 AND relation = $3)`
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, q, userID, targetID, relation)
+	return exists, err
+}
+
+func (r *RelationRepository) GetRelated(ctx context.Context, userID string, relation domain.RelationType) ([]string, error) {
+	q := `SELECT target_id FROM user_relations WHERE user_id = $1 AND relation = $2`
+
+	related := make([]string, 0)
+	err := r.db.SelectContext(ctx, &related, q, userID, relation)
+	return related, err
+}
+
+func (r *RelationRepository) GetRelatedBy(ctx context.Context, targetID string, relation domain.RelationType) ([]string, error) {
+	q := `SELECT user_id FROM user_relations WHERE target_id = $1 AND relation = $2`
+
+	related := make([]string, 0)
+	err := r.db.SelectContext(ctx, &related, q, targetID, relation)
+	return related, err
+}
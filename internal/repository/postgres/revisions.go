@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type revisionRow struct {
+	TweetID    string    `db:"tweet_id"`
+	Revision   int       `db:"revision"`
+	Text       string    `db:"text"`
+	MediaID    string    `db:"media_id"`
+	CreatedAt  time.Time `db:"created_at"`
+	ReplacedAt time.Time `db:"replaced_at"`
+}
+
+func (r revisionRow) toDomain() domain.TweetRevision {
+	return domain.TweetRevision{
+		TweetID:    r.TweetID,
+		Revision:   r.Revision,
+		Text:       r.Text,
+		MediaID:    r.MediaID,
+		CreatedAt:  r.CreatedAt,
+		ReplacedAt: r.ReplacedAt,
+	}
+}
+
+type TweetRevisionRepository struct {
+	db *sqlx.DB
+}
+
+func NewTweetRevisionRepository(db *sqlx.DB) *TweetRevisionRepository {
+	return &TweetRevisionRepository{db: db}
+}
+
+func (r *TweetRevisionRepository) AddRevision(ctx context.Context, revision domain.TweetRevision) (domain.TweetRevision, error) {
+	// primary key makes one of two concurrent edits fail instead of sharing revision number
+	q := `INSERT INTO tweet_revisions (tweet_id, revision, text, media_id, created_at, replaced_at)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5 FROM tweet_revisions WHERE tweet_id = $1
+		RETURNING tweet_id, revision, text, media_id, created_at, replaced_at`
+
+	var row revisionRow
+
+	err := r.db.QueryRowxContext(ctx, q, revision.TweetID, revision.Text, revision.MediaID, revision.CreatedAt,
+		revision.ReplacedAt).StructScan(&row)
+	if err != nil {
+		return domain.TweetRevision{}, err
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *TweetRevisionRepository) DeleteRevision(ctx context.Context, tweetID string, revision int) error {
+	q := `DELETE FROM tweet_revisions WHERE tweet_id = $1 AND revision = $2`
+
+	_, err := r.db.ExecContext(ctx, q, tweetID, revision)
+	return err
+}
+
+func (r *TweetRevisionRepository) GetRevisions(ctx context.Context, tweetID string) ([]domain.TweetRevision, error) {
+	q := `SELECT tweet_id, revision, text, media_id, created_at, replaced_at FROM tweet_revisions
+		WHERE tweet_id = $1 ORDER BY revision`
+
+	var rows []revisionRow
+
+	if err := r.db.SelectContext(ctx, &rows, q, tweetID); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]domain.TweetRevision, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, row.toDomain())
+	}
+
+	return revisions, nil
+}
+
+func (r *TweetRevisionRepository) GetEditStats(ctx context.Context, tweetIDs []string) (map[string]domain.EditStats, error) {
+	result := make(map[string]domain.EditStats, len(tweetIDs))
+
+	if len(tweetIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT tweet_id, count(*) AS count, max(replaced_at) AS edited_at FROM tweet_revisions
+		WHERE tweet_id IN (?) GROUP BY tweet_id`, tweetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []struct {
+		TweetID  string    `db:"tweet_id"`
+		Count    int       `db:"count"`
+		EditedAt time.Time `db:"edited_at"`
+	}
+
+	if err := r.db.SelectContext(ctx, &stats, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, stat := range stats {
+		result[stat.TweetID] = domain.EditStats{Count: stat.Count, EditedAt: stat.EditedAt}
+	}
+
+	return result, nil
+}
+
+func (r *TweetRevisionRepository) DeleteRevisions(ctx context.Context, tweetID string) error {
+	q := `DELETE FROM tweet_revisions WHERE tweet_id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, tweetID)
+	return err
+}
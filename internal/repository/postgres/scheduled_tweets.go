@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const scheduledTweetColumns = `id, user_id, text, media_id, publish_at, status, tweet_id, attempts, last_error, created_at, updated_at`
+
+type ScheduledTweetRepository struct {
+	db *sqlx.DB
+}
+
+func NewScheduledTweetRepository(db *sqlx.DB) *ScheduledTweetRepository {
+	return &ScheduledTweetRepository{db: db}
+}
+
+func (r *ScheduledTweetRepository) CreateScheduledTweet(ctx context.Context, tweet domain.ScheduledTweet) (domain.ScheduledTweet, error) {
+	q := `INSERT INTO scheduled_tweets (id, user_id, text, media_id, publish_at, status) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + scheduledTweetColumns
+
+	var created domain.ScheduledTweet
+
+	err := r.db.QueryRowxContext(ctx, q, tweet.ScheduledID, tweet.UserID, tweet.Text, tweet.MediaID, tweet.PublishAt,
+		domain.ScheduledTweetPending).StructScan(&created)
+
+	return created, err
+}
+
+func (r *ScheduledTweetRepository) GetScheduledTweet(ctx context.Context, userID, scheduledID string) (domain.ScheduledTweet, error) {
+	q := `SELECT ` + scheduledTweetColumns + ` FROM scheduled_tweets WHERE id = $1 AND user_id = $2`
+
+	var tweet domain.ScheduledTweet
+
+	err := r.db.QueryRowxContext(ctx, q, scheduledID, userID).StructScan(&tweet)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tweet, response.ErrScheduledTweetNotFound
+	}
+
+	return tweet, err
+}
+
+func (r *ScheduledTweetRepository) GetScheduledTweets(ctx context.Context, userID, pageCursor string, limit int) ([]domain.ScheduledTweet, string, error) {
+	var tweets []domain.ScheduledTweet
+
+	if pageCursor == "" {
+		q := `SELECT ` + scheduledTweetColumns + ` FROM scheduled_tweets WHERE user_id = $1
+			ORDER BY created_at DESC, id DESC LIMIT $2`
+
+		if err := r.db.SelectContext(ctx, &tweets, q, userID, limit); err != nil {
+			return nil, "", err
+		}
+	} else {
+		createdAt, scheduledID, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		q := `SELECT ` + scheduledTweetColumns + ` FROM scheduled_tweets WHERE user_id = $1 AND (created_at, id) < ($2, $3)
+			ORDER BY created_at DESC, id DESC LIMIT $4`
+
+		if err := r.db.SelectContext(ctx, &tweets, q, userID, createdAt, scheduledID, limit); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var nextCursor string
+	if len(tweets) == limit {
+		last := tweets[len(tweets)-1]
+		nextCursor = cursor.Encode(last.CreatedAt, last.ScheduledID)
+	}
+
+	return tweets, nextCursor, nil
+}
+
+func (r *ScheduledTweetRepository) UpdateScheduledTweet(ctx context.Context, tweet domain.ScheduledTweet) (domain.ScheduledTweet, error) {
+	q := `UPDATE scheduled_tweets SET text = $1, media_id = $2, publish_at = $3, updated_at = now()
+		WHERE id = $4 AND user_id = $5 AND status = $6
+		RETURNING ` + scheduledTweetColumns
+
+	var updated domain.ScheduledTweet
+
+	err := r.db.QueryRowxContext(ctx, q, tweet.Text, tweet.MediaID, tweet.PublishAt, tweet.ScheduledID, tweet.UserID,
+		domain.ScheduledTweetPending).StructScan(&updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return updated, r.notPendingError(ctx, tweet.UserID, tweet.ScheduledID)
+	}
+
+	return updated, err
+}
+
+func (r *ScheduledTweetRepository) DeleteScheduledTweet(ctx context.Context, userID, scheduledID string) error {
+	q := `DELETE FROM scheduled_tweets WHERE id = $1 AND user_id = $2 AND status = $3`
+
+	res, err := r.db.ExecContext(ctx, q, scheduledID, userID, domain.ScheduledTweetPending)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return r.notPendingError(ctx, userID, scheduledID)
+	}
+
+	return nil
+}
+
+// ClaimDueTweets uses SKIP LOCKED so concurrent publishers never claim the same row
+func (r *ScheduledTweetRepository) ClaimDueTweets(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.ScheduledTweet, error) {
+	q := `UPDATE scheduled_tweets SET status = $1, attempts = attempts + 1, locked_until = $2, updated_at = now()
+		WHERE id IN (
+			SELECT id FROM scheduled_tweets
+			WHERE status IN ($3, $1) AND publish_at <= $4 AND (locked_until IS NULL OR locked_until <= $4)
+			ORDER BY publish_at LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + scheduledTweetColumns
+
+	var tweets []domain.ScheduledTweet
+
+	err := r.db.SelectContext(ctx, &tweets, q, domain.ScheduledTweetPublishing, now.Add(claimTTL),
+		domain.ScheduledTweetPending, now, limit)
+
+	return tweets, err
+}
+
+func (r *ScheduledTweetRepository) MarkPublished(ctx context.Context, scheduledID, tweetID string) error {
+	q := `UPDATE scheduled_tweets SET status = $1, tweet_id = $2, last_error = '', updated_at = now() WHERE id = $3`
+
+	_, err := r.db.ExecContext(ctx, q, domain.ScheduledTweetPublished, tweetID, scheduledID)
+	return err
+}
+
+func (r *ScheduledTweetRepository) RetryLater(ctx context.Context, scheduledID, lastError string, retryAt time.Time) error {
+	q := `UPDATE scheduled_tweets SET status = $1, last_error = $2, locked_until = $3, updated_at = now() WHERE id = $4`
+
+	_, err := r.db.ExecContext(ctx, q, domain.ScheduledTweetPending, lastError, retryAt, scheduledID)
+	return err
+}
+
+func (r *ScheduledTweetRepository) MarkFailed(ctx context.Context, scheduledID, lastError string) error {
+	q := `UPDATE scheduled_tweets SET status = $1, last_error = $2, updated_at = now() WHERE id = $3`
+
+	_, err := r.db.ExecContext(ctx, q, domain.ScheduledTweetFailed, lastError, scheduledID)
+	return err
+}
+
+func (r *ScheduledTweetRepository) notPendingError(ctx context.Context, userID, scheduledID string) error {
+	if _, err := r.GetScheduledTweet(ctx, userID, scheduledID); err != nil {
+		return err
+	}
+	return response.ErrScheduledTweetLocked
+}
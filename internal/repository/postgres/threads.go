@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type threadLinkRow struct {
+	TweetID          string    `db:"tweet_id"`
+	InReplyToTweetID string    `db:"in_reply_to_tweet_id"`
+	ConversationID   string    `db:"conversation_id"`
+	AuthorID         string    `db:"author_id"`
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+func (t threadLinkRow) toDomain() domain.ThreadLink {
+	return domain.ThreadLink{
+		TweetID:          t.TweetID,
+		InReplyToTweetID: t.InReplyToTweetID,
+		ConversationID:   t.ConversationID,
+		AuthorID:         t.AuthorID,
+		CreatedAt:        t.CreatedAt,
+	}
+}
+
+type ThreadRepository struct {
+	db *sqlx.DB
+}
+
+func NewThreadRepository(db *sqlx.DB) *ThreadRepository {
+	return &ThreadRepository{db: db}
+}
+
+func (r *ThreadRepository) AddReply(ctx context.Context, link domain.ThreadLink) error {
+	q := `INSERT INTO tweet_threads (tweet_id, in_reply_to_tweet_id, conversation_id, author_id) VALUES ($1, $2, $3, $4)`
+
+	_, err := r.db.ExecContext(ctx, q, link.TweetID, link.InReplyToTweetID, link.ConversationID, link.AuthorID)
+	return err
+}
+
+func (r *ThreadRepository) GetLinks(ctx context.Context, tweetIDs []string) (map[string]domain.ThreadLink, error) {
+	result := make(map[string]domain.ThreadLink)
+
+	if len(tweetIDs) == 0 {
+		return result, nil
+	}
+
+	q, args, err := sqlx.In(`SELECT tweet_id, in_reply_to_tweet_id, conversation_id, author_id, created_at FROM tweet_threads
+		WHERE tweet_id IN (?)`, tweetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []threadLinkRow
+
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.TweetID] = row.toDomain()
+	}
+
+	return result, nil
+}
+
+func (r *ThreadRepository) GetReplies(ctx context.Context, tweetID string) ([]domain.ThreadLink, error) {
+	q := `SELECT tweet_id, in_reply_to_tweet_id, conversation_id, author_id, created_at FROM tweet_threads
+		WHERE in_reply_to_tweet_id = $1 ORDER BY created_at, tweet_id`
+
+	var rows []threadLinkRow
+
+	if err := r.db.SelectContext(ctx, &rows, q, tweetID); err != nil {
+		return nil, err
+	}
+
+	replies := make([]domain.ThreadLink, 0, len(rows))
+	for _, row := range rows {
+		replies = append(replies, row.toDomain())
+	}
+
+	return replies, nil
+}
+
+func (r *ThreadRepository) DeleteLink(ctx context.Context, tweetID string) error {
+	q := `DELETE FROM tweet_threads WHERE tweet_id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, tweetID)
+	return err
+}
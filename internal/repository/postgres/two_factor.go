@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type twoFactorRow struct {
+	UserID       string    `db:"user_id"`
+	Secret       string    `db:"secret"`
+	Enabled      bool      `db:"enabled"`
+	LastUsedStep int64     `db:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type twoFactorChallengeRow struct {
+	TokenHash string    `db:"token_hash"`
+	UserID    string    `db:"user_id"`
+	Attempts  int       `db:"attempts"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+type TwoFactorRepository struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepository(db *sqlx.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+func (r *TwoFactorRepository) GetTwoFactor(ctx context.Context, userID string) (domain.TwoFactor, error) {
+	q := `SELECT user_id, secret, enabled, last_used_step, created_at FROM two_factor WHERE user_id = $1`
+
+	var row twoFactorRow
+
+	err := r.db.GetContext(ctx, &row, q, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.TwoFactor{}, response.ErrTwoFactorSetupNotFound
+	}
+
+	if err != nil {
+		return domain.TwoFactor{}, err
+	}
+
+	return domain.TwoFactor{
+		UserID:       row.UserID,
+		Secret:       row.Secret,
+		Enabled:      row.Enabled,
+		LastUsedStep: row.LastUsedStep,
+		CreatedAt:    row.CreatedAt,
+	}, nil
+}
+
+func (r *TwoFactorRepository) SaveSetup(ctx context.Context, userID, secret string) error {
+	q := `INSERT INTO two_factor (user_id, secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, last_used_step = 0, created_at = now()
+		WHERE NOT two_factor.enabled`
+
+	return r.expectOne(ctx, response.ErrTwoFactorEnabled, q, userID, secret)
+}
+
+func (r *TwoFactorRepository) EnableTwoFactor(ctx context.Context, userID string, step int64, codeHashes []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `UPDATE two_factor SET enabled = TRUE, last_used_step = $1 WHERE user_id = $2 AND NOT enabled`
+
+	result, err := tx.ExecContext(ctx, q, step, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return response.ErrTwoFactorEnabled
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *TwoFactorRepository) DeleteTwoFactor(ctx context.Context, userID string) error {
+	q := `DELETE FROM two_factor WHERE user_id = $1`
+
+	return r.expectOne(ctx, response.ErrTwoFactorNotEnabled, q, userID)
+}
+
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID string, step int64) error {
+	q := `UPDATE two_factor SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $1`
+
+	return r.expectOne(ctx, response.ErrInvalidTwoFactorCode, q, step, userID)
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var enabled bool
+
+	err = tx.GetContext(ctx, &enabled, `SELECT enabled FROM two_factor WHERE user_id = $1 FOR UPDATE`, userID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !enabled) {
+		return response.ErrTwoFactorNotEnabled
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sqlx.Tx, userID string, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	for _, hash := range codeHashes {
+		q := `INSERT INTO two_factor_recovery_codes (user_id, code_hash) VALUES ($1, $2)`
+
+		if _, err := tx.ExecContext(ctx, q, userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	q := `UPDATE two_factor_recovery_codes SET used_at = now() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	return r.expectOne(ctx, response.ErrInvalidTwoFactorCode, q, userID, codeHash)
+}
+
+func (r *TwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	q := `SELECT count(*) FROM two_factor_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+
+	err := r.db.GetContext(ctx, &count, q, userID)
+
+	return count, err
+}
+
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, challenge domain.TwoFactorChallenge) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM two_factor_challenges WHERE expires_at <= now()`); err != nil {
+		return err
+	}
+
+	q := `INSERT INTO two_factor_challenges (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`
+
+	_, err := r.db.ExecContext(ctx, q, challenge.TokenHash, challenge.UserID, challenge.ExpiresAt)
+
+	return err
+}
+
+func (r *TwoFactorRepository) AttemptChallenge(ctx context.Context, tokenHash string, now time.Time, maxAttempts int) (domain.TwoFactorChallenge, error) {
+	q := `UPDATE two_factor_challenges SET attempts = attempts + 1
+		WHERE token_hash = $1 AND expires_at > $2 AND attempts < $3
+		RETURNING token_hash, user_id, attempts, expires_at`
+
+	var row twoFactorChallengeRow
+
+	err := r.db.QueryRowxContext(ctx, q, tokenHash, now, maxAttempts).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.TwoFactorChallenge{}, response.ErrInvalidChallenge
+	}
+
+	if err != nil {
+		return domain.TwoFactorChallenge{}, err
+	}
+
+	return domain.TwoFactorChallenge{
+		TokenHash: row.TokenHash,
+		UserID:    row.UserID,
+		Attempts:  row.Attempts,
+		ExpiresAt: row.ExpiresAt,
+	}, nil
+}
+
+func (r *TwoFactorRepository) DeleteChallenge(ctx context.Context, tokenHash string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM two_factor_challenges WHERE token_hash = $1`, tokenHash)
+
+	return err
+}
+
+// expectOne runs statement and returns notFound when it changed no rows
+func (r *TwoFactorRepository) expectOne(ctx context.Context, notFound error, q string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const (
+	webhookColumns  = `id, user_id, url, events, secret, enabled, failures, created_at, updated_at`
+	deliveryColumns = `id, webhook_id, event, payload, status, attempts, response_status, last_error, created_at, updated_at`
+)
+
+type webhookRow struct {
+	WebhookID string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	URL       string    `db:"url"`
+	Events    string    `db:"events"`
+	Secret    string    `db:"secret"`
+	Enabled   bool      `db:"enabled"`
+	Failures  int       `db:"failures"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (w webhookRow) toDomain() (domain.Webhook, error) {
+	var events []string
+	if err := json.Unmarshal([]byte(w.Events), &events); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return domain.Webhook{
+		WebhookID: w.WebhookID,
+		UserID:    w.UserID,
+		URL:       w.URL,
+		Events:    events,
+		Secret:    w.Secret,
+		Enabled:   w.Enabled,
+		Failures:  w.Failures,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}, nil
+}
+
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	q := `INSERT INTO webhooks (id, user_id, url, events, secret, enabled) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + webhookColumns
+
+	return r.getOne(ctx, q, webhook.WebhookID, webhook.UserID, webhook.URL, string(events), webhook.Secret, webhook.Enabled)
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, webhookID string) (domain.Webhook, error) {
+	q := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	return r.getOne(ctx, q, webhookID)
+}
+
+func (r *WebhookRepository) GetWebhooks(ctx context.Context, userID string) ([]domain.Webhook, error) {
+	q := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at`
+
+	return r.getMany(ctx, q, userID)
+}
+
+func (r *WebhookRepository) GetSubscribedWebhooks(ctx context.Context, userID, event string) ([]domain.Webhook, error) {
+	events, err := json.Marshal([]string{event})
+	if err != nil {
+		return nil, err
+	}
+
+	q := `SELECT ` + webhookColumns + ` FROM webhooks
+		WHERE user_id = $1 AND enabled AND events::jsonb @> $2::jsonb ORDER BY created_at`
+
+	return r.getMany(ctx, q, userID, string(events))
+}
+
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	q := `UPDATE webhooks SET url = $1, events = $2, enabled = $3, failures = $4, updated_at = now()
+		WHERE id = $5 AND user_id = $6
+		RETURNING ` + webhookColumns
+
+	return r.getOne(ctx, q, webhook.URL, string(events), webhook.Enabled, webhook.Failures, webhook.WebhookID, webhook.UserID)
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	q := `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, q, webhookID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return response.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, webhookID string, succeeded bool, disableAfter int) (domain.Webhook, error) {
+	q := `UPDATE webhooks SET
+			failures = CASE WHEN $1::boolean THEN 0 ELSE failures + 1 END,
+			enabled = enabled AND ($1 OR failures + 1 < $2),
+			updated_at = now()
+		WHERE id = $3
+		RETURNING ` + webhookColumns
+
+	return r.getOne(ctx, q, succeeded, disableAfter, webhookID)
+}
+
+func (r *WebhookRepository) getOne(ctx context.Context, q string, args ...any) (domain.Webhook, error) {
+	var row webhookRow
+
+	err := r.db.QueryRowxContext(ctx, q, args...).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Webhook{}, response.ErrWebhookNotFound
+	}
+
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return row.toDomain()
+}
+
+func (r *WebhookRepository) getMany(ctx context.Context, q string, args ...any) ([]domain.Webhook, error) {
+	var rows []webhookRow
+
+	if err := r.db.SelectContext(ctx, &rows, q, args...); err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]domain.Webhook, 0, len(rows))
+
+	for _, row := range rows {
+		webhook, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	q := `INSERT INTO webhook_deliveries (id, webhook_id, event, payload, status) VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + deliveryColumns
+
+	var created domain.WebhookDelivery
+
+	err := r.db.QueryRowxContext(ctx, q, delivery.DeliveryID, delivery.WebhookID, delivery.Event,
+		string(delivery.Payload), domain.WebhookDeliveryPending).StructScan(&created)
+
+	return created, err
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	q := `UPDATE webhook_deliveries SET attempts = attempts + 1, locked_until = $1, updated_at = now()
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $2 AND (locked_until IS NULL OR locked_until <= $3)
+			ORDER BY created_at LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+
+	var deliveries []domain.WebhookDelivery
+
+	err := r.db.SelectContext(ctx, &deliveries, q, now.Add(claimTTL), domain.WebhookDeliveryPending, now, limit)
+
+	return deliveries, err
+}
+
+func (r *WebhookRepository) RetryDelivery(ctx context.Context, deliveryID string, responseStatus int, lastError string, retryAt time.Time) error {
+	q := `UPDATE webhook_deliveries SET response_status = $1, last_error = $2, locked_until = $3, updated_at = now() WHERE id = $4`
+
+	_, err := r.db.ExecContext(ctx, q, responseStatus, lastError, retryAt, deliveryID)
+	return err
+}
+
+func (r *WebhookRepository) FinishDelivery(ctx context.Context, deliveryID string, status domain.WebhookDeliveryStatus, responseStatus int, lastError string) error {
+	q := `UPDATE webhook_deliveries SET status = $1, response_status = $2, last_error = $3, updated_at = now() WHERE id = $4`
+
+	_, err := r.db.ExecContext(ctx, q, status, responseStatus, lastError, deliveryID)
+	return err
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID, pageCursor string, limit int) ([]domain.WebhookDelivery, string, error) {
+	base := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE webhook_id = $1`
+
+	var deliveries []domain.WebhookDelivery
+
+	if pageCursor == "" {
+		q := base + ` ORDER BY created_at DESC, id DESC LIMIT $2`
+
+		if err := r.db.SelectContext(ctx, &deliveries, q, webhookID, limit); err != nil {
+			return nil, "", err
+		}
+	} else {
+		createdAt, id, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		q := base + ` AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4`
+
+		if err := r.db.SelectContext(ctx, &deliveries, q, webhookID, createdAt, id, limit); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var nextCursor string
+	if len(deliveries) == limit {
+		last := deliveries[len(deliveries)-1]
+		nextCursor = cursor.Encode(last.CreatedAt, last.DeliveryID)
+	}
+
+	return deliveries, nextCursor, nil
+}
@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/repository/memory"
+	"github.com/Verce11o/yata/internal/repository/postgres"
+	"time"
+)
+
+const (
+	storageMemory   = "memory"
+	storagePostgres = "postgres"
+)
+
+type Bookmarks interface {
+	AddBookmark(ctx context.Context, userID, tweetID string) error
+	DeleteBookmark(ctx context.Context, userID, tweetID string) error
+	GetBookmarks(ctx context.Context, userID, cursor string, limit int) ([]domain.Bookmark, string, error)
+	GetBookmarkedTweets(ctx context.Context, userID string, tweetIDs []string) (map[string]bool, error)
+}
+
+type ScheduledTweets interface {
+	CreateScheduledTweet(ctx context.Context, tweet domain.ScheduledTweet) (domain.ScheduledTweet, error)
+	GetScheduledTweet(ctx context.Context, userID, scheduledID string) (domain.ScheduledTweet, error)
+	GetScheduledTweets(ctx context.Context, userID, cursor string, limit int) ([]domain.ScheduledTweet, string, error)
+	// UpdateScheduledTweet and DeleteScheduledTweet only touch tweets that are still pending
+	UpdateScheduledTweet(ctx context.Context, tweet domain.ScheduledTweet) (domain.ScheduledTweet, error)
+	DeleteScheduledTweet(ctx context.Context, userID, scheduledID string) error
+	// ClaimDueTweets locks due tweets for claimTTL, tweets whose claim expired are returned again
+	ClaimDueTweets(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.ScheduledTweet, error)
+	MarkPublished(ctx context.Context, scheduledID, tweetID string) error
+	RetryLater(ctx context.Context, scheduledID, lastError string, retryAt time.Time) error
+	MarkFailed(ctx context.Context, scheduledID, lastError string) error
+}
+
+type Leases interface {
+	// AcquireLease takes or extends named lease, returns false when someone else holds it
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+type Drafts interface {
+	CreateDraft(ctx context.Context, draft domain.Draft) (domain.Draft, error)
+	GetDraft(ctx context.Context, userID, draftID string) (domain.Draft, error)
+	GetDrafts(ctx context.Context, userID, cursor string, limit int) ([]domain.Draft, string, error)
+	// UpdateDraft succeeds only if stored version equals draft.Version, version is incremented then
+	UpdateDraft(ctx context.Context, draft domain.Draft) (domain.Draft, error)
+	DeleteDraft(ctx context.Context, userID, draftID string) error
+	// LockDraft prevents changes of the draft until it is unlocked or lock expires
+	LockDraft(ctx context.Context, userID, draftID string, until time.Time) (domain.Draft, error)
+	UnlockDraft(ctx context.Context, userID, draftID string) error
+	SetPublishedTweet(ctx context.Context, userID, draftID, tweetID string) error
+}
+
+type Polls interface {
+	CreatePoll(ctx context.Context, poll domain.PollRecord) error
+	GetPolls(ctx context.Context, tweetIDs []string) (map[string]domain.PollRecord, error)
+	GetVotes(ctx context.Context, userID string, tweetIDs []string) (map[string]int, error)
+	Vote(ctx context.Context, tweetID, userID string, option int) error
+	GetVoters(ctx context.Context, tweetID string) ([]string, error)
+	// ClaimClosedPolls locks closed polls for claimTTL, polls not marked notified until the claim expires are returned again
+	ClaimClosedPolls(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.PollRecord, error)
+	MarkPollNotified(ctx context.Context, tweetID string) error
+	DeletePoll(ctx context.Context, tweetID string) error
+}
+
+type TweetRevisions interface {
+	// AddRevision numbers revisions of each tweet starting from 1
+	AddRevision(ctx context.Context, revision domain.TweetRevision) (domain.TweetRevision, error)
+	DeleteRevision(ctx context.Context, tweetID string, revision int) error
+	GetRevisions(ctx context.Context, tweetID string) ([]domain.TweetRevision, error)
+	GetEditStats(ctx context.Context, tweetIDs []string) (map[string]domain.EditStats, error)
+	DeleteRevisions(ctx context.Context, tweetID string) error
+}
+
+type Threads interface {
+	AddReply(ctx context.Context, link domain.ThreadLink) error
+	GetLinks(ctx context.Context, tweetIDs []string) (map[string]domain.ThreadLink, error)
+	// GetReplies returns direct replies of the tweet, oldest first
+	GetReplies(ctx context.Context, tweetID string) ([]domain.ThreadLink, error)
+	DeleteLink(ctx context.Context, tweetID string) error
+}
+
+type Relations interface {
+	AddRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) error
+	DeleteRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) error
+	HasRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) (bool, error)
+	// GetRelated returns users the user has blocked or muted
+	GetRelated(ctx context.Context, userID string, relation domain.RelationType) ([]string, error)
+	// GetRelatedBy returns users who have blocked or muted the user
+	GetRelatedBy(ctx context.Context, targetID string, relation domain.RelationType) ([]string, error)
+}
+
+type AccountSettings interface {
+	// GetSettings returns only stored settings, users missing in result have default ones
+	GetSettings(ctx context.Context, userIDs []string) (map[string]domain.AccountSettings, error)
+	UpdateSettings(ctx context.Context, settings domain.AccountSettings) (domain.AccountSettings, error)
+}
+
+// Follows mirror subscriptions made through the gateway, notifications service cannot list them
+type Follows interface {
+	AddFollow(ctx context.Context, followerID, followeeID string) error
+	DeleteFollow(ctx context.Context, followerID, followeeID string) error
+	// GetFollowedUsers returns which of followeeIDs are followed by followerID
+	GetFollowedUsers(ctx context.Context, followerID string, followeeIDs []string) (map[string]bool, error)
+	// GetFollowingUsers returns which of followerIDs follow followeeID
+	GetFollowingUsers(ctx context.Context, followeeID string, followerIDs []string) (map[string]bool, error)
+	GetFollowers(ctx context.Context, userID, cursor string, limit int) ([]domain.Follow, string, error)
+	GetFollowing(ctx context.Context, userID, cursor string, limit int) ([]domain.Follow, string, error)
+	// GetMutuals returns followers of the user who are followed back
+	GetMutuals(ctx context.Context, userID, cursor string, limit int) ([]domain.Follow, string, error)
+	GetFollowerCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+	GetMostFollowed(ctx context.Context, limit int) ([]string, error)
+}
+
+type FollowRequests interface {
+	CreateFollowRequest(ctx context.Context, requesterID, targetID string) error
+	HasFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error)
+	GetFollowRequests(ctx context.Context, targetID, cursor string, limit int) ([]domain.FollowRequest, string, error)
+	DeleteFollowRequest(ctx context.Context, requesterID, targetID string) error
+}
+
+type NotificationPreferences interface {
+	// GetPreferences returns false if user has never changed preferences
+	GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, bool, error)
+	UpdatePreferences(ctx context.Context, preferences domain.NotificationPreferences) (domain.NotificationPreferences, error)
+}
+
+// Presence keeps live connections of all gateway replicas, user is online while any session has not expired
+type Presence interface {
+	TouchSession(ctx context.Context, sessionID, userID string, ttl time.Duration) error
+	EndSession(ctx context.Context, sessionID, userID string) error
+	GetPresence(ctx context.Context, userID string) (domain.Presence, error)
+}
+
+// Conversations keep direct messages, members of a conversation do not change after it is created
+type Conversations interface {
+	// CreateConversation returns already existing conversation if direct key is taken, groups have empty key
+	CreateConversation(ctx context.Context, conversation domain.Conversation, directKey string) (domain.Conversation, error)
+	GetDirectConversation(ctx context.Context, directKey string) (domain.Conversation, error)
+	GetConversation(ctx context.Context, conversationID string) (domain.Conversation, error)
+	GetConversations(ctx context.Context, userID, cursor string, limit int) ([]domain.Conversation, string, error)
+	CreateMessage(ctx context.Context, message domain.Message) (domain.Message, error)
+	GetMessages(ctx context.Context, conversationID, cursor string, limit int) ([]domain.Message, string, error)
+	// MarkRead moves member's read receipt forward, returns false if it already was at this message or later
+	MarkRead(ctx context.Context, conversationID, userID, messageID string) (time.Time, bool, error)
+}
+
+// Webhooks keep user endpoints and their delivery log, deliveries are claimed like scheduled tweets
+type Webhooks interface {
+	CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	GetWebhook(ctx context.Context, webhookID string) (domain.Webhook, error)
+	GetWebhooks(ctx context.Context, userID string) ([]domain.Webhook, error)
+	// GetSubscribedWebhooks returns enabled webhooks of user subscribed to event
+	GetSubscribedWebhooks(ctx context.Context, userID, event string) ([]domain.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID, webhookID string) error
+	// RecordDelivery resets failures on success, otherwise counts failure and disables webhook
+	// once there are disableAfter failures in a row
+	RecordDelivery(ctx context.Context, webhookID string, succeeded bool, disableAfter int) (domain.Webhook, error)
+	CreateDelivery(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	// ClaimDueDeliveries locks due deliveries for claimTTL and counts the attempt
+	ClaimDueDeliveries(ctx context.Context, now time.Time, claimTTL time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, deliveryID string, responseStatus int, lastError string, retryAt time.Time) error
+	FinishDelivery(ctx context.Context, deliveryID string, status domain.WebhookDeliveryStatus, responseStatus int, lastError string) error
+	GetDeliveries(ctx context.Context, webhookID, cursor string, limit int) ([]domain.WebhookDelivery, string, error)
+}
+
+// Notifications keep notifications produced by the gateway, the rest live in notifications service
+type Notifications interface {
+	CreateNotifications(ctx context.Context, notifications []domain.Notification) error
+	GetNotifications(ctx context.Context, userID string) ([]domain.Notification, error)
+	MarkNotificationAsRead(ctx context.Context, userID, notificationID string) (bool, error)
+	ReadAllNotifications(ctx context.Context, userID string) error
+}
+
+// APIKeys store only hashes of keys, revoked keys are kept, so they stay listed
+type APIKeys interface {
+	CreateAPIKey(ctx context.Context, key domain.APIKey, hash string) (domain.APIKey, error)
+	GetAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error)
+	CountActiveAPIKeys(ctx context.Context, userID string) (int, error)
+	// GetAPIKeyByHash returns only keys that are not revoked
+	GetAPIKeyByHash(ctx context.Context, hash string) (domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
+	TouchAPIKey(ctx context.Context, keyID string, usedAt time.Time) error
+}
+
+type OIDC interface {
+	// CreateState also drops expired states of abandoned sign ins
+	CreateState(ctx context.Context, state domain.OIDCState) error
+	// ConsumeState deletes state, so callback cannot be replayed
+	ConsumeState(ctx context.Context, state string, now time.Time) (domain.OIDCState, error)
+	GetIdentity(ctx context.Context, provider, subject string) (domain.Identity, error)
+	CreateIdentity(ctx context.Context, identity domain.Identity) error
+}
+
+// TwoFactor keeps authenticator secrets, hashes of recovery codes and pending login challenges
+type TwoFactor interface {
+	// GetTwoFactor returns enabled or pending setup of user
+	GetTwoFactor(ctx context.Context, userID string) (domain.TwoFactor, error)
+	// SaveSetup replaces pending setup, enabled one is kept
+	SaveSetup(ctx context.Context, userID, secret string) error
+	EnableTwoFactor(ctx context.Context, userID string, step int64, codeHashes []string) error
+	DeleteTwoFactor(ctx context.Context, userID string) error
+	// UseStep accepts code of step only once and only if it is later than the last accepted one
+	UseStep(ctx context.Context, userID string, step int64) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) error
+	CountRecoveryCodes(ctx context.Context, userID string) (int, error)
+	CreateChallenge(ctx context.Context, challenge domain.TwoFactorChallenge) error
+	// AttemptChallenge counts attempt and returns challenge while it is not expired and has attempts left
+	AttemptChallenge(ctx context.Context, tokenHash string, now time.Time, maxAttempts int) (domain.TwoFactorChallenge, error)
+	DeleteChallenge(ctx context.Context, tokenHash string) error
+}
+
+// Repositories keep data owned by the gateway itself
+type Repositories struct {
+	Bookmarks       Bookmarks
+	ScheduledTweets ScheduledTweets
+	Leases          Leases
+	Drafts          Drafts
+	Polls           Polls
+	Notifications   Notifications
+	TweetRevisions  TweetRevisions
+	Threads         Threads
+	Relations       Relations
+	AccountSettings AccountSettings
+	Follows         Follows
+	FollowRequests  FollowRequests
+	Preferences     NotificationPreferences
+	Presence        Presence
+	Conversations   Conversations
+	Webhooks        Webhooks
+	APIKeys         APIKeys
+	OIDC            OIDC
+	TwoFactor       TwoFactor
+}
+
+func NewRepositories(cfg *config.Config) *Repositories {
+	if cfg.App.Storage == storagePostgres {
+		db := postgres.NewPostgresConnection(cfg.Postgres)
+
+		return &Repositories{
+			Bookmarks:       postgres.NewBookmarkRepository(db),
+			ScheduledTweets: postgres.NewScheduledTweetRepository(db),
+			Leases:          postgres.NewLeaseRepository(db),
+			Drafts:          postgres.NewDraftRepository(db),
+			Polls:           postgres.NewPollRepository(db),
+			Notifications:   postgres.NewNotificationRepository(db),
+			TweetRevisions:  postgres.NewTweetRevisionRepository(db),
+			Threads:         postgres.NewThreadRepository(db),
+			Relations:       postgres.NewRelationRepository(db),
+			AccountSettings: postgres.NewAccountSettingsRepository(db),
+			Follows:         postgres.NewFollowRepository(db),
+			FollowRequests:  postgres.NewFollowRequestRepository(db),
+			Preferences:     postgres.NewNotificationPreferencesRepository(db),
+			Presence:        postgres.NewPresenceRepository(db),
+			Conversations:   postgres.NewConversationRepository(db),
+			Webhooks:        postgres.NewWebhookRepository(db),
+			APIKeys:         postgres.NewAPIKeyRepository(db),
+			OIDC:            postgres.NewOIDCRepository(db),
+			TwoFactor:       postgres.NewTwoFactorRepository(db),
+		}
+	}
+
+	return &Repositories{
+		Bookmarks:       memory.NewBookmarkRepository(),
+		ScheduledTweets: memory.NewScheduledTweetRepository(),
+		Leases:          memory.NewLeaseRepository(),
+		Drafts:          memory.NewDraftRepository(),
+		Polls:           memory.NewPollRepository(),
+		Notifications:   memory.NewNotificationRepository(),
+		TweetRevisions:  memory.NewTweetRevisionRepository(),
+		Threads:         memory.NewThreadRepository(),
+		Relations:       memory.NewRelationRepository(),
+		AccountSettings: memory.NewAccountSettingsRepository(),
+		Follows:         memory.NewFollowRepository(),
+		FollowRequests:  memory.NewFollowRequestRepository(),
+		Preferences:     memory.NewNotificationPreferencesRepository(),
+		Presence:        memory.NewPresenceRepository(),
+		Conversations:   memory.NewConversationRepository(),
+		Webhooks:        memory.NewWebhookRepository(),
+		APIKeys:         memory.NewAPIKeyRepository(),
+		OIDC:            memory.NewOIDCRepository(),
+		TwoFactor:       memory.NewTwoFactorRepository(),
+	}
+}
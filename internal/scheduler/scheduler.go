@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/Verce11o/yata/internal/service"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"sync"
+	"time"
+)
+
+const leaseName = "tweets-scheduler"
+
+// Scheduler publishes due scheduled tweets.
+// Every replica runs it, but only the holder of the lease does the work.
+// A tweet is claimed for a limited time, so if replica dies while publishing,
+// tweet is picked up again after the claim expires, which gives at-least-once delivery.
+type Scheduler struct {
+	log      *zap.SugaredLogger
+	tracer   trace.Tracer
+	cfg      config.Scheduler
+	repo     repository.ScheduledTweets
+	leases   repository.Leases
+	services *service.Services
+	holder   string
+
+	mu    sync.RWMutex
+	state domain.SchedulerState
+}
+
+func NewScheduler(log *zap.SugaredLogger, tracer trace.Tracer, cfg config.Scheduler, repos *repository.Repositories, services *service.Services) *Scheduler {
+	hostname, _ := os.Hostname()
+	holder := fmt.Sprintf("%s-%s", hostname, uuid.New().String())
+
+	return &Scheduler{
+		log:      log,
+		tracer:   tracer,
+		cfg:      cfg,
+		repo:     repos.ScheduledTweets,
+		leases:   repos.Leases,
+		services: services,
+		holder:   holder,
+		state:    domain.SchedulerState{Holder: holder},
+	}
+}
+
+// Run blocks until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	s.update(func(state *domain.SchedulerState) {
+		state.Running = true
+	})
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) State() domain.SchedulerState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.state
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	ctx, span := s.tracer.Start(ctx, "Scheduler.Tick")
+	defer span.End()
+
+	leader, err := s.leases.AcquireLease(ctx, leaseName, s.holder, s.cfg.LeaseTTL)
+	if err != nil {
+		s.log.Errorf("cannot acquire scheduler lease: %v", err)
+		s.update(func(state *domain.SchedulerState) {
+			state.Leader = false
+			state.LastError = err.Error()
+		})
+		return
+	}
+
+	s.update(func(state *domain.SchedulerState) {
+		state.Leader = leader
+	})
+
+	if !leader {
+		return
+	}
+
+	tweets, err := s.repo.ClaimDueTweets(ctx, time.Now(), s.cfg.ClaimTTL, s.cfg.BatchSize)
+	if err != nil {
+		s.log.Errorf("cannot claim scheduled tweets: %v", err)
+		s.update(func(state *domain.SchedulerState) {
+			state.LastError = err.Error()
+		})
+		return
+	}
+
+	s.update(func(state *domain.SchedulerState) {
+		state.Publishing = len(tweets)
+	})
+
+	for _, tweet := range tweets {
+		s.process(ctx, tweet)
+	}
+
+	s.update(func(state *domain.SchedulerState) {
+		state.Publishing = 0
+		state.LastRunAt = time.Now().UTC()
+	})
+}
+
+func (s *Scheduler) process(ctx context.Context, tweet domain.ScheduledTweet) {
+	tweetID, err := s.publish(ctx, tweet)
+
+	if err == nil {
+		if err := s.repo.MarkPublished(ctx, tweet.ScheduledID, tweetID); err != nil {
+			// tweet is published again once the claim expires, that is the price of at-least-once
+			s.log.Errorf("cannot mark scheduled tweet %s as published: %v", tweet.ScheduledID, err)
+		}
+		s.update(func(state *domain.SchedulerState) {
+			state.Published++
+		})
+		return
+	}
+
+	if isTransient(err) && tweet.Attempts < s.cfg.MaxAttempts {
+		s.log.Warnf("cannot publish scheduled tweet %s, attempt %d: %v", tweet.ScheduledID, tweet.Attempts, err)
+
+		if err := s.repo.RetryLater(ctx, tweet.ScheduledID, err.Error(), time.Now().Add(s.backoff(tweet.Attempts))); err != nil {
+			s.log.Errorf("cannot reschedule tweet %s: %v", tweet.ScheduledID, err)
+		}
+		s.update(func(state *domain.SchedulerState) {
+			state.Retried++
+			state.LastError = err.Error()
+		})
+		return
+	}
+
+	s.log.Errorf("cannot publish scheduled tweet %s: %v", tweet.ScheduledID, err)
+
+	if err := s.repo.MarkFailed(ctx, tweet.ScheduledID, err.Error()); err != nil {
+		s.log.Errorf("cannot mark scheduled tweet %s as failed: %v", tweet.ScheduledID, err)
+	}
+	s.update(func(state *domain.SchedulerState) {
+		state.Failed++
+		state.LastError = err.Error()
+	})
+}
+
+func (s *Scheduler) publish(ctx context.Context, tweet domain.ScheduledTweet) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "Scheduler.Publish")
+	defer span.End()
+
+	return service.PublishTweet(ctx, s.log, s.services.Tweets, s.services.Media, tweet.UserID, tweet.Text, tweet.MediaID)
+}
+
+func (s *Scheduler) backoff(attempt int) time.Duration {
+	backoff := s.cfg.RetryBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > s.cfg.MaxRetryBackoff {
+		return s.cfg.MaxRetryBackoff
+	}
+	return backoff
+}
+
+func (s *Scheduler) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.leases.ReleaseLease(ctx, leaseName, s.holder); err != nil {
+		s.log.Errorf("cannot release scheduler lease: %v", err)
+	}
+
+	s.update(func(state *domain.SchedulerState) {
+		state.Running = false
+		state.Leader = false
+	})
+}
+
+func (s *Scheduler) update(apply func(state *domain.SchedulerState)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apply(&s.state)
+}
+
+// isTransient reports whether request could succeed if repeated later
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type AccountService struct {
+	log      *zap.SugaredLogger
+	tracer   trace.Tracer
+	settings repository.AccountSettings
+	follows  repository.Follows
+}
+
+func NewAccountService(log *zap.SugaredLogger, tracer trace.Tracer, settings repository.AccountSettings, follows repository.Follows) *AccountService {
+	return &AccountService{log: log, tracer: tracer, settings: settings, follows: follows}
+}
+
+func (a *AccountService) GetSettings(ctx context.Context, userID string) (domain.AccountSettings, error) {
+	ctx, span := a.tracer.Start(ctx, "Service.GetSettings")
+	defer span.End()
+
+	settings, err := a.settings.GetSettings(ctx, []string{userID})
+	if err != nil {
+		a.log.Errorf("cannot get account settings: %v", err)
+		return domain.AccountSettings{}, err
+	}
+
+	if stored, ok := settings[userID]; ok {
+		return stored, nil
+	}
+
+	return domain.AccountSettings{UserID: userID}, nil
+}
+
+func (a *AccountService) UpdateSettings(ctx context.Context, userID string, input domain.UpdateAccountSettingsInput) (domain.AccountSettings, error) {
+	ctx, span := a.tracer.Start(ctx, "Service.UpdateSettings")
+	defer span.End()
+
+	current, err := a.GetSettings(ctx, userID)
+	if err != nil {
+		return domain.AccountSettings{}, err
+	}
+
+	if input.IsPrivate != nil {
+		current.IsPrivate = *input.IsPrivate
+	}
+
+	if input.AllowMessages != nil {
+		current.AllowMessages = *input.AllowMessages
+	}
+
+	settings, err := a.settings.UpdateSettings(ctx, current)
+
+	if err != nil {
+		a.log.Errorf("cannot update account settings: %v", err)
+		return domain.AccountSettings{}, err
+	}
+
+	return settings, nil
+}
+
+func (a *AccountService) IsPrivate(ctx context.Context, userID string) (bool, error) {
+	settings, err := a.GetSettings(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return settings.IsPrivate, nil
+}
+
+// CheckVisible returns response.ErrPrivateAccount if viewer cannot see author's tweets
+func (a *AccountService) CheckVisible(ctx context.Context, viewerID, authorID string) error {
+	protected, err := a.GetProtectedAuthors(ctx, viewerID, []string{authorID})
+	if err != nil {
+		return err
+	}
+
+	if _, ok := protected[authorID]; ok {
+		return response.ErrPrivateAccount
+	}
+
+	return nil
+}
+
+// GetProtectedAuthors returns private authors viewer does not follow
+func (a *AccountService) GetProtectedAuthors(ctx context.Context, viewerID string, authorIDs []string) (map[string]struct{}, error) {
+	ctx, span := a.tracer.Start(ctx, "Service.GetProtectedAuthors")
+	defer span.End()
+
+	protected := make(map[string]struct{})
+
+	candidates := make([]string, 0, len(authorIDs))
+	seen := make(map[string]bool, len(authorIDs))
+
+	for _, authorID := range authorIDs {
+		if authorID == viewerID || seen[authorID] {
+			continue
+		}
+		seen[authorID] = true
+		candidates = append(candidates, authorID)
+	}
+
+	if len(candidates) == 0 {
+		return protected, nil
+	}
+
+	settings, err := a.settings.GetSettings(ctx, candidates)
+	if err != nil {
+		a.log.Errorf("cannot get account settings: %v", err)
+		return nil, err
+	}
+
+	private := make([]string, 0, len(settings))
+	for userID, item := range settings {
+		if item.IsPrivate {
+			private = append(private, userID)
+		}
+	}
+
+	if len(private) == 0 {
+		return protected, nil
+	}
+
+	followed, err := a.follows.GetFollowedUsers(ctx, viewerID, private)
+	if err != nil {
+		a.log.Errorf("cannot get followed users: %v", err)
+		return nil, err
+	}
+
+	for _, userID := range private {
+		if !followed[userID] {
+			protected[userID] = struct{}{}
+		}
+	}
+
+	return protected, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	apiKeyPrefix      = "yata_"
+	apiKeySecretBytes = 32
+	// apiKeyPrefixLength is the visible part of key, enough to tell keys apart in the list
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+	// apiKeyTouchInterval limits last used writes to one per key in a while
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	repo   repository.APIKeys
+}
+
+func NewAPIKeyService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.APIKeys) *APIKeyService {
+	return &APIKeyService{log: log, tracer: tracer, repo: repo}
+}
+
+// CreateAPIKey returns the key itself, it cannot be shown again
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, userID string, input domain.CreateAPIKeyInput) (domain.APIKey, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.CreateAPIKey")
+	defer span.End()
+
+	count, err := s.repo.CountActiveAPIKeys(ctx, userID)
+	if err != nil {
+		s.log.Errorf("cannot count api keys: %v", err)
+		return domain.APIKey{}, err
+	}
+
+	if count >= domain.MaxAPIKeys {
+		return domain.APIKey{}, response.ErrTooManyAPIKeys
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+
+	created, err := s.repo.CreateAPIKey(ctx, domain.APIKey{
+		KeyID:  uuid.NewString(),
+		UserID: userID,
+		Name:   input.Name,
+		Prefix: key[:apiKeyPrefixLength],
+		Scopes: input.Scopes,
+	}, hashSecret(key))
+
+	if err != nil {
+		s.log.Errorf("cannot create api key: %v", err)
+		return domain.APIKey{}, err
+	}
+
+	created.Key = key
+
+	return created, nil
+}
+
+func (s *APIKeyService) GetAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetAPIKeys")
+	defer span.End()
+
+	keys, err := s.repo.GetAPIKeys(ctx, userID)
+	if err != nil {
+		s.log.Errorf("cannot get api keys: %v", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	ctx, span := s.tracer.Start(ctx, "Service.RevokeAPIKey")
+	defer span.End()
+
+	return s.repo.RevokeAPIKey(ctx, userID, keyID)
+}
+
+// Authenticate returns active key matching provided one and records its use
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (domain.APIKey, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.Authenticate")
+	defer span.End()
+
+	if len(key) <= apiKeyPrefixLength || key[:len(apiKeyPrefix)] != apiKeyPrefix {
+		return domain.APIKey{}, response.ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.repo.GetAPIKeyByHash(ctx, hashSecret(key))
+	if errors.Is(err, response.ErrAPIKeyNotFound) {
+		return domain.APIKey{}, response.ErrInvalidAPIKey
+	}
+
+	if err != nil {
+		s.log.Errorf("cannot get api key: %v", err)
+		return domain.APIKey{}, err
+	}
+
+	now := time.Now()
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		// last used time is informational, so request is not failed because of it
+		if err := s.repo.TouchAPIKey(ctx, apiKey.KeyID, now); err != nil {
+			s.log.Errorf("cannot update api key last use: %v", err)
+		}
+		apiKey.LastUsedAt = &now
+	}
+
+	return apiKey, nil
+}
+
+// hashSecret is used for random high entropy secrets only, unlike passwords they do not need salt or slow hash
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
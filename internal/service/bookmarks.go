@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type BookmarkService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	repo   repository.Bookmarks
+	tweets Tweet
+}
+
+func NewBookmarkService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.Bookmarks, tweets Tweet) *BookmarkService {
+	return &BookmarkService{log: log, tracer: tracer, repo: repo, tweets: tweets}
+}
+
+func (b *BookmarkService) AddBookmark(ctx context.Context, userID, tweetID string) error {
+	ctx, span := b.tracer.Start(ctx, "Service.AddBookmark")
+	defer span.End()
+
+	if _, err := b.tweets.GetTweet(ctx, tweetID); err != nil {
+		return err
+	}
+
+	if err := b.repo.AddBookmark(ctx, userID, tweetID); err != nil {
+		b.log.Errorf("cannot add bookmark: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (b *BookmarkService) DeleteBookmark(ctx context.Context, userID, tweetID string) error {
+	ctx, span := b.tracer.Start(ctx, "Service.DeleteBookmark")
+	defer span.End()
+
+	if err := b.repo.DeleteBookmark(ctx, userID, tweetID); err != nil {
+		b.log.Errorf("cannot delete bookmark: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetBookmarks returns bookmarked tweets, most recently bookmarked first
+func (b *BookmarkService) GetBookmarks(ctx context.Context, userID, cursor string, limit int) ([]domain.TweetResponse, string, error) {
+	ctx, span := b.tracer.Start(ctx, "Service.GetBookmarks")
+	defer span.End()
+
+	bookmarks, cursor, err := b.repo.GetBookmarks(ctx, userID, cursor, pageLimit(limit))
+	if err != nil {
+		b.log.Errorf("cannot get bookmarks: %v", err)
+		return nil, "", err
+	}
+
+	result := make([]domain.TweetResponse, 0, len(bookmarks))
+
+	for _, bookmark := range bookmarks {
+		tweet, err := b.tweets.GetTweet(ctx, bookmark.TweetID)
+
+		// tweet could be deleted after it was bookmarked
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		tweet.BookmarkedByMe = true
+		result = append(result, tweet)
+	}
+
+	return result, cursor, nil
+}
+
+func (b *BookmarkService) GetBookmarkedTweets(ctx context.Context, userID string, tweetIDs []string) (map[string]bool, error) {
+	ctx, span := b.tracer.Start(ctx, "Service.GetBookmarkedTweets")
+	defer span.End()
+
+	bookmarked, err := b.repo.GetBookmarkedTweets(ctx, userID, tweetIDs)
+	if err != nil {
+		b.log.Errorf("cannot get bookmarked tweets: %v", err)
+		return nil, err
+	}
+
+	return bookmarked, nil
+}
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	return min(limit, maxPageLimit)
+}
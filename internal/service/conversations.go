@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/realtime"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+type ConversationService struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	repo      repository.Conversations
+	follows   repository.Follows
+	relations Relation
+	accounts  Account
+	auth      Auth
+	publisher Publisher
+}
+
+func NewConversationService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.Conversations, follows repository.Follows, relations Relation, accounts Account, auth Auth, publisher Publisher) *ConversationService {
+	return &ConversationService{log: log, tracer: tracer, repo: repo, follows: follows, relations: relations, accounts: accounts, auth: auth, publisher: publisher}
+}
+
+// CreateConversation returns existing direct conversation if there is one already
+func (s *ConversationService) CreateConversation(ctx context.Context, userID string, input domain.CreateConversationInput) (domain.Conversation, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.CreateConversation")
+	defer span.End()
+
+	recipients := make([]string, 0, len(input.UserIDs))
+	for _, recipientID := range input.UserIDs {
+		if recipientID != userID {
+			recipients = append(recipients, recipientID)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return domain.Conversation{}, response.ErrInvalidRequest
+	}
+
+	for _, recipientID := range recipients {
+		if _, err := s.auth.GetUserByID(ctx, recipientID); err != nil {
+			s.log.Debugf("cannot get user %s: %v", recipientID, err)
+			return domain.Conversation{}, response.ErrUserNotFound
+		}
+	}
+
+	if err := s.checkAllowed(ctx, userID, recipients); err != nil {
+		return domain.Conversation{}, err
+	}
+
+	members := append([]string{userID}, recipients...)
+	sort.Strings(members)
+
+	conversation := domain.Conversation{
+		ConversationID: uuid.NewString(),
+		CreatorID:      userID,
+		IsGroup:        len(recipients) > 1,
+	}
+
+	for _, memberID := range members {
+		conversation.Members = append(conversation.Members, domain.ConversationMember{UserID: memberID})
+	}
+
+	var directKey string
+	if !conversation.IsGroup {
+		directKey = strings.Join(members, ":")
+	}
+
+	created, err := s.repo.CreateConversation(ctx, conversation, directKey)
+	if err != nil {
+		s.log.Errorf("cannot create conversation: %v", err)
+		return domain.Conversation{}, err
+	}
+
+	return created, nil
+}
+
+func (s *ConversationService) GetConversations(ctx context.Context, userID, cursor string, limit int) ([]domain.Conversation, string, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetConversations")
+	defer span.End()
+
+	conversations, nextCursor, err := s.repo.GetConversations(ctx, userID, cursor, pageLimit(limit))
+	if err != nil {
+		s.log.Errorf("cannot get conversations: %v", err)
+		return nil, "", err
+	}
+
+	return conversations, nextCursor, nil
+}
+
+// GetConversation hides conversations user is not a member of
+func (s *ConversationService) GetConversation(ctx context.Context, userID, conversationID string) (domain.Conversation, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetConversation")
+	defer span.End()
+
+	conversation, err := s.repo.GetConversation(ctx, conversationID)
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+
+	for _, member := range conversation.Members {
+		if member.UserID == userID {
+			return conversation, nil
+		}
+	}
+
+	return domain.Conversation{}, response.ErrConversationNotFound
+}
+
+// SendMessage checks permissions again for direct conversations since recipient may unfollow sender,
+// group members are checked once when group is created
+func (s *ConversationService) SendMessage(ctx context.Context, userID, conversationID string, input domain.SendMessageInput) (domain.Message, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.SendMessage")
+	defer span.End()
+
+	conversation, err := s.GetConversation(ctx, userID, conversationID)
+	if err != nil {
+		return domain.Message{}, err
+	}
+
+	if !conversation.IsGroup {
+		if err := s.checkAllowed(ctx, userID, otherMembers(conversation, userID)); err != nil {
+			return domain.Message{}, err
+		}
+	}
+
+	message, err := s.repo.CreateMessage(ctx, domain.Message{
+		MessageID:      uuid.NewString(),
+		ConversationID: conversationID,
+		SenderID:       userID,
+		Text:           input.Text,
+	})
+
+	if err != nil {
+		s.log.Errorf("cannot create message: %v", err)
+		return domain.Message{}, err
+	}
+
+	s.publish(conversation, realtime.EventMessage, message)
+
+	return message, nil
+}
+
+func (s *ConversationService) GetMessages(ctx context.Context, userID, conversationID, cursor string, limit int) ([]domain.Message, string, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetMessages")
+	defer span.End()
+
+	if _, err := s.GetConversation(ctx, userID, conversationID); err != nil {
+		return nil, "", err
+	}
+
+	messages, nextCursor, err := s.repo.GetMessages(ctx, conversationID, cursor, pageLimit(limit))
+	if err != nil {
+		s.log.Errorf("cannot get messages: %v", err)
+		return nil, "", err
+	}
+
+	return messages, nextCursor, nil
+}
+
+// MarkRead moves user's read receipt to the message and lets other members know
+func (s *ConversationService) MarkRead(ctx context.Context, userID, conversationID, messageID string) error {
+	ctx, span := s.tracer.Start(ctx, "Service.MarkRead")
+	defer span.End()
+
+	conversation, err := s.GetConversation(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	readAt, moved, err := s.repo.MarkRead(ctx, conversationID, userID, messageID)
+	if err != nil {
+		s.log.Errorf("cannot mark messages as read: %v", err)
+		return err
+	}
+
+	if moved {
+		s.publish(conversation, realtime.EventReadReceipt, domain.ReadReceipt{
+			ConversationID: conversationID,
+			UserID:         userID,
+			MessageID:      messageID,
+			ReadAt:         readAt,
+		})
+	}
+
+	return nil
+}
+
+// checkAllowed lets sender message recipients who follow sender or accept messages from everyone
+func (s *ConversationService) checkAllowed(ctx context.Context, senderID string, recipients []string) error {
+	for _, recipientID := range recipients {
+		if err := s.relations.CheckBlocked(ctx, recipientID, senderID); err != nil {
+			return err
+		}
+	}
+
+	followers, err := s.follows.GetFollowingUsers(ctx, senderID, recipients)
+	if err != nil {
+		s.log.Errorf("cannot get followers: %v", err)
+		return err
+	}
+
+	for _, recipientID := range recipients {
+		if followers[recipientID] {
+			continue
+		}
+
+		settings, err := s.accounts.GetSettings(ctx, recipientID)
+		if err != nil {
+			return err
+		}
+
+		if !settings.AllowMessages {
+			return response.ErrMessagesNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// publish sends event to every member, sender included, so their other devices stay in sync
+func (s *ConversationService) publish(conversation domain.Conversation, eventType string, data any) {
+	for _, member := range conversation.Members {
+		s.publisher.Publish(member.UserID, realtime.Event{Type: eventType, Data: data})
+	}
+}
+
+func otherMembers(conversation domain.Conversation, userID string) []string {
+	others := make([]string, 0, len(conversation.Members))
+	for _, member := range conversation.Members {
+		if member.UserID != userID {
+			others = append(others, member.UserID)
+		}
+	}
+
+	return others
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// draftPublishTimeout bounds how long draft stays locked if gateway dies while publishing it
+const draftPublishTimeout = time.Minute
+
+type DraftService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	repo   repository.Drafts
+	tweets Tweet
+	media  Media
+}
+
+func NewDraftService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.Drafts, tweets Tweet, media Media) *DraftService {
+	return &DraftService{log: log, tracer: tracer, repo: repo, tweets: tweets, media: media}
+}
+
+func (d *DraftService) CreateDraft(ctx context.Context, userID string, input domain.CreateDraftInput) (domain.Draft, error) {
+	ctx, span := d.tracer.Start(ctx, "Service.CreateDraft")
+	defer span.End()
+
+	if err := d.checkMedia(ctx, userID, input.MediaIDs); err != nil {
+		return domain.Draft{}, err
+	}
+
+	draft, err := d.repo.CreateDraft(ctx, domain.Draft{
+		DraftID:  uuid.New().String(),
+		UserID:   userID,
+		Text:     input.Text,
+		MediaIDs: mediaIDs(input.MediaIDs),
+	})
+
+	if err != nil {
+		d.log.Errorf("cannot create draft: %v", err)
+		return domain.Draft{}, err
+	}
+
+	return draft, nil
+}
+
+func (d *DraftService) GetDraft(ctx context.Context, userID, draftID string) (domain.Draft, error) {
+	ctx, span := d.tracer.Start(ctx, "Service.GetDraft")
+	defer span.End()
+
+	return d.repo.GetDraft(ctx, userID, draftID)
+}
+
+func (d *DraftService) GetDrafts(ctx context.Context, userID, cursor string, limit int) ([]domain.Draft, string, error) {
+	ctx, span := d.tracer.Start(ctx, "Service.GetDrafts")
+	defer span.End()
+
+	drafts, cursor, err := d.repo.GetDrafts(ctx, userID, cursor, pageLimit(limit))
+	if err != nil {
+		d.log.Errorf("cannot get drafts: %v", err)
+		return nil, "", err
+	}
+
+	return drafts, cursor, nil
+}
+
+// UpdateDraft returns current draft along with ErrDraftVersionConflict, so client can merge changes
+func (d *DraftService) UpdateDraft(ctx context.Context, userID, draftID string, input domain.UpdateDraftInput) (domain.Draft, error) {
+	ctx, span := d.tracer.Start(ctx, "Service.UpdateDraft")
+	defer span.End()
+
+	if err := d.checkMedia(ctx, userID, input.MediaIDs); err != nil {
+		return domain.Draft{}, err
+	}
+
+	return d.repo.UpdateDraft(ctx, domain.Draft{
+		DraftID:  draftID,
+		UserID:   userID,
+		Text:     input.Text,
+		MediaIDs: mediaIDs(input.MediaIDs),
+		Version:  input.Version,
+	})
+}
+
+func (d *DraftService) DeleteDraft(ctx context.Context, userID, draftID string) error {
+	ctx, span := d.tracer.Start(ctx, "Service.DeleteDraft")
+	defer span.End()
+
+	if _, err := d.repo.LockDraft(ctx, userID, draftID, time.Now().Add(draftPublishTimeout)); err != nil {
+		return err
+	}
+
+	if err := d.repo.DeleteDraft(ctx, userID, draftID); err != nil {
+		d.log.Errorf("cannot delete draft: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// PublishDraft turns draft into a tweet. Draft is locked while tweet is being created
+// and is only deleted after the tweet exists, so failure at any step leaves it in place.
+// Tweet id is saved before deletion, repeated publish of such draft doesn't create second tweet.
+func (d *DraftService) PublishDraft(ctx context.Context, userID, draftID string) (string, error) {
+	ctx, span := d.tracer.Start(ctx, "Service.PublishDraft")
+	defer span.End()
+
+	draft, err := d.repo.LockDraft(ctx, userID, draftID, time.Now().Add(draftPublishTimeout))
+	if err != nil {
+		return "", err
+	}
+
+	tweetID := draft.PublishedTweetID
+
+	if tweetID == "" {
+		if strings.TrimSpace(draft.Text) == "" {
+			d.unlock(ctx, userID, draftID)
+			return "", response.ErrEmptyTweet
+		}
+
+		var mediaID string
+		if len(draft.MediaIDs) > 0 {
+			mediaID = draft.MediaIDs[0]
+		}
+
+		tweetID, err = PublishTweet(ctx, d.log, d.tweets, d.media, userID, draft.Text, mediaID)
+		if err != nil {
+			d.log.Errorf("cannot publish draft: %v", err)
+			d.unlock(ctx, userID, draftID)
+			return "", err
+		}
+
+		if err := d.repo.SetPublishedTweet(ctx, userID, draftID, tweetID); err != nil {
+			d.log.Errorf("cannot save published tweet of draft %s: %v", draftID, err)
+		}
+	}
+
+	// tweet is created, so failed cleanup is not an error for the user
+	if err := d.repo.DeleteDraft(ctx, userID, draftID); err != nil {
+		d.log.Errorf("cannot delete published draft %s: %v", draftID, err)
+	}
+
+	return tweetID, nil
+}
+
+func (d *DraftService) unlock(ctx context.Context, userID, draftID string) {
+	if err := d.repo.UnlockDraft(ctx, userID, draftID); err != nil {
+		d.log.Errorf("cannot unlock draft %s: %v", draftID, err)
+	}
+}
+
+func (d *DraftService) checkMedia(ctx context.Context, userID string, mediaIDs []string) error {
+	for _, mediaID := range mediaIDs {
+		if _, err := d.media.GetImage(ctx, userID, mediaID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mediaIDs(ids []string) []string {
+	if ids == nil {
+		return make([]string, 0)
+	}
+	return ids
+}
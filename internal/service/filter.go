@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+)
+
+// CheckAccess tells whether viewer may see and interact with owner's tweets and profile
+func (s *Services) CheckAccess(ctx context.Context, viewerID, ownerID string) error {
+	return checkAccess(ctx, s.Relations, s.Accounts, viewerID, ownerID)
+}
+
+func checkAccess(ctx context.Context, relations Relation, accounts Account, viewerID, ownerID string) error {
+	if err := relations.CheckBlocked(ctx, ownerID, viewerID); err != nil {
+		return err
+	}
+
+	return accounts.CheckVisible(ctx, viewerID, ownerID)
+}
+
+// FilterTweets drops tweets of users hidden from viewer by blocks and mutes,
+// and tweets of private accounts viewer does not follow
+func (s *Services) FilterTweets(ctx context.Context, viewerID string, tweets []domain.TweetResponse) ([]domain.TweetResponse, error) {
+	hidden, err := s.Relations.GetHiddenUsers(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	authorIDs := make([]string, 0, len(tweets))
+	for _, tweet := range tweets {
+		authorIDs = append(authorIDs, tweet.UserID)
+	}
+
+	protected, err := s.Accounts.GetProtectedAuthors(ctx, viewerID, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.TweetResponse, 0, len(tweets))
+	for _, tweet := range tweets {
+		_, isHidden := hidden[tweet.UserID]
+		_, isProtected := protected[tweet.UserID]
+
+		if !isHidden && !isProtected {
+			result = append(result, tweet)
+		}
+	}
+
+	return result, nil
+}
+
+// FilterComments drops comments of users hidden from viewer and of private accounts viewer does not follow,
+// access to the commented tweet itself is checked by the caller
+func (s *Services) FilterComments(ctx context.Context, viewerID string, comments []domain.CommentResponse) ([]domain.CommentResponse, error) {
+	hidden, err := s.Relations.GetHiddenUsers(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	authorIDs := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		authorIDs = append(authorIDs, comment.UserID)
+	}
+
+	protected, err := s.Accounts.GetProtectedAuthors(ctx, viewerID, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		_, isHidden := hidden[comment.UserID]
+		_, isProtected := protected[comment.UserID]
+
+		if !isHidden && !isProtected {
+			result = append(result, comment)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Services) FilterNotifications(ctx context.Context, userID string, notifications []domain.Notification) ([]domain.Notification, error) {
+	hidden, err := s.Relations.GetHiddenUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if _, ok := hidden[notification.SenderID]; !ok {
+			result = append(result, notification)
+		}
+	}
+
+	return result, nil
+}
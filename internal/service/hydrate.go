@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+)
+
+// HydrateTweets fills tweets with data kept by the gateway itself as seen by viewer
+func (s *Services) HydrateTweets(ctx context.Context, viewerID string, tweets []domain.TweetResponse) error {
+	tweetIDs := make([]string, 0, len(tweets))
+
+	for i := range tweets {
+		media, err := s.Media.GetAttachment(ctx, domain.TweetAttachment, tweets[i].TweetID)
+		if err != nil {
+			return err
+		}
+		tweets[i].Media = media
+		tweetIDs = append(tweetIDs, tweets[i].TweetID)
+	}
+
+	bookmarked, err := s.Bookmarks.GetBookmarkedTweets(ctx, viewerID, tweetIDs)
+	if err != nil {
+		return err
+	}
+
+	polls, err := s.Polls.GetPolls(ctx, viewerID, tweetIDs)
+	if err != nil {
+		return err
+	}
+
+	edits, err := s.Revisions.GetEditStats(ctx, tweetIDs)
+	if err != nil {
+		return err
+	}
+
+	links, err := s.Threads.GetLinks(ctx, tweetIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range tweets {
+		tweets[i].BookmarkedByMe = bookmarked[tweets[i].TweetID]
+		tweets[i].Poll = polls[tweets[i].TweetID]
+
+		tweets[i].ConversationID = tweets[i].TweetID
+		if link, ok := links[tweets[i].TweetID]; ok {
+			tweets[i].InReplyTo = link.InReplyToTweetID
+			tweets[i].ConversationID = link.ConversationID
+		}
+
+		if stats, ok := edits[tweets[i].TweetID]; ok {
+			editedAt := stats.EditedAt
+			tweets[i].EditCount = stats.Count
+			tweets[i].EditedAt = &editedAt
+		}
+	}
+
+	return nil
+}
+
+func (s *Services) HydrateTweet(ctx context.Context, viewerID string, tweet *domain.TweetResponse) error {
+	tweets := []domain.TweetResponse{*tweet}
+	err := s.HydrateTweets(ctx, viewerID, tweets)
+	*tweet = tweets[0]
+	return err
+}
+
+func (s *Services) HydrateComments(ctx context.Context, comments []domain.CommentResponse) error {
+	for i := range comments {
+		media, err := s.Media.GetAttachment(ctx, domain.CommentAttachment, comments[i].CommentID)
+		if err != nil {
+			return err
+		}
+		comments[i].Media = media
+	}
+
+	return nil
+}
+
+func (s *Services) HydrateComment(ctx context.Context, comment *domain.CommentResponse) error {
+	comments := []domain.CommentResponse{*comment}
+	err := s.HydrateComments(ctx, comments)
+	*comment = comments[0]
+	return err
+}
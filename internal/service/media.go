@@ -0,0 +1,595 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/files"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/lib/signature"
+	"github.com/Verce11o/yata/internal/storage"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	uploadsPrefix     = "uploads/"
+	mediaPrefix       = "media/"
+	attachmentsPrefix = "attachments/"
+	sessionName       = "session"
+	chunkPrefix       = "chunk-"
+)
+
+type MediaService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	store  storage.BlobStore
+	cfg    config.Media
+}
+
+func NewMediaService(log *zap.SugaredLogger, tracer trace.Tracer, store storage.BlobStore, cfg config.Media) *MediaService {
+	return &MediaService{log: log, tracer: tracer, store: store, cfg: cfg}
+}
+
+func (m *MediaService) InitUpload(ctx context.Context, userID string, input domain.InitMediaUploadInput) (domain.MediaUpload, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.InitUpload")
+	defer span.End()
+
+	if !files.IsAllowedMime(input.ContentType) {
+		return domain.MediaUpload{}, response.ErrInvalidImage
+	}
+
+	if input.Size > m.maxSize(input.ContentType) {
+		return domain.MediaUpload{}, response.ErrMediaTooLarge
+	}
+
+	// private media can only be served by signed urls
+	if input.Private && m.cfg.SignSecret == "" {
+		return domain.MediaUpload{}, response.ErrInvalidRequest
+	}
+
+	now := time.Now().UTC()
+
+	upload := domain.MediaUpload{
+		UploadID:    uuid.NewString(),
+		UserID:      userID,
+		ContentType: input.ContentType,
+		Size:        input.Size,
+		Private:     input.Private,
+		ExpiresAt:   now.Add(m.cfg.UploadTTL),
+		CreatedAt:   now,
+	}
+
+	if err := m.saveSession(ctx, upload); err != nil {
+		m.log.Errorf("cannot init upload: %v", err)
+		return domain.MediaUpload{}, err
+	}
+
+	return upload, nil
+}
+
+func (m *MediaService) AppendChunk(ctx context.Context, userID, uploadID string, offset int64, chunk []byte) (domain.MediaUpload, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.AppendChunk")
+	defer span.End()
+
+	upload, err := m.getSession(ctx, userID, uploadID)
+	if err != nil {
+		return domain.MediaUpload{}, err
+	}
+
+	// client has to resume from the offset we already have
+	if offset != upload.Offset {
+		return upload, response.ErrInvalidOffset
+	}
+
+	if len(chunk) == 0 {
+		return upload, response.ErrInvalidRequest
+	}
+
+	if int64(len(chunk)) > m.cfg.MaxChunkSize || offset+int64(len(chunk)) > upload.Size {
+		return upload, response.ErrMediaTooLarge
+	}
+
+	_, err = m.store.Put(ctx, chunkKey(uploadID, offset), bytes.NewReader(chunk), int64(len(chunk)), "application/octet-stream", nil)
+	if err != nil {
+		m.log.Errorf("cannot store upload chunk: %v", err)
+		return domain.MediaUpload{}, err
+	}
+
+	upload.Offset += int64(len(chunk))
+	upload.ExpiresAt = time.Now().UTC().Add(m.cfg.UploadTTL)
+
+	if err := m.saveSession(ctx, upload); err != nil {
+		m.log.Errorf("cannot update upload session: %v", err)
+		return domain.MediaUpload{}, err
+	}
+
+	return upload, nil
+}
+
+func (m *MediaService) CompleteUpload(ctx context.Context, userID, uploadID string) (domain.Media, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.CompleteUpload")
+	defer span.End()
+
+	upload, err := m.getSession(ctx, userID, uploadID)
+	if err != nil {
+		return domain.Media{}, err
+	}
+
+	if upload.Offset != upload.Size {
+		return domain.Media{}, response.ErrUploadIncomplete
+	}
+
+	chunks, err := m.store.List(ctx, uploadPrefix(uploadID)+chunkPrefix)
+	if err != nil {
+		m.log.Errorf("cannot list upload chunks: %v", err)
+		return domain.Media{}, err
+	}
+
+	readers := make([]io.Reader, 0, len(chunks))
+
+	for _, chunk := range chunks {
+		reader, _, err := m.store.Get(ctx, chunk.Key)
+		if err != nil {
+			m.log.Errorf("cannot read upload chunk: %v", err)
+			return domain.Media{}, err
+		}
+		defer reader.Close()
+
+		readers = append(readers, reader)
+	}
+
+	// containers can keep their headers at the end, so media is assembled in a local file to be probed
+	tmp, err := os.CreateTemp("", "yata-media-*")
+	if err != nil {
+		m.log.Errorf("cannot create temp file: %v", err)
+		return domain.Media{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.MultiReader(readers...))
+	if err != nil {
+		m.log.Errorf("cannot assemble upload: %v", err)
+		return domain.Media{}, err
+	}
+
+	// sniff real content type instead of trusting the one declared on init
+	header := make([]byte, 512)
+	n, err := tmp.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		m.log.Errorf("cannot read upload header: %v", err)
+		return domain.Media{}, err
+	}
+
+	contentType, err := files.DetectMediaType(header[:n])
+	if err != nil {
+		return domain.Media{}, err
+	}
+
+	if size > m.maxSize(contentType) {
+		return domain.Media{}, response.ErrMediaTooLarge
+	}
+
+	metadata, err := m.probe(ctx, userID, upload.Private, tmp, size, contentType)
+	if err != nil {
+		return domain.Media{}, err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		m.log.Errorf("cannot rewind upload: %v", err)
+		return domain.Media{}, err
+	}
+
+	mediaID := uuid.NewString()
+
+	info, err := m.store.Put(ctx, mediaPrefix+mediaID, tmp, size, contentType, metadata)
+	if err != nil {
+		m.log.Errorf("cannot store media: %v", err)
+		return domain.Media{}, err
+	}
+
+	if err := m.deleteUpload(ctx, uploadID); err != nil {
+		// media is already stored, leftovers will be collected by gc
+		m.log.Errorf("cannot delete completed upload: %v", err)
+	}
+
+	return m.toMedia(mediaID, info), nil
+}
+
+// GetImage returns nil image for media which can't be passed to backend services
+func (m *MediaService) GetImage(ctx context.Context, userID, mediaID string) (*domain.Image, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.GetImage")
+	defer span.End()
+
+	if _, err := uuid.Parse(mediaID); err != nil {
+		return nil, response.ErrMediaNotFound
+	}
+
+	reader, info, err := m.store.Get(ctx, mediaPrefix+mediaID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, response.ErrMediaNotFound
+	}
+	if err != nil {
+		m.log.Errorf("cannot get media: %v", err)
+		return nil, err
+	}
+	defer reader.Close()
+
+	if info.Metadata["owner"] != userID {
+		return nil, response.ErrMediaNotFound
+	}
+
+	// backend services store images only, videos live in gateway storage
+	if files.IsVideo(info.ContentType) {
+		return nil, nil
+	}
+
+	chunk, err := io.ReadAll(reader)
+	if err != nil {
+		m.log.Errorf("cannot read media: %v", err)
+		return nil, err
+	}
+
+	return &domain.Image{
+		ContentType: info.ContentType,
+		Chunk:       chunk,
+		ImageName:   files.MediaName(mediaID, info.ContentType),
+	}, nil
+}
+
+// StoreImage keeps image uploaded directly with tweet or comment, so it can be served back
+func (m *MediaService) StoreImage(ctx context.Context, userID string, image *domain.Image) (string, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.StoreImage")
+	defer span.End()
+
+	metadata, err := m.probe(ctx, userID, false, bytes.NewReader(image.Chunk), int64(len(image.Chunk)), image.ContentType)
+	if err != nil {
+		return "", err
+	}
+
+	mediaID := uuid.NewString()
+
+	_, err = m.store.Put(ctx, mediaPrefix+mediaID, bytes.NewReader(image.Chunk), int64(len(image.Chunk)), image.ContentType, metadata)
+	if err != nil {
+		m.log.Errorf("cannot store image: %v", err)
+		return "", err
+	}
+
+	image.ImageName = files.MediaName(mediaID, image.ContentType)
+
+	return mediaID, nil
+}
+
+func (m *MediaService) Attach(ctx context.Context, kind domain.AttachmentKind, ownerID, mediaID string) error {
+	ctx, span := m.tracer.Start(ctx, "Service.Attach")
+	defer span.End()
+
+	_, err := m.store.Put(ctx, attachmentKey(kind, ownerID), strings.NewReader(mediaID), int64(len(mediaID)), "text/plain", nil)
+	if err != nil {
+		m.log.Errorf("cannot attach media: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (m *MediaService) Detach(ctx context.Context, kind domain.AttachmentKind, ownerID string) error {
+	ctx, span := m.tracer.Start(ctx, "Service.Detach")
+	defer span.End()
+
+	if err := m.store.Delete(ctx, attachmentKey(kind, ownerID)); err != nil {
+		m.log.Errorf("cannot detach media: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAttachment returns nil if nothing is attached
+func (m *MediaService) GetAttachment(ctx context.Context, kind domain.AttachmentKind, ownerID string) (*domain.Media, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.GetAttachment")
+	defer span.End()
+
+	reader, _, err := m.store.Get(ctx, attachmentKey(kind, ownerID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		m.log.Errorf("cannot get attachment: %v", err)
+		return nil, err
+	}
+	defer reader.Close()
+
+	mediaID, err := io.ReadAll(reader)
+	if err != nil {
+		m.log.Errorf("cannot read attachment: %v", err)
+		return nil, err
+	}
+
+	return m.GetMedia(ctx, string(mediaID))
+}
+
+// GetMedia returns nil if media doesn't exist anymore
+func (m *MediaService) GetMedia(ctx context.Context, mediaID string) (*domain.Media, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.GetMedia")
+	defer span.End()
+
+	info, err := m.store.Stat(ctx, mediaPrefix+mediaID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		m.log.Errorf("cannot stat media: %v", err)
+		return nil, err
+	}
+
+	media := m.toMedia(mediaID, info)
+
+	return &media, nil
+}
+
+// OpenMedia opens media by its public name. Private media requires valid signature
+func (m *MediaService) OpenMedia(ctx context.Context, name string, expires int64, sign string) (io.ReadSeekCloser, storage.ObjectInfo, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.OpenMedia")
+	defer span.End()
+
+	mediaID := strings.SplitN(name, ".", 2)[0]
+
+	if _, err := uuid.Parse(mediaID); err != nil {
+		return nil, storage.ObjectInfo{}, response.ErrMediaNotFound
+	}
+
+	reader, info, err := m.store.Get(ctx, mediaPrefix+mediaID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, storage.ObjectInfo{}, response.ErrMediaNotFound
+	}
+	if err != nil {
+		m.log.Errorf("cannot open media: %v", err)
+		return nil, storage.ObjectInfo{}, err
+	}
+
+	if isPrivate(info) {
+		if expires < time.Now().Unix() || !signature.Verify(m.cfg.SignSecret, signPayload(mediaID, expires), sign) {
+			reader.Close()
+			return nil, storage.ObjectInfo{}, response.ErrInvalidSignature
+		}
+	}
+
+	return reader, info, nil
+}
+
+// CollectExpiredUploads removes partial uploads which were not completed in time
+func (m *MediaService) CollectExpiredUploads(ctx context.Context) (int, error) {
+	ctx, span := m.tracer.Start(ctx, "Service.CollectExpiredUploads")
+	defer span.End()
+
+	objects, err := m.store.List(ctx, uploadsPrefix)
+	if err != nil {
+		m.log.Errorf("cannot list uploads: %v", err)
+		return 0, err
+	}
+
+	now := time.Now()
+	lastModified := make(map[string]time.Time)
+
+	for _, object := range objects {
+		uploadID := strings.SplitN(strings.TrimPrefix(object.Key, uploadsPrefix), "/", 2)[0]
+		if object.ModTime.After(lastModified[uploadID]) {
+			lastModified[uploadID] = object.ModTime
+		}
+	}
+
+	collected := 0
+
+	for uploadID, modTime := range lastModified {
+		upload, err := m.readSession(ctx, uploadID)
+
+		switch {
+		case err == nil && upload.ExpiresAt.After(now):
+			continue
+		case errors.Is(err, storage.ErrNotFound) && modTime.Add(m.cfg.UploadTTL).After(now):
+			// session may be written right now
+			continue
+		case err != nil && !errors.Is(err, storage.ErrNotFound):
+			m.log.Errorf("cannot read upload session %s: %v", uploadID, err)
+			continue
+		}
+
+		if err := m.deleteUpload(ctx, uploadID); err != nil {
+			m.log.Errorf("cannot delete expired upload %s: %v", uploadID, err)
+			continue
+		}
+
+		collected++
+	}
+
+	return collected, nil
+}
+
+func (m *MediaService) getSession(ctx context.Context, userID, uploadID string) (domain.MediaUpload, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return domain.MediaUpload{}, response.ErrUploadNotFound
+	}
+
+	upload, err := m.readSession(ctx, uploadID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return domain.MediaUpload{}, response.ErrUploadNotFound
+	}
+	if err != nil {
+		m.log.Errorf("cannot read upload session: %v", err)
+		return domain.MediaUpload{}, err
+	}
+
+	if upload.UserID != userID || upload.ExpiresAt.Before(time.Now()) {
+		return domain.MediaUpload{}, response.ErrUploadNotFound
+	}
+
+	return upload, nil
+}
+
+func (m *MediaService) readSession(ctx context.Context, uploadID string) (domain.MediaUpload, error) {
+	reader, _, err := m.store.Get(ctx, uploadPrefix(uploadID)+sessionName)
+	if err != nil {
+		return domain.MediaUpload{}, err
+	}
+	defer reader.Close()
+
+	var upload domain.MediaUpload
+
+	if err := json.NewDecoder(reader).Decode(&upload); err != nil {
+		return domain.MediaUpload{}, err
+	}
+
+	return upload, nil
+}
+
+func (m *MediaService) saveSession(ctx context.Context, upload domain.MediaUpload) error {
+	session, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.store.Put(ctx, uploadPrefix(upload.UploadID)+sessionName, bytes.NewReader(session), int64(len(session)), "application/json", nil)
+	return err
+}
+
+func (m *MediaService) deleteUpload(ctx context.Context, uploadID string) error {
+	objects, err := m.store.List(ctx, uploadPrefix(uploadID))
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		if err := m.store.Delete(ctx, object.Key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// probe checks media limits and returns metadata to be stored with media
+func (m *MediaService) probe(ctx context.Context, userID string, private bool, r io.ReaderAt, size int64, contentType string) (map[string]string, error) {
+	meta, err := files.ProbeMedia(r, size, contentType, files.Limits{
+		MaxPixels:          m.cfg.MaxPixels,
+		MaxFrames:          m.cfg.MaxFrames,
+		MaxAnimationPixels: m.cfg.MaxAnimationPixels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if meta.DurationMs > m.cfg.MaxDuration.Milliseconds() {
+		return nil, response.ErrMediaTooLong
+	}
+
+	metadata := map[string]string{
+		"owner":    userID,
+		"private":  strconv.FormatBool(private),
+		"type":     string(meta.Type),
+		"width":    strconv.Itoa(meta.Width),
+		"height":   strconv.Itoa(meta.Height),
+		"duration": strconv.FormatInt(meta.DurationMs, 10),
+		"codec":    meta.Codec,
+	}
+
+	if meta.Thumbnail != nil {
+		thumbnailID := uuid.NewString()
+
+		_, err := m.store.Put(ctx, mediaPrefix+thumbnailID, bytes.NewReader(meta.Thumbnail), int64(len(meta.Thumbnail)), "image/png", map[string]string{
+			"owner":   userID,
+			"private": strconv.FormatBool(private),
+			"type":    string(domain.MediaTypeImage),
+		})
+		if err != nil {
+			m.log.Errorf("cannot store thumbnail: %v", err)
+			return nil, err
+		}
+
+		metadata["thumbnail"] = thumbnailID
+	}
+
+	return metadata, nil
+}
+
+func (m *MediaService) maxSize(contentType string) int64 {
+	if files.IsVideo(contentType) {
+		return m.cfg.MaxVideoSize
+	}
+	return m.cfg.MaxSize
+}
+
+func (m *MediaService) toMedia(mediaID string, info storage.ObjectInfo) domain.Media {
+	private := isPrivate(info)
+
+	width, _ := strconv.Atoi(info.Metadata["width"])
+	height, _ := strconv.Atoi(info.Metadata["height"])
+	duration, _ := strconv.ParseInt(info.Metadata["duration"], 10, 64)
+
+	media := domain.Media{
+		MediaID:     mediaID,
+		URL:         m.mediaURL(mediaID, info.ContentType, private),
+		ContentType: info.ContentType,
+		MediaType:   domain.MediaType(info.Metadata["type"]),
+		Size:        info.Size,
+		Width:       width,
+		Height:      height,
+		DurationMs:  duration,
+		Codec:       info.Metadata["codec"],
+	}
+
+	// media stored before probing was introduced
+	if media.MediaType == "" {
+		media.MediaType = domain.MediaTypeImage
+	}
+
+	if thumbnailID := info.Metadata["thumbnail"]; thumbnailID != "" {
+		media.ThumbnailURL = m.mediaURL(thumbnailID, "image/png", private)
+	}
+
+	return media
+}
+
+func (m *MediaService) mediaURL(mediaID, contentType string, private bool) string {
+	url := fmt.Sprintf("%s/%s", m.cfg.BaseURL, files.MediaName(mediaID, contentType))
+
+	if private {
+		expires := time.Now().Add(m.cfg.SignedURLTTL).Unix()
+		url = fmt.Sprintf("%s?expires=%d&signature=%s", url, expires, signature.Sign(m.cfg.SignSecret, signPayload(mediaID, expires)))
+	}
+
+	return url
+}
+
+func isPrivate(info storage.ObjectInfo) bool {
+	private, _ := strconv.ParseBool(info.Metadata["private"])
+	return private
+}
+
+func signPayload(mediaID string, expires int64) []byte {
+	return []byte(fmt.Sprintf("%s:%d", mediaID, expires))
+}
+
+func attachmentKey(kind domain.AttachmentKind, ownerID string) string {
+	return fmt.Sprintf("%s%s/%s", attachmentsPrefix, kind, ownerID)
+}
+
+func uploadPrefix(uploadID string) string {
+	return uploadsPrefix + uploadID + "/"
+}
+
+func chunkKey(uploadID string, offset int64) string {
+	// zero padding keeps chunks sorted by offset
+	return fmt.Sprintf("%s%s%020d", uploadPrefix(uploadID), chunkPrefix, offset)
+}
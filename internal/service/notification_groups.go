@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"time"
+)
+
+type groupKey struct {
+	kind     string
+	targetID string
+}
+
+// GetNotificationGroups pages grouped notifications, group is unread while any of its notifications is unread
+func (n *NotificationService) GetNotificationGroups(ctx context.Context, userID string, query domain.NotificationQuery) ([]domain.NotificationGroup, string, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.GetNotificationGroups")
+	defer span.End()
+
+	var cursorTime time.Time
+	var cursorID string
+
+	if query.Cursor != "" {
+		var err error
+		if cursorTime, cursorID, err = cursor.Decode(query.Cursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	groups, err := n.listGroups(ctx, userID, query.Type)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit := pageLimit(query.Limit)
+	page := make([]domain.NotificationGroup, 0, limit+1)
+
+	for _, group := range groups {
+		if query.UnreadOnly && group.Read {
+			continue
+		}
+
+		if query.Cursor != "" && !cursor.Before(group.LatestAt, group.GroupID, cursorTime, cursorID) {
+			continue
+		}
+
+		page = append(page, group)
+		if len(page) > limit {
+			break
+		}
+	}
+
+	hasMore := len(page) > limit
+	if hasMore {
+		page = page[:limit]
+	}
+
+	for i := range page {
+		page[i].Actors = n.groupActors(ctx, page[i].Actors)
+	}
+
+	if !hasMore {
+		return page, "", nil
+	}
+
+	last := page[len(page)-1]
+
+	return page, cursor.Encode(last.LatestAt, last.GroupID), nil
+}
+
+func (n *NotificationService) MarkGroupAsRead(ctx context.Context, userID, groupID string) error {
+	ctx, span := n.tracer.Start(ctx, "Service.MarkGroupAsRead")
+	defer span.End()
+
+	groups, err := n.listGroups(ctx, userID, "")
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		if group.GroupID == groupID {
+			return n.MarkNotificationsAsRead(ctx, userID, group.NotificationIDs)
+		}
+	}
+
+	return response.ErrNotificationNotFound
+}
+
+// listGroups joins notifications newest first, notification starts a new group
+// once it is more than group window older than the newest one of the current group,
+// notifications without target are never joined
+func (n *NotificationService) listGroups(ctx context.Context, userID, kind string) ([]domain.NotificationGroup, error) {
+	notifications, err := n.listNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]domain.NotificationGroup, 0)
+	open := make(map[groupKey]int)
+	actors := make(map[int]map[string]bool)
+
+	for _, notification := range notifications {
+		if kind != "" && notification.Type != kind {
+			continue
+		}
+
+		key := groupKey{kind: notification.Type, targetID: notification.TargetID}
+
+		idx, ok := open[key]
+		if !ok || groups[idx].LatestAt.Sub(notification.CreatedAt) > n.cfg.GroupWindow {
+			idx = len(groups)
+
+			// notifications service does not send targets, without one unrelated notifications would be merged
+			if notification.TargetID != "" {
+				open[key] = idx
+			}
+			actors[idx] = make(map[string]bool)
+
+			groups = append(groups, domain.NotificationGroup{
+				GroupID:  notification.NotificationID,
+				Type:     notification.Type,
+				TargetID: notification.TargetID,
+				Read:     true,
+				LatestAt: notification.CreatedAt,
+			})
+		}
+
+		group := &groups[idx]
+		group.NotificationIDs = append(group.NotificationIDs, notification.NotificationID)
+		group.Read = group.Read && notification.Read
+
+		if actors[idx][notification.SenderID] {
+			continue
+		}
+
+		actors[idx][notification.SenderID] = true
+		group.ActorCount++
+
+		if len(group.Actors) < n.cfg.GroupActors {
+			group.Actors = append(group.Actors, domain.NotificationActor{UserID: notification.SenderID})
+		}
+	}
+
+	return groups, nil
+}
+
+func (n *NotificationService) groupActors(ctx context.Context, actors []domain.NotificationActor) []domain.NotificationActor {
+	for i, actor := range actors {
+		user, err := n.auth.GetUserByID(ctx, actor.UserID)
+		if err != nil {
+			// account may be deleted, still show it in the group
+			n.log.Debugf("cannot get user %s: %v", actor.UserID, err)
+			continue
+		}
+
+		actors[i].Username = user.Username
+	}
+
+	return actors
+}
@@ -2,60 +2,390 @@ package service
 
 import (
 	"context"
+	"errors"
 	pbNotifications "github.com/Verce11o/yata-protos/gen/go/notifications"
+	"github.com/Verce11o/yata/internal/config"
 	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/cursor"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"time"
 )
 
 type NotificationService struct {
-	log    *zap.SugaredLogger
-	tracer trace.Tracer
-	client pbNotifications.NotificationsClient
+	log         *zap.SugaredLogger
+	tracer      trace.Tracer
+	client      pbNotifications.NotificationsClient
+	repo        repository.Notifications
+	follows     repository.Follows
+	requests    repository.FollowRequests
+	preferences repository.NotificationPreferences
+	relations   repository.Relations
+	accounts    Account
+	auth        Auth
+	webhooks    Webhook
+	unread      *UnreadCounts
+	cfg         config.Notifications
 }
 
-func NewNotificationService(log *zap.SugaredLogger, tracer trace.Tracer, client pbNotifications.NotificationsClient) *NotificationService {
-	return &NotificationService{log: log, tracer: tracer, client: client}
+func NewNotificationService(log *zap.SugaredLogger, tracer trace.Tracer, client pbNotifications.NotificationsClient, repo repository.Notifications, follows repository.Follows, requests repository.FollowRequests, preferences repository.NotificationPreferences, relations repository.Relations, accounts Account, auth Auth, webhooks Webhook, unread *UnreadCounts, cfg config.Notifications) *NotificationService {
+	return &NotificationService{log: log, tracer: tracer, client: client, repo: repo, follows: follows, requests: requests, preferences: preferences, relations: relations, accounts: accounts, auth: auth, webhooks: webhooks, unread: unread, cfg: cfg}
 }
 
-func (n *NotificationService) SubscribeToUser(ctx context.Context, userID, toUserID string) error {
+// SubscribeToUser subscribes right away to public accounts, for private ones follow request is created instead
+func (n *NotificationService) SubscribeToUser(ctx context.Context, userID, toUserID string) (domain.FollowStatus, error) {
 	ctx, span := n.tracer.Start(ctx, "Service.SubscribeUser")
 	defer span.End()
 
-	_, err := n.client.SubscribeToUser(ctx, &pbNotifications.SubscribeToUserRequest{
+	private, err := n.accounts.IsPrivate(ctx, toUserID)
+	if err != nil {
+		return "", err
+	}
+
+	if !private {
+		return domain.FollowStatusFollowing, n.subscribe(ctx, userID, toUserID)
+	}
+
+	followed, err := n.follows.GetFollowedUsers(ctx, userID, []string{toUserID})
+	if err != nil {
+		n.log.Errorf("cannot get followed users: %v", err)
+		return "", err
+	}
+
+	if followed[toUserID] {
+		return domain.FollowStatusFollowing, nil
+	}
+
+	requested, err := n.requests.HasFollowRequest(ctx, userID, toUserID)
+	if err != nil {
+		n.log.Errorf("cannot check follow request: %v", err)
+		return "", err
+	}
+
+	if requested {
+		return domain.FollowStatusRequested, nil
+	}
+
+	if err := n.requests.CreateFollowRequest(ctx, userID, toUserID); err != nil {
+		n.log.Errorf("cannot create follow request: %v", err)
+		return "", err
+	}
+
+	err = n.repo.CreateNotifications(ctx, []domain.Notification{{
+		NotificationID: uuid.New().String(),
+		UserID:         toUserID,
+		SenderID:       userID,
+		CreatedAt:      time.Now().UTC(),
+		Type:           domain.FollowRequestNotification,
+	}})
+
+	if err != nil {
+		n.log.Errorf("cannot notify about follow request: %v", err)
+	}
+
+	n.unread.Invalidate(toUserID)
+
+	return domain.FollowStatusRequested, nil
+}
+
+// UnSubscribeFromUser also cancels pending follow request
+func (n *NotificationService) UnSubscribeFromUser(ctx context.Context, userID, toUserID string) error {
+	ctx, span := n.tracer.Start(ctx, "Service.UnSubscribeFromUser")
+	defer span.End()
+
+	err := n.requests.DeleteFollowRequest(ctx, userID, toUserID)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, response.ErrFollowRequestNotFound) {
+		n.log.Errorf("cannot cancel follow request: %v", err)
+		return err
+	}
+
+	_, err = n.client.UnSubscribeFromUser(ctx, &pbNotifications.UnSubscribeFromUserRequest{
 		UserId:   userID,
 		ToUserId: toUserID,
 	})
 
 	if err != nil {
-		n.log.Errorf("cannot subscribe to user: %v", err)
+		n.log.Errorf("cannot unsubscribe from user: %v", err)
+		return err
+	}
+
+	if err := n.follows.DeleteFollow(ctx, userID, toUserID); err != nil {
+		n.log.Errorf("cannot delete follow: %v", err)
 		return err
 	}
 
 	return nil
 }
 
-func (n *NotificationService) UnSubscribeFromUser(ctx context.Context, userID, toUserID string) error {
-	ctx, span := n.tracer.Start(ctx, "Service.UnSubscribeFromUser")
+func (n *NotificationService) GetFollowRequests(ctx context.Context, userID, cursor string, limit int) ([]domain.FollowRequest, string, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.GetFollowRequests")
 	defer span.End()
 
-	_, err := n.client.UnSubscribeFromUser(ctx, &pbNotifications.UnSubscribeFromUserRequest{
+	requests, next, err := n.requests.GetFollowRequests(ctx, userID, cursor, pageLimit(limit))
+	if err != nil {
+		n.log.Errorf("cannot get follow requests: %v", err)
+		return nil, "", err
+	}
+
+	return requests, next, nil
+}
+
+func (n *NotificationService) ApproveFollowRequest(ctx context.Context, userID, requesterID string) error {
+	ctx, span := n.tracer.Start(ctx, "Service.ApproveFollowRequest")
+	defer span.End()
+
+	requested, err := n.requests.HasFollowRequest(ctx, requesterID, userID)
+	if err != nil {
+		n.log.Errorf("cannot check follow request: %v", err)
+		return err
+	}
+
+	if !requested {
+		return response.ErrFollowRequestNotFound
+	}
+
+	// request is removed last, so failed approval can be retried
+	if err := n.subscribe(ctx, requesterID, userID); err != nil {
+		return err
+	}
+
+	err = n.requests.DeleteFollowRequest(ctx, requesterID, userID)
+	if err != nil && !errors.Is(err, response.ErrFollowRequestNotFound) {
+		n.log.Errorf("cannot delete follow request: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *NotificationService) RejectFollowRequest(ctx context.Context, userID, requesterID string) error {
+	ctx, span := n.tracer.Start(ctx, "Service.RejectFollowRequest")
+	defer span.End()
+
+	if err := n.requests.DeleteFollowRequest(ctx, requesterID, userID); err != nil {
+		if !errors.Is(err, response.ErrFollowRequestNotFound) {
+			n.log.Errorf("cannot delete follow request: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (n *NotificationService) GetFollowers(ctx context.Context, viewerID, userID, cursor string, limit int) ([]domain.FollowUser, string, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.GetFollowers")
+	defer span.End()
+
+	follows, next, err := n.follows.GetFollowers(ctx, userID, cursor, pageLimit(limit))
+	if err != nil {
+		n.log.Errorf("cannot get followers: %v", err)
+		return nil, "", err
+	}
+
+	users, err := n.toFollowUsers(ctx, viewerID, follows, func(follow domain.Follow) string { return follow.FollowerID })
+	if err != nil {
+		return nil, "", err
+	}
+
+	return users, next, nil
+}
+
+func (n *NotificationService) GetFollowing(ctx context.Context, viewerID, userID, cursor string, limit int) ([]domain.FollowUser, string, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.GetFollowing")
+	defer span.End()
+
+	follows, next, err := n.follows.GetFollowing(ctx, userID, cursor, pageLimit(limit))
+	if err != nil {
+		n.log.Errorf("cannot get following: %v", err)
+		return nil, "", err
+	}
+
+	users, err := n.toFollowUsers(ctx, viewerID, follows, func(follow domain.Follow) string { return follow.FolloweeID })
+	if err != nil {
+		return nil, "", err
+	}
+
+	return users, next, nil
+}
+
+func (n *NotificationService) GetMutuals(ctx context.Context, viewerID, userID, cursor string, limit int) ([]domain.FollowUser, string, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.GetMutuals")
+	defer span.End()
+
+	follows, next, err := n.follows.GetMutuals(ctx, userID, cursor, pageLimit(limit))
+	if err != nil {
+		n.log.Errorf("cannot get mutuals: %v", err)
+		return nil, "", err
+	}
+
+	users, err := n.toFollowUsers(ctx, viewerID, follows, func(follow domain.Follow) string { return follow.FollowerID })
+	if err != nil {
+		return nil, "", err
+	}
+
+	return users, next, nil
+}
+
+// toFollowUsers adds usernames and follows_you flag relative to viewer, pick selects listed user
+func (n *NotificationService) toFollowUsers(ctx context.Context, viewerID string, follows []domain.Follow, pick func(domain.Follow) string) ([]domain.FollowUser, error) {
+	userIDs := make([]string, 0, len(follows))
+	for _, follow := range follows {
+		userIDs = append(userIDs, pick(follow))
+	}
+
+	followsViewer, err := n.follows.GetFollowingUsers(ctx, viewerID, userIDs)
+	if err != nil {
+		n.log.Errorf("cannot get viewer followers: %v", err)
+		return nil, err
+	}
+
+	users := make([]domain.FollowUser, 0, len(follows))
+
+	for _, follow := range follows {
+		userID := pick(follow)
+
+		var username string
+
+		user, err := n.auth.GetUserByID(ctx, userID)
+		if err != nil {
+			// account may be deleted, still keep it in the list
+			n.log.Debugf("cannot get user %s: %v", userID, err)
+		} else {
+			username = user.Username
+		}
+
+		users = append(users, domain.FollowUser{
+			UserID:     userID,
+			Username:   username,
+			FollowsYou: followsViewer[userID],
+			FollowedAt: follow.CreatedAt,
+		})
+	}
+
+	return users, nil
+}
+
+func (n *NotificationService) subscribe(ctx context.Context, userID, toUserID string) error {
+	_, err := n.client.SubscribeToUser(ctx, &pbNotifications.SubscribeToUserRequest{
 		UserId:   userID,
 		ToUserId: toUserID,
 	})
 
 	if err != nil {
-		n.log.Errorf("cannot unsubscribe from user: %v", err)
+		n.log.Errorf("cannot subscribe to user: %v", err)
+		return err
+	}
+
+	if err := n.follows.AddFollow(ctx, userID, toUserID); err != nil {
+		n.log.Errorf("cannot add follow: %v", err)
 		return err
 	}
 
+	// follow is already stored, so failed dispatch only costs the webhook event
+	if err := n.webhooks.Dispatch(ctx, toUserID, domain.WebhookEventFollower, domain.FollowerEvent{FollowerID: userID}); err != nil {
+		n.log.Errorf("cannot dispatch follower webhook: %v", err)
+	}
+
 	return nil
 }
 
-func (n *NotificationService) GetNotifications(ctx context.Context, userID string) ([]domain.Notification, error) {
+func (n *NotificationService) GetNotifications(ctx context.Context, userID string, query domain.NotificationQuery) ([]domain.Notification, string, error) {
 	ctx, span := n.tracer.Start(ctx, "Service.GetNotifications")
 	defer span.End()
 
+	var cursorTime time.Time
+	var cursorID string
+
+	if query.Cursor != "" {
+		var err error
+		if cursorTime, cursorID, err = cursor.Decode(query.Cursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	notifications, err := n.listNotifications(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit := pageLimit(query.Limit)
+	page := make([]domain.Notification, 0, limit+1)
+
+	for _, notification := range notifications {
+		if query.Type != "" && notification.Type != query.Type {
+			continue
+		}
+
+		if query.UnreadOnly && notification.Read {
+			continue
+		}
+
+		if query.Cursor != "" && !cursor.Before(notification.CreatedAt, notification.NotificationID, cursorTime, cursorID) {
+			continue
+		}
+
+		page = append(page, notification)
+		if len(page) > limit {
+			break
+		}
+	}
+
+	if len(page) <= limit {
+		return page, "", nil
+	}
+
+	page = page[:limit]
+	last := page[len(page)-1]
+
+	return page, cursor.Encode(last.CreatedAt, last.NotificationID), nil
+}
+
+// GetUnreadCount is polled for badges, so it is served from cache until notifications of user change
+func (n *NotificationService) GetUnreadCount(ctx context.Context, userID string) (int, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.GetUnreadCount")
+	defer span.End()
+
+	now := time.Now()
+
+	if count, ok := n.unread.get(userID, now); ok {
+		return count, nil
+	}
+
+	notifications, err := n.listNotifications(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, notification := range notifications {
+		if !notification.Read {
+			count++
+		}
+	}
+
+	n.unread.set(userID, count, now)
+
+	return count, nil
+}
+
+// InvalidateUnreadCount is called when notification of user was created outside of this service
+func (n *NotificationService) InvalidateUnreadCount(userID string) {
+	n.unread.Invalidate(userID)
+}
+
+// listNotifications merges notifications of notifications service and the gateway,
+// drops ones user does not want to see and sorts them newest first
+func (n *NotificationService) listNotifications(ctx context.Context, userID string) ([]domain.Notification, error) {
 	resp, err := n.client.GetNotifications(ctx, &pbNotifications.GetNotificationsRequest{UserId: userID})
 
 	if err != nil {
@@ -66,6 +396,7 @@ func (n *NotificationService) GetNotifications(ctx context.Context, userID strin
 	result := make([]domain.Notification, 0, len(resp.GetNotifications()))
 
 	for _, notif := range resp.GetNotifications() {
+		// payload of notifications service has no target, such notifications are not grouped
 		item := domain.Notification{
 			NotificationID: notif.GetNotificationId(),
 			UserID:         notif.GetUserId(),
@@ -77,6 +408,43 @@ func (n *NotificationService) GetNotifications(ctx context.Context, userID strin
 		result = append(result, item)
 	}
 
+	local, err := n.repo.GetNotifications(ctx, userID)
+	if err != nil {
+		n.log.Errorf("cannot get gateway notifications: %v", err)
+		return nil, err
+	}
+
+	result = append(result, local...)
+
+	preferences, err := n.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := getHiddenUsers(ctx, n.relations, userID)
+	if err != nil {
+		n.log.Errorf("cannot get hidden users: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	allowed := result[:0]
+
+	for _, notification := range result {
+		if _, ok := hidden[notification.SenderID]; ok {
+			continue
+		}
+
+		if allowsNotification(preferences, notification.Type, domain.ChannelInApp, now) {
+			allowed = append(allowed, notification)
+		}
+	}
+	result = allowed
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return cursor.Before(result[j].CreatedAt, result[j].NotificationID, result[i].CreatedAt, result[i].NotificationID)
+	})
+
 	return result, nil
 }
 
@@ -84,7 +452,19 @@ func (n *NotificationService) MarkNotificationAsRead(ctx context.Context, userID
 	ctx, span := n.tracer.Start(ctx, "Service.MarkNotificationAsRead")
 	defer span.End()
 
-	_, err := n.client.MarkNotificationAsRead(ctx, &pbNotifications.MarkNotificationAsReadRequest{
+	defer n.unread.Invalidate(userID)
+
+	found, err := n.repo.MarkNotificationAsRead(ctx, userID, notificationID)
+	if err != nil {
+		n.log.Errorf("cannot mark gateway notification as read: %v", err)
+		return err
+	}
+
+	if found {
+		return nil
+	}
+
+	_, err = n.client.MarkNotificationAsRead(ctx, &pbNotifications.MarkNotificationAsReadRequest{
 		UserId:         userID,
 		NotificationId: notificationID,
 	})
@@ -97,10 +477,36 @@ func (n *NotificationService) MarkNotificationAsRead(ctx context.Context, userID
 	return nil
 }
 
+// MarkNotificationsAsRead skips notifications that do not exist anymore
+func (n *NotificationService) MarkNotificationsAsRead(ctx context.Context, userID string, notificationIDs []string) error {
+	ctx, span := n.tracer.Start(ctx, "Service.MarkNotificationsAsRead")
+	defer span.End()
+
+	for _, notificationID := range notificationIDs {
+		err := n.MarkNotificationAsRead(ctx, userID, notificationID)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (n *NotificationService) ReadAllNotifications(ctx context.Context, userID string) error {
 	ctx, span := n.tracer.Start(ctx, "Service.ReadAllNotifications")
 	defer span.End()
 
+	defer n.unread.Invalidate(userID)
+
+	if err := n.repo.ReadAllNotifications(ctx, userID); err != nil {
+		n.log.Errorf("cannot read all gateway notifications: %v", err)
+		return err
+	}
+
 	_, err := n.client.ReadAllNotifications(ctx, &pbNotifications.ReadAllNotificationsRequest{UserId: userID})
 	if err != nil {
 		n.log.Errorf("cannot read all notifications: %v", err)
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/oidc"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/lib/token"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// oidcUsernameLength leaves room for random suffix within username limit of 30
+const oidcUsernameLength = 20
+
+type OIDCService struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	repo      repository.OIDC
+	auth      Auth
+	providers map[string]*oidc.Provider
+	cfg       config.OIDC
+	jwt       config.JWTConfig
+}
+
+func NewOIDCService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.OIDC, auth Auth, cfg config.OIDC, jwt config.JWTConfig) *OIDCService {
+	client := &http.Client{Timeout: cfg.Timeout}
+	providers := make(map[string]*oidc.Provider, len(cfg.Providers))
+
+	for name, provider := range cfg.Providers {
+		providers[name] = oidc.NewProvider(oidc.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		}, client)
+	}
+
+	return &OIDCService{log: log, tracer: tracer, repo: repo, auth: auth, providers: providers, cfg: cfg, jwt: jwt}
+}
+
+// AuthURL starts sign in at provider, with linkUserID provider is linked to that user instead
+func (s *OIDCService) AuthURL(ctx context.Context, providerName, linkUserID string) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.AuthURL")
+	defer span.End()
+
+	// name comes from request params, which are reused by fiber after the request, and is kept in state
+	providerName = strings.Clone(providerName)
+
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", response.ErrOIDCProviderNotFound
+	}
+
+	state := domain.OIDCState{
+		Provider:   providerName,
+		LinkUserID: linkUserID,
+		ExpiresAt:  time.Now().Add(s.cfg.StateTTL),
+	}
+
+	for _, value := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		random, err := oidc.RandomString()
+		if err != nil {
+			return "", err
+		}
+		*value = random
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state.State, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		s.log.Errorf("cannot build %s authorization url: %v", providerName, err)
+		return "", response.ErrOIDCFailed
+	}
+
+	if err := s.repo.CreateState(ctx, state); err != nil {
+		s.log.Errorf("cannot create oidc state: %v", err)
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// Callback finishes sign in, users are found by linked identity, unknown ones are registered with verified provider email
+func (s *OIDCService) Callback(ctx context.Context, providerName, code, state string) (domain.OIDCResult, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.Callback")
+	defer span.End()
+
+	providerName = strings.Clone(providerName)
+
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return domain.OIDCResult{}, response.ErrOIDCProviderNotFound
+	}
+
+	stored, err := s.repo.ConsumeState(ctx, state, time.Now())
+	if err != nil {
+		return domain.OIDCResult{}, err
+	}
+
+	if stored.Provider != providerName {
+		return domain.OIDCResult{}, response.ErrInvalidOIDCState
+	}
+
+	rawIDToken, err := provider.Exchange(ctx, code, stored.CodeVerifier)
+	if err != nil {
+		s.log.Errorf("cannot exchange %s code: %v", providerName, err)
+		return domain.OIDCResult{}, response.ErrOIDCFailed
+	}
+
+	claims, err := provider.VerifyIDToken(ctx, rawIDToken, stored.Nonce)
+	if err != nil {
+		s.log.Errorf("cannot verify %s id token: %v", providerName, err)
+		return domain.OIDCResult{}, response.ErrOIDCFailed
+	}
+
+	if stored.LinkUserID != "" {
+		if err := s.link(ctx, providerName, stored.LinkUserID, claims); err != nil {
+			return domain.OIDCResult{}, err
+		}
+		return domain.OIDCResult{Linked: true}, nil
+	}
+
+	identity, err := s.repo.GetIdentity(ctx, providerName, claims.Subject)
+
+	if errors.Is(err, response.ErrUserNotFound) {
+		identity, err = s.register(ctx, providerName, claims)
+	}
+
+	if err != nil {
+		return domain.OIDCResult{}, err
+	}
+
+	sessionToken, err := token.GenerateToken(identity.UserID, s.jwt.Secret, time.Duration(s.jwt.TokenTTLHours)*time.Hour)
+	if err != nil {
+		return domain.OIDCResult{}, err
+	}
+
+	return domain.OIDCResult{Token: sessionToken}, nil
+}
+
+// link requires provider email to be verified and match account email, so provider account of someone else cannot be attached
+func (s *OIDCService) link(ctx context.Context, providerName, userID string, claims oidc.Claims) error {
+	if !claims.EmailVerified {
+		return response.ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.auth.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return response.ErrOIDCEmailMismatch
+	}
+
+	return s.repo.CreateIdentity(ctx, domain.Identity{
+		Provider: providerName,
+		Subject:  claims.Subject,
+		UserID:   userID,
+		Email:    claims.Email,
+	})
+}
+
+// register creates account with random password, it can be changed by password reset.
+// Existing accounts are not taken over by email, their owners link provider after signing in
+func (s *OIDCService) register(ctx context.Context, providerName string, claims oidc.Claims) (domain.Identity, error) {
+	if !claims.EmailVerified || claims.Email == "" {
+		return domain.Identity{}, response.ErrOIDCEmailNotVerified
+	}
+
+	username, err := oidcUsername(claims)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+
+	password, err := oidc.RandomString()
+	if err != nil {
+		return domain.Identity{}, err
+	}
+
+	userID, err := s.auth.Register(ctx, domain.SignUpInput{
+		Username: username,
+		Email:    claims.Email,
+		Password: password,
+	})
+
+	if st, ok := status.FromError(err); ok && st.Code() == codes.AlreadyExists {
+		return domain.Identity{}, response.ErrOIDCAccountExists
+	}
+
+	if err != nil {
+		return domain.Identity{}, err
+	}
+
+	identity := domain.Identity{
+		Provider: providerName,
+		Subject:  claims.Subject,
+		UserID:   userID,
+		Email:    claims.Email,
+	}
+
+	if err := s.repo.CreateIdentity(ctx, identity); err != nil {
+		s.log.Errorf("cannot link registered user %s: %v", userID, err)
+		return domain.Identity{}, err
+	}
+
+	return identity, nil
+}
+
+// oidcUsername derives username from provider profile, random suffix keeps it unique
+func oidcUsername(claims oidc.Claims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	var username strings.Builder
+
+	for _, r := range strings.ToLower(base) {
+		if username.Len() >= oidcUsernameLength {
+			break
+		}
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			username.WriteRune(r)
+		}
+	}
+
+	if username.Len() == 0 {
+		username.WriteString("user")
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	return username.String() + "_" + hex.EncodeToString(suffix), nil
+}
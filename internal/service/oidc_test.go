@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/oidc"
+	"github.com/Verce11o/yata/internal/lib/oidc/oidctest"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/lib/token"
+	"github.com/Verce11o/yata/internal/repository/memory"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testProvider  = "mock"
+	testJWTSecret = "secret"
+)
+
+// fakeAuth keeps accounts of the auth service in memory, methods not used by OIDC panic
+type fakeAuth struct {
+	Auth
+
+	mu         sync.Mutex
+	users      map[string]domain.GetUserResponse
+	registered int
+}
+
+func newFakeAuth() *fakeAuth {
+	return &fakeAuth{users: make(map[string]domain.GetUserResponse)}
+}
+
+func (a *fakeAuth) addUser(email string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	userID := uuid.New()
+	a.users[userID.String()] = domain.GetUserResponse{UserID: userID, Username: "user", Email: email, IsVerified: true}
+
+	return userID.String()
+}
+
+func (a *fakeAuth) Register(ctx context.Context, input domain.SignUpInput) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, user := range a.users {
+		if strings.EqualFold(user.Email, input.Email) {
+			return "", status.Error(codes.AlreadyExists, "user already exists")
+		}
+	}
+
+	userID := uuid.New()
+	a.users[userID.String()] = domain.GetUserResponse{UserID: userID, Username: input.Username, Email: input.Email}
+	a.registered++
+
+	return userID.String(), nil
+}
+
+func (a *fakeAuth) GetUserByID(ctx context.Context, userID string) (domain.GetUserResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	user, ok := a.users[userID]
+	if !ok {
+		return domain.GetUserResponse{}, status.Error(codes.NotFound, "user not found")
+	}
+
+	return user, nil
+}
+
+type oidcTest struct {
+	service  *OIDCService
+	provider *oidctest.Server
+	auth     *fakeAuth
+}
+
+func newOIDCTest(t *testing.T) *oidcTest {
+	t.Helper()
+
+	provider, err := oidctest.NewServer("yata", "client-secret", oidctest.User{
+		Subject:           "subject-1",
+		Email:             "alice@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "Alice",
+	})
+	if err != nil {
+		t.Fatalf("cannot start provider: %v", err)
+	}
+	t.Cleanup(provider.Close)
+
+	providerCfg := provider.Config("http://gateway.test/api/auth/oidc/" + testProvider + "/callback")
+
+	cfg := config.OIDC{
+		StateTTL: time.Minute,
+		Timeout:  5 * time.Second,
+		Providers: map[string]config.OIDCProvider{
+			testProvider: {
+				Issuer:       providerCfg.Issuer,
+				ClientID:     providerCfg.ClientID,
+				ClientSecret: providerCfg.ClientSecret,
+				RedirectURL:  providerCfg.RedirectURL,
+			},
+		},
+	}
+
+	auth := newFakeAuth()
+	jwt := config.JWTConfig{Secret: testJWTSecret, TokenTTLHours: 1}
+
+	return &oidcTest{
+		service:  NewOIDCService(zap.NewNop().Sugar(), noop.NewTracerProvider().Tracer(""), memory.NewOIDCRepository(), auth, cfg, jwt),
+		provider: provider,
+		auth:     auth,
+	}
+}
+
+// signIn starts sign in, lets tamper modify authorization request and finishes it at the provider
+func (o *oidcTest) signIn(t *testing.T, linkUserID string, tamper func(query url.Values)) (domain.OIDCResult, error) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	authURL, err := o.service.AuthURL(ctx, testProvider, linkUserID)
+	if err != nil {
+		t.Fatalf("AuthURL() error = %v", err)
+	}
+
+	if tamper != nil {
+		parsed, err := url.Parse(authURL)
+		if err != nil {
+			t.Fatalf("cannot parse authorization url: %v", err)
+		}
+
+		query := parsed.Query()
+		tamper(query)
+		parsed.RawQuery = query.Encode()
+		authURL = parsed.String()
+	}
+
+	code, state, err := o.provider.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+
+	return o.service.Callback(ctx, testProvider, code, state)
+}
+
+func tokenUserID(t *testing.T, result domain.OIDCResult) string {
+	t.Helper()
+
+	userID, err := token.ParseToken(result.Token, testJWTSecret)
+	if err != nil {
+		t.Fatalf("cannot parse session token: %v", err)
+	}
+
+	return userID
+}
+
+func TestOIDCFirstLoginCreatesAccount(t *testing.T) {
+	o := newOIDCTest(t)
+
+	result, err := o.signIn(t, "", nil)
+	if err != nil {
+		t.Fatalf("Callback() error = %v", err)
+	}
+
+	userID := tokenUserID(t, result)
+
+	user, err := o.auth.GetUserByID(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("registered user not found: %v", err)
+	}
+
+	if user.Email != "alice@example.com" || !strings.HasPrefix(user.Username, "alice_") {
+		t.Errorf("registered user = %+v, want alice@example.com with alice_ username", user)
+	}
+
+	// the same provider account signs in to the same user
+	result, err = o.signIn(t, "", nil)
+	if err != nil {
+		t.Fatalf("second Callback() error = %v", err)
+	}
+
+	if got := tokenUserID(t, result); got != userID {
+		t.Errorf("second sign in user = %s, want %s", got, userID)
+	}
+
+	if o.auth.registered != 1 {
+		t.Errorf("registered %d users, want 1", o.auth.registered)
+	}
+}
+
+func TestOIDCFirstLoginRequiresVerifiedEmail(t *testing.T) {
+	o := newOIDCTest(t)
+
+	o.provider.SetUser(oidctest.User{Subject: "subject-2", Email: "bob@example.com"})
+
+	if _, err := o.signIn(t, "", nil); !errors.Is(err, response.ErrOIDCEmailNotVerified) {
+		t.Fatalf("Callback() error = %v, want %v", err, response.ErrOIDCEmailNotVerified)
+	}
+}
+
+func TestOIDCFirstLoginDoesNotTakeOverExistingAccount(t *testing.T) {
+	o := newOIDCTest(t)
+
+	o.auth.addUser("alice@example.com")
+
+	if _, err := o.signIn(t, "", nil); !errors.Is(err, response.ErrOIDCAccountExists) {
+		t.Fatalf("Callback() error = %v, want %v", err, response.ErrOIDCAccountExists)
+	}
+}
+
+func TestOIDCLinkExistingAccount(t *testing.T) {
+	o := newOIDCTest(t)
+
+	userID := o.auth.addUser("Alice@Example.com")
+
+	result, err := o.signIn(t, userID, nil)
+	if err != nil {
+		t.Fatalf("link Callback() error = %v", err)
+	}
+
+	if !result.Linked || result.Token != "" {
+		t.Fatalf("link result = %+v, want linked without token", result)
+	}
+
+	result, err = o.signIn(t, "", nil)
+	if err != nil {
+		t.Fatalf("sign in Callback() error = %v", err)
+	}
+
+	if got := tokenUserID(t, result); got != userID {
+		t.Errorf("signed in user = %s, want linked %s", got, userID)
+	}
+
+	if o.auth.registered != 0 {
+		t.Errorf("registered %d users, want 0", o.auth.registered)
+	}
+}
+
+func TestOIDCLinkRequiresMatchingEmail(t *testing.T) {
+	o := newOIDCTest(t)
+
+	userID := o.auth.addUser("carol@example.com")
+
+	if _, err := o.signIn(t, userID, nil); !errors.Is(err, response.ErrOIDCEmailMismatch) {
+		t.Fatalf("Callback() error = %v, want %v", err, response.ErrOIDCEmailMismatch)
+	}
+}
+
+func TestOIDCRejectsVerifierMismatch(t *testing.T) {
+	o := newOIDCTest(t)
+
+	// provider receives challenge of a verifier the gateway does not hold
+	_, err := o.signIn(t, "", func(query url.Values) {
+		query.Set("code_challenge", oidc.Challenge("another verifier"))
+	})
+
+	if !errors.Is(err, response.ErrOIDCFailed) {
+		t.Fatalf("Callback() error = %v, want %v", err, response.ErrOIDCFailed)
+	}
+}
+
+func TestOIDCRejectsNonceMismatch(t *testing.T) {
+	o := newOIDCTest(t)
+
+	_, err := o.signIn(t, "", func(query url.Values) {
+		query.Set("nonce", "another nonce")
+	})
+
+	if !errors.Is(err, response.ErrOIDCFailed) {
+		t.Fatalf("Callback() error = %v, want %v", err, response.ErrOIDCFailed)
+	}
+}
+
+func TestOIDCRejectsBadState(t *testing.T) {
+	o := newOIDCTest(t)
+	ctx := context.Background()
+
+	authURL, err := o.service.AuthURL(ctx, testProvider, "")
+	if err != nil {
+		t.Fatalf("AuthURL() error = %v", err)
+	}
+
+	code, state, err := o.provider.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+
+	if _, err := o.service.Callback(ctx, testProvider, code, "unknown"); !errors.Is(err, response.ErrInvalidOIDCState) {
+		t.Fatalf("Callback() with unknown state error = %v, want %v", err, response.ErrInvalidOIDCState)
+	}
+
+	if _, err := o.service.Callback(ctx, testProvider, code, state); err != nil {
+		t.Fatalf("Callback() error = %v", err)
+	}
+
+	// state is single use
+	if _, err := o.service.Callback(ctx, testProvider, code, state); !errors.Is(err, response.ErrInvalidOIDCState) {
+		t.Fatalf("replayed Callback() error = %v, want %v", err, response.ErrInvalidOIDCState)
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+type PollService struct {
+	log           *zap.SugaredLogger
+	tracer        trace.Tracer
+	cfg           config.Polls
+	repo          repository.Polls
+	notifications repository.Notifications
+	unread        *UnreadCounts
+	relations     Relation
+	accounts      Account
+}
+
+func NewPollService(log *zap.SugaredLogger, tracer trace.Tracer, cfg config.Polls, repo repository.Polls, notifications repository.Notifications, unread *UnreadCounts, relations Relation, accounts Account) *PollService {
+	return &PollService{log: log, tracer: tracer, cfg: cfg, repo: repo, notifications: notifications, unread: unread, relations: relations, accounts: accounts}
+}
+
+func (p *PollService) CreatePoll(ctx context.Context, authorID, tweetID string, input domain.CreatePollInput) error {
+	ctx, span := p.tracer.Start(ctx, "Service.CreatePoll")
+	defer span.End()
+
+	err := p.repo.CreatePoll(ctx, domain.PollRecord{
+		TweetID:  tweetID,
+		AuthorID: authorID,
+		Options:  input.Options,
+		ClosesAt: time.Now().UTC().Add(time.Duration(input.DurationMinutes) * time.Minute),
+	})
+
+	if err != nil {
+		p.log.Errorf("cannot create poll: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetPolls returns polls of given tweets as seen by viewer
+func (p *PollService) GetPolls(ctx context.Context, viewerID string, tweetIDs []string) (map[string]*domain.Poll, error) {
+	ctx, span := p.tracer.Start(ctx, "Service.GetPolls")
+	defer span.End()
+
+	records, err := p.repo.GetPolls(ctx, tweetIDs)
+	if err != nil {
+		p.log.Errorf("cannot get polls: %v", err)
+		return nil, err
+	}
+
+	result := make(map[string]*domain.Poll, len(records))
+
+	if len(records) == 0 {
+		return result, nil
+	}
+
+	votes, err := p.repo.GetVotes(ctx, viewerID, tweetIDs)
+	if err != nil {
+		p.log.Errorf("cannot get poll votes: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+
+	for tweetID, record := range records {
+		var voted *int
+		if option, ok := votes[tweetID]; ok {
+			voted = &option
+		}
+		result[tweetID] = pollView(record, voted, now)
+	}
+
+	return result, nil
+}
+
+func (p *PollService) Vote(ctx context.Context, userID, tweetID string, option int) (*domain.Poll, error) {
+	ctx, span := p.tracer.Start(ctx, "Service.Vote")
+	defer span.End()
+
+	records, err := p.repo.GetPolls(ctx, []string{tweetID})
+	if err != nil {
+		p.log.Errorf("cannot get polls: %v", err)
+		return nil, err
+	}
+
+	record, ok := records[tweetID]
+	if !ok {
+		return nil, response.ErrPollNotFound
+	}
+
+	// polls on tweets hidden from the user look the same as missing ones
+	if err := checkAccess(ctx, p.relations, p.accounts, userID, record.AuthorID); err != nil {
+		if errors.Is(err, response.ErrBlocked) || errors.Is(err, response.ErrPrivateAccount) {
+			return nil, response.ErrPollNotFound
+		}
+		return nil, err
+	}
+
+	if err := p.repo.Vote(ctx, tweetID, userID, option); err != nil {
+		return nil, err
+	}
+
+	polls, err := p.GetPolls(ctx, userID, []string{tweetID})
+	if err != nil {
+		return nil, err
+	}
+
+	return polls[tweetID], nil
+}
+
+func (p *PollService) DeletePoll(ctx context.Context, tweetID string) error {
+	ctx, span := p.tracer.Start(ctx, "Service.DeletePoll")
+	defer span.End()
+
+	if err := p.repo.DeletePoll(ctx, tweetID); err != nil {
+		p.log.Errorf("cannot delete poll: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ClosePolls notifies voters and authors of polls that have closed since the last call
+func (p *PollService) ClosePolls(ctx context.Context) (int, error) {
+	ctx, span := p.tracer.Start(ctx, "Service.ClosePolls")
+	defer span.End()
+
+	polls, err := p.repo.ClaimClosedPolls(ctx, time.Now(), p.cfg.ClaimTTL, p.cfg.BatchSize)
+	if err != nil {
+		p.log.Errorf("cannot claim closed polls: %v", err)
+		return 0, err
+	}
+
+	for _, poll := range polls {
+		voters, err := p.repo.GetVoters(ctx, poll.TweetID)
+		if err != nil {
+			p.log.Errorf("cannot get voters of poll %s: %v", poll.TweetID, err)
+			continue
+		}
+
+		recipients := append([]string{poll.AuthorID}, voters...)
+		notifications := make([]domain.Notification, 0, len(recipients))
+		seen := make(map[string]bool, len(recipients))
+		now := time.Now().UTC()
+
+		for _, userID := range recipients {
+			if seen[userID] {
+				continue
+			}
+			seen[userID] = true
+
+			notifications = append(notifications, domain.Notification{
+				NotificationID: uuid.New().String(),
+				UserID:         userID,
+				SenderID:       poll.AuthorID,
+				CreatedAt:      now,
+				Type:           domain.PollClosedNotification,
+				TargetID:       poll.TweetID,
+			})
+		}
+
+		if err := p.notifications.CreateNotifications(ctx, notifications); err != nil {
+			p.log.Errorf("cannot notify about closed poll %s: %v", poll.TweetID, err)
+			continue
+		}
+
+		p.unread.Invalidate(recipients...)
+
+		if err := p.repo.MarkPollNotified(ctx, poll.TweetID); err != nil {
+			// voters are notified again once the claim expires
+			p.log.Errorf("cannot mark poll %s as notified: %v", poll.TweetID, err)
+		}
+	}
+
+	return len(polls), nil
+}
+
+func pollView(record domain.PollRecord, voted *int, now time.Time) *domain.Poll {
+	poll := &domain.Poll{
+		Options:     make([]domain.PollOption, 0, len(record.Options)),
+		VotedOption: voted,
+		Closed:      !record.ClosesAt.After(now),
+		ClosesAt:    record.ClosesAt,
+	}
+
+	showTallies := poll.Closed || voted != nil
+	total := 0
+
+	for i, text := range record.Options {
+		option := domain.PollOption{Text: text}
+
+		if showTallies {
+			votes := record.Votes[i]
+			option.Votes = &votes
+			total += votes
+		}
+
+		poll.Options = append(poll.Options, option)
+	}
+
+	if showTallies {
+		poll.TotalVotes = &total
+	}
+
+	return poll
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"time"
+)
+
+const (
+	quietHoursLayout = "15:04"
+	defaultTimeZone  = "UTC"
+)
+
+func (n *NotificationService) GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.GetPreferences")
+	defer span.End()
+
+	preferences, ok, err := n.preferences.GetPreferences(ctx, userID)
+	if err != nil {
+		n.log.Errorf("cannot get notification preferences: %v", err)
+		return domain.NotificationPreferences{}, err
+	}
+
+	if !ok {
+		return domain.NotificationPreferences{
+			UserID:   userID,
+			Types:    make(map[string]domain.ChannelPreferences),
+			TimeZone: defaultTimeZone,
+		}, nil
+	}
+
+	return preferences, nil
+}
+
+func (n *NotificationService) UpdatePreferences(ctx context.Context, userID string, input domain.UpdateNotificationPreferencesInput) (domain.NotificationPreferences, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.UpdatePreferences")
+	defer span.End()
+
+	// muted notification types are not counted
+	defer n.unread.Invalidate(userID)
+
+	timeZone := input.TimeZone
+	if timeZone == "" {
+		timeZone = defaultTimeZone
+	}
+
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return domain.NotificationPreferences{}, response.ErrInvalidPreferences
+	}
+
+	if input.QuietHours.Enabled {
+		if _, err := time.Parse(quietHoursLayout, input.QuietHours.Start); err != nil {
+			return domain.NotificationPreferences{}, response.ErrInvalidPreferences
+		}
+
+		if _, err := time.Parse(quietHoursLayout, input.QuietHours.End); err != nil {
+			return domain.NotificationPreferences{}, response.ErrInvalidPreferences
+		}
+	}
+
+	types := input.Types
+	if types == nil {
+		types = make(map[string]domain.ChannelPreferences)
+	}
+
+	preferences, err := n.preferences.UpdatePreferences(ctx, domain.NotificationPreferences{
+		UserID:     userID,
+		Types:      types,
+		QuietHours: input.QuietHours,
+		TimeZone:   timeZone,
+	})
+
+	if err != nil {
+		n.log.Errorf("cannot update notification preferences: %v", err)
+		return domain.NotificationPreferences{}, err
+	}
+
+	return preferences, nil
+}
+
+// AllowNotification tells whether recipient wants the notification delivered to the channel right now
+func (n *NotificationService) AllowNotification(ctx context.Context, notification domain.Notification, channel domain.NotificationChannel) (bool, error) {
+	ctx, span := n.tracer.Start(ctx, "Service.AllowNotification")
+	defer span.End()
+
+	preferences, err := n.GetPreferences(ctx, notification.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return allowsNotification(preferences, notification.Type, channel, time.Now()), nil
+}
+
+func allowsNotification(preferences domain.NotificationPreferences, notificationType string, channel domain.NotificationChannel, now time.Time) bool {
+	if channels, ok := preferences.Types[notificationType]; ok {
+		switch channel {
+		case domain.ChannelInApp:
+			if !channels.InApp {
+				return false
+			}
+		case domain.ChannelPush:
+			if !channels.Push {
+				return false
+			}
+		case domain.ChannelEmail:
+			if !channels.Email {
+				return false
+			}
+		}
+	}
+
+	// quiet hours only hold back interruptions, notifications list stays complete
+	if channel == domain.ChannelInApp {
+		return true
+	}
+
+	return !inQuietHours(preferences, now)
+}
+
+func inQuietHours(preferences domain.NotificationPreferences, now time.Time) bool {
+	if !preferences.QuietHours.Enabled {
+		return false
+	}
+
+	location, err := time.LoadLocation(preferences.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	start, errStart := time.Parse(quietHoursLayout, preferences.QuietHours.Start)
+	end, errEnd := time.Parse(quietHoursLayout, preferences.QuietHours.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	if startMinute <= endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+
+	return minute >= startMinute || minute < endMinute
+}
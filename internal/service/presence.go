@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type PresenceService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	repo   repository.Presence
+	cfg    config.Presence
+}
+
+func NewPresenceService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.Presence, cfg config.Presence) *PresenceService {
+	return &PresenceService{log: log, tracer: tracer, repo: repo, cfg: cfg}
+}
+
+// Heartbeat keeps connection online for presence ttl
+func (p *PresenceService) Heartbeat(ctx context.Context, sessionID, userID string) error {
+	ctx, span := p.tracer.Start(ctx, "Service.Heartbeat")
+	defer span.End()
+
+	if err := p.repo.TouchSession(ctx, sessionID, userID, p.cfg.TTL); err != nil {
+		p.log.Errorf("cannot touch presence session: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PresenceService) Disconnect(ctx context.Context, sessionID, userID string) error {
+	ctx, span := p.tracer.Start(ctx, "Service.Disconnect")
+	defer span.End()
+
+	if err := p.repo.EndSession(ctx, sessionID, userID); err != nil {
+		p.log.Errorf("cannot end presence session: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PresenceService) GetPresence(ctx context.Context, userID string) (domain.Presence, error) {
+	ctx, span := p.tracer.Start(ctx, "Service.GetPresence")
+	defer span.End()
+
+	presence, err := p.repo.GetPresence(ctx, userID)
+	if err != nil {
+		p.log.Errorf("cannot get presence: %v", err)
+		return domain.Presence{}, err
+	}
+
+	return presence, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"go.uber.org/zap"
+)
+
+// PublishTweet creates tweet with optional uploaded media the same way POST /api/tweets does
+func PublishTweet(ctx context.Context, log *zap.SugaredLogger, tweets Tweet, media Media, userID, text, mediaID string) (string, error) {
+	var image *domain.Image
+
+	if mediaID != "" {
+		var err error
+		if image, err = media.GetImage(ctx, userID, mediaID); err != nil {
+			return "", err
+		}
+	}
+
+	tweetID, err := tweets.CreateTweet(ctx, domain.CreateTweetRequest{
+		UserID: userID,
+		Text:   text,
+		Image:  image,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	// tweet is already visible, so failed attachment is not a reason to report failure
+	if mediaID != "" {
+		if err := media.Attach(ctx, domain.TweetAttachment, tweetID, mediaID); err != nil {
+			log.Errorf("cannot attach media %s to tweet %s: %v", mediaID, tweetID, err)
+		}
+	}
+
+	return tweetID, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/recommendation"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+const followingPageSize = 100
+
+type RecommendationService struct {
+	log         *zap.SugaredLogger
+	tracer      trace.Tracer
+	recommender *recommendation.Recommender
+	relations   Relation
+	auth        Auth
+}
+
+func NewRecommendationService(log *zap.SugaredLogger, tracer trace.Tracer, recommender *recommendation.Recommender, relations Relation, auth Auth) *RecommendationService {
+	return &RecommendationService{log: log, tracer: tracer, recommender: recommender, relations: relations, auth: auth}
+}
+
+func (r *RecommendationService) RecommendUsers(ctx context.Context, userID string, limit int) ([]domain.UserRecommendation, error) {
+	ctx, span := r.tracer.Start(ctx, "Service.RecommendUsers")
+	defer span.End()
+
+	// blocked and muted users in both directions are never suggested
+	hidden, err := r.relations.GetHiddenUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked, err := r.recommender.Recommend(ctx, userID, hidden, pageLimit(limit))
+	if err != nil {
+		r.log.Errorf("cannot recommend users: %v", err)
+		return nil, err
+	}
+
+	result := make([]domain.UserRecommendation, 0, len(ranked))
+
+	for _, item := range ranked {
+		user, err := r.auth.GetUserByID(ctx, item.UserID)
+		if err != nil {
+			// deleted accounts may still be present in the graph
+			r.log.Debugf("cannot get recommended user %s: %v", item.UserID, err)
+			continue
+		}
+
+		result = append(result, domain.UserRecommendation{
+			UserID:        item.UserID,
+			Username:      user.Username,
+			MutualFollows: item.MutualFollows,
+			RecentPosts:   item.RecentPosts,
+			Followers:     item.Followers,
+			Score:         item.Score,
+		})
+	}
+
+	return result, nil
+}
+
+// socialGraph builds recommendation graph from gateway follows and the global tweets feed
+type socialGraph struct {
+	follows repository.Follows
+	tweets  Tweet
+	cfg     config.Recommendations
+}
+
+func NewSocialGraph(follows repository.Follows, tweets Tweet, cfg config.Recommendations) recommendation.Graph {
+	return &socialGraph{follows: follows, tweets: tweets, cfg: cfg}
+}
+
+func (g *socialGraph) Following(ctx context.Context, userID string) ([]string, error) {
+	following := make([]string, 0)
+	cursor := ""
+
+	for len(following) < g.cfg.MaxFollowing {
+		follows, next, err := g.follows.GetFollowing(ctx, userID, cursor, followingPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, follow := range follows {
+			following = append(following, follow.FolloweeID)
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(following) > g.cfg.MaxFollowing {
+		following = following[:g.cfg.MaxFollowing]
+	}
+
+	return following, nil
+}
+
+func (g *socialGraph) FollowerCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return g.follows.GetFollowerCounts(ctx, userIDs)
+}
+
+func (g *socialGraph) RecentPosts(ctx context.Context) (map[string]int, error) {
+	since := time.Now().Add(-g.cfg.ActivityWindow)
+	posts := make(map[string]int)
+	cursor := ""
+
+	for page := 0; page < g.cfg.ActivityPages; page++ {
+		tweets, next, err := g.tweets.GetAllTweets(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tweet := range tweets {
+			if tweet.CreatedAt.After(since) {
+				posts[tweet.UserID]++
+			}
+		}
+
+		// feed is newest first, so the rest is older than the window
+		if next == "" || len(tweets) == 0 || tweets[len(tweets)-1].CreatedAt.Before(since) {
+			break
+		}
+		cursor = next
+	}
+
+	return posts, nil
+}
+
+func (g *socialGraph) MostFollowed(ctx context.Context, limit int) ([]string, error) {
+	return g.follows.GetMostFollowed(ctx, limit)
+}
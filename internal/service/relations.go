@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type RelationService struct {
+	log           *zap.SugaredLogger
+	tracer        trace.Tracer
+	repo          repository.Relations
+	notifications Notification
+}
+
+func NewRelationService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.Relations, notifications Notification) *RelationService {
+	return &RelationService{log: log, tracer: tracer, repo: repo, notifications: notifications}
+}
+
+// Block also removes subscriptions between both users
+func (r *RelationService) Block(ctx context.Context, userID, targetID string) error {
+	ctx, span := r.tracer.Start(ctx, "Service.Block")
+	defer span.End()
+
+	if err := r.addRelation(ctx, userID, targetID, domain.RelationBlock); err != nil {
+		return err
+	}
+
+	// users are usually not subscribed to each other, so errors here are expected
+	if err := r.notifications.UnSubscribeFromUser(ctx, targetID, userID); err != nil {
+		r.log.Debugf("cannot unsubscribe blocked user: %v", err)
+	}
+
+	if err := r.notifications.UnSubscribeFromUser(ctx, userID, targetID); err != nil {
+		r.log.Debugf("cannot unsubscribe from blocked user: %v", err)
+	}
+
+	return nil
+}
+
+func (r *RelationService) Unblock(ctx context.Context, userID, targetID string) error {
+	ctx, span := r.tracer.Start(ctx, "Service.Unblock")
+	defer span.End()
+
+	return r.deleteRelation(ctx, userID, targetID, domain.RelationBlock)
+}
+
+func (r *RelationService) Mute(ctx context.Context, userID, targetID string) error {
+	ctx, span := r.tracer.Start(ctx, "Service.Mute")
+	defer span.End()
+
+	return r.addRelation(ctx, userID, targetID, domain.RelationMute)
+}
+
+func (r *RelationService) Unmute(ctx context.Context, userID, targetID string) error {
+	ctx, span := r.tracer.Start(ctx, "Service.Unmute")
+	defer span.End()
+
+	return r.deleteRelation(ctx, userID, targetID, domain.RelationMute)
+}
+
+// CheckBlocked returns response.ErrBlocked if blockerID has blocked userID
+func (r *RelationService) CheckBlocked(ctx context.Context, blockerID, userID string) error {
+	ctx, span := r.tracer.Start(ctx, "Service.CheckBlocked")
+	defer span.End()
+
+	blocked, err := r.repo.HasRelation(ctx, blockerID, userID, domain.RelationBlock)
+	if err != nil {
+		r.log.Errorf("cannot check block: %v", err)
+		return err
+	}
+
+	if blocked {
+		return response.ErrBlocked
+	}
+
+	return nil
+}
+
+// GetHiddenUsers returns users whose content viewer should not see:
+// muted and blocked by viewer, and those who blocked viewer
+func (r *RelationService) GetHiddenUsers(ctx context.Context, viewerID string) (map[string]struct{}, error) {
+	ctx, span := r.tracer.Start(ctx, "Service.GetHiddenUsers")
+	defer span.End()
+
+	hidden, err := getHiddenUsers(ctx, r.repo, viewerID)
+	if err != nil {
+		r.log.Errorf("cannot get hidden users: %v", err)
+		return nil, err
+	}
+
+	return hidden, nil
+}
+
+func getHiddenUsers(ctx context.Context, repo repository.Relations, viewerID string) (map[string]struct{}, error) {
+	muted, err := repo.GetRelated(ctx, viewerID, domain.RelationMute)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := repo.GetRelated(ctx, viewerID, domain.RelationBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedBy, err := repo.GetRelatedBy(ctx, viewerID, domain.RelationBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden := make(map[string]struct{}, len(muted)+len(blocked)+len(blockedBy))
+
+	for _, ids := range [][]string{muted, blocked, blockedBy} {
+		for _, id := range ids {
+			hidden[id] = struct{}{}
+		}
+	}
+
+	return hidden, nil
+}
+
+func (r *RelationService) addRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) error {
+	if userID == targetID {
+		return response.ErrSelfRelation
+	}
+
+	if err := r.repo.AddRelation(ctx, userID, targetID, relation); err != nil {
+		r.log.Errorf("cannot add %s relation: %v", relation, err)
+		return err
+	}
+
+	r.invalidateUnreadCounts(userID, targetID, relation)
+
+	return nil
+}
+
+func (r *RelationService) deleteRelation(ctx context.Context, userID, targetID string, relation domain.RelationType) error {
+	if err := r.repo.DeleteRelation(ctx, userID, targetID, relation); err != nil {
+		r.log.Errorf("cannot delete %s relation: %v", relation, err)
+		return err
+	}
+
+	r.invalidateUnreadCounts(userID, targetID, relation)
+
+	return nil
+}
+
+// invalidateUnreadCounts drops counts that include or skip notifications of hidden users,
+// blocks hide users from each other, mutes only from the one who muted
+func (r *RelationService) invalidateUnreadCounts(userID, targetID string, relation domain.RelationType) {
+	r.notifications.InvalidateUnreadCount(userID)
+
+	if relation == domain.RelationBlock {
+		r.notifications.InvalidateUnreadCount(targetID)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+type TweetRevisionService struct {
+	log        *zap.SugaredLogger
+	tracer     trace.Tracer
+	repo       repository.TweetRevisions
+	media      Media
+	editWindow time.Duration
+}
+
+func NewTweetRevisionService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.TweetRevisions, media Media, editWindow time.Duration) *TweetRevisionService {
+	return &TweetRevisionService{log: log, tracer: tracer, repo: repo, media: media, editWindow: editWindow}
+}
+
+// RecordRevision keeps current version of hydrated tweet before it is edited
+func (r *TweetRevisionService) RecordRevision(ctx context.Context, tweet domain.TweetResponse) (domain.TweetRevision, error) {
+	ctx, span := r.tracer.Start(ctx, "Service.RecordRevision")
+	defer span.End()
+
+	if r.editWindow > 0 && time.Since(tweet.CreatedAt) > r.editWindow {
+		return domain.TweetRevision{}, response.ErrEditWindowClosed
+	}
+
+	revision := domain.TweetRevision{
+		TweetID:    tweet.TweetID,
+		Text:       tweet.Text,
+		CreatedAt:  tweet.CreatedAt,
+		ReplacedAt: time.Now().UTC(),
+	}
+
+	if tweet.EditedAt != nil {
+		revision.CreatedAt = *tweet.EditedAt
+	}
+
+	if tweet.Media != nil {
+		revision.MediaID = tweet.Media.MediaID
+	}
+
+	revision, err := r.repo.AddRevision(ctx, revision)
+	if err != nil {
+		r.log.Errorf("cannot add tweet revision: %v", err)
+		return domain.TweetRevision{}, err
+	}
+
+	return revision, nil
+}
+
+func (r *TweetRevisionService) DeleteRevision(ctx context.Context, revision domain.TweetRevision) error {
+	ctx, span := r.tracer.Start(ctx, "Service.DeleteRevision")
+	defer span.End()
+
+	if err := r.repo.DeleteRevision(ctx, revision.TweetID, revision.Revision); err != nil {
+		r.log.Errorf("cannot delete tweet revision: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TweetRevisionService) GetHistory(ctx context.Context, tweetID string) ([]domain.TweetRevision, error) {
+	ctx, span := r.tracer.Start(ctx, "Service.GetHistory")
+	defer span.End()
+
+	revisions, err := r.repo.GetRevisions(ctx, tweetID)
+	if err != nil {
+		r.log.Errorf("cannot get tweet revisions: %v", err)
+		return nil, err
+	}
+
+	for i := range revisions {
+		if revisions[i].MediaID == "" {
+			continue
+		}
+
+		if revisions[i].Media, err = r.media.GetMedia(ctx, revisions[i].MediaID); err != nil {
+			return nil, err
+		}
+	}
+
+	return revisions, nil
+}
+
+func (r *TweetRevisionService) GetEditStats(ctx context.Context, tweetIDs []string) (map[string]domain.EditStats, error) {
+	ctx, span := r.tracer.Start(ctx, "Service.GetEditStats")
+	defer span.End()
+
+	stats, err := r.repo.GetEditStats(ctx, tweetIDs)
+	if err != nil {
+		r.log.Errorf("cannot get tweet edit stats: %v", err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *TweetRevisionService) DeleteHistory(ctx context.Context, tweetID string) error {
+	ctx, span := r.tracer.Start(ctx, "Service.DeleteHistory")
+	defer span.End()
+
+	if err := r.repo.DeleteRevisions(ctx, tweetID); err != nil {
+		r.log.Errorf("cannot delete tweet revisions: %v", err)
+		return err
+	}
+
+	return nil
+}
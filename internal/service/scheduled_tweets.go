@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ScheduledTweetService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	repo   repository.ScheduledTweets
+	media  Media
+}
+
+func NewScheduledTweetService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.ScheduledTweets, media Media) *ScheduledTweetService {
+	return &ScheduledTweetService{log: log, tracer: tracer, repo: repo, media: media}
+}
+
+func (s *ScheduledTweetService) CreateScheduledTweet(ctx context.Context, userID string, input domain.CreateScheduledTweetInput) (domain.ScheduledTweet, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.CreateScheduledTweet")
+	defer span.End()
+
+	if !input.PublishAt.After(time.Now()) {
+		return domain.ScheduledTweet{}, response.ErrInvalidPublishAt
+	}
+
+	if err := s.checkMedia(ctx, userID, input.MediaID); err != nil {
+		return domain.ScheduledTweet{}, err
+	}
+
+	tweet, err := s.repo.CreateScheduledTweet(ctx, domain.ScheduledTweet{
+		ScheduledID: uuid.New().String(),
+		UserID:      userID,
+		Text:        input.Text,
+		MediaID:     input.MediaID,
+		PublishAt:   input.PublishAt.UTC(),
+	})
+
+	if err != nil {
+		s.log.Errorf("cannot create scheduled tweet: %v", err)
+		return domain.ScheduledTweet{}, err
+	}
+
+	return tweet, nil
+}
+
+func (s *ScheduledTweetService) GetScheduledTweets(ctx context.Context, userID, cursor string, limit int) ([]domain.ScheduledTweet, string, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetScheduledTweets")
+	defer span.End()
+
+	tweets, cursor, err := s.repo.GetScheduledTweets(ctx, userID, cursor, pageLimit(limit))
+	if err != nil {
+		s.log.Errorf("cannot get scheduled tweets: %v", err)
+		return nil, "", err
+	}
+
+	return tweets, cursor, nil
+}
+
+func (s *ScheduledTweetService) UpdateScheduledTweet(ctx context.Context, userID, scheduledID string, input domain.UpdateScheduledTweetInput) (domain.ScheduledTweet, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.UpdateScheduledTweet")
+	defer span.End()
+
+	tweet, err := s.repo.GetScheduledTweet(ctx, userID, scheduledID)
+	if err != nil {
+		return domain.ScheduledTweet{}, err
+	}
+
+	if input.Text != nil {
+		tweet.Text = *input.Text
+	}
+
+	if input.MediaID != nil {
+		if err := s.checkMedia(ctx, userID, *input.MediaID); err != nil {
+			return domain.ScheduledTweet{}, err
+		}
+		tweet.MediaID = *input.MediaID
+	}
+
+	if input.PublishAt != nil {
+		if !input.PublishAt.After(time.Now()) {
+			return domain.ScheduledTweet{}, response.ErrInvalidPublishAt
+		}
+		tweet.PublishAt = input.PublishAt.UTC()
+	}
+
+	// repository refuses update if scheduler has claimed the tweet in the meantime
+	tweet, err = s.repo.UpdateScheduledTweet(ctx, tweet)
+	if err != nil {
+		s.log.Errorf("cannot update scheduled tweet: %v", err)
+		return domain.ScheduledTweet{}, err
+	}
+
+	return tweet, nil
+}
+
+func (s *ScheduledTweetService) CancelScheduledTweet(ctx context.Context, userID, scheduledID string) error {
+	ctx, span := s.tracer.Start(ctx, "Service.CancelScheduledTweet")
+	defer span.End()
+
+	if err := s.repo.DeleteScheduledTweet(ctx, userID, scheduledID); err != nil {
+		s.log.Errorf("cannot cancel scheduled tweet: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ScheduledTweetService) checkMedia(ctx context.Context, userID, mediaID string) error {
+	if mediaID == "" {
+		return nil
+	}
+
+	_, err := s.media.GetImage(ctx, userID, mediaID)
+	return err
+}
@@ -6,7 +6,12 @@ import (
 	"github.com/Verce11o/yata/internal/config"
 	"github.com/Verce11o/yata/internal/domain"
 	trace "github.com/Verce11o/yata/internal/lib/metrics/tracer"
+	"github.com/Verce11o/yata/internal/realtime"
+	"github.com/Verce11o/yata/internal/recommendation"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/Verce11o/yata/internal/storage"
 	"go.uber.org/zap"
+	"io"
 	"time"
 )
 
@@ -38,18 +43,191 @@ type Comment interface {
 }
 
 type Notification interface {
-	SubscribeToUser(ctx context.Context, userID, toUserID string) error
+	SubscribeToUser(ctx context.Context, userID, toUserID string) (domain.FollowStatus, error)
 	UnSubscribeFromUser(ctx context.Context, userID, toUserID string) error
-	GetNotifications(ctx context.Context, userID string) ([]domain.Notification, error)
+	GetFollowRequests(ctx context.Context, userID, cursor string, limit int) ([]domain.FollowRequest, string, error)
+	ApproveFollowRequest(ctx context.Context, userID, requesterID string) error
+	RejectFollowRequest(ctx context.Context, userID, requesterID string) error
+	GetFollowers(ctx context.Context, viewerID, userID, cursor string, limit int) ([]domain.FollowUser, string, error)
+	GetFollowing(ctx context.Context, viewerID, userID, cursor string, limit int) ([]domain.FollowUser, string, error)
+	GetMutuals(ctx context.Context, viewerID, userID, cursor string, limit int) ([]domain.FollowUser, string, error)
+	GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID string, input domain.UpdateNotificationPreferencesInput) (domain.NotificationPreferences, error)
+	AllowNotification(ctx context.Context, notification domain.Notification, channel domain.NotificationChannel) (bool, error)
+	GetNotifications(ctx context.Context, userID string, query domain.NotificationQuery) ([]domain.Notification, string, error)
+	GetNotificationGroups(ctx context.Context, userID string, query domain.NotificationQuery) ([]domain.NotificationGroup, string, error)
+	MarkGroupAsRead(ctx context.Context, userID, groupID string) error
+	GetUnreadCount(ctx context.Context, userID string) (int, error)
+	InvalidateUnreadCount(userID string)
 	MarkNotificationAsRead(ctx context.Context, userID, notificationID string) error
+	MarkNotificationsAsRead(ctx context.Context, userID string, notificationIDs []string) error
 	ReadAllNotifications(ctx context.Context, userID string) error
 }
 
+type Presence interface {
+	Heartbeat(ctx context.Context, sessionID, userID string) error
+	Disconnect(ctx context.Context, sessionID, userID string) error
+	GetPresence(ctx context.Context, userID string) (domain.Presence, error)
+}
+
+type Conversation interface {
+	CreateConversation(ctx context.Context, userID string, input domain.CreateConversationInput) (domain.Conversation, error)
+	GetConversations(ctx context.Context, userID, cursor string, limit int) ([]domain.Conversation, string, error)
+	GetConversation(ctx context.Context, userID, conversationID string) (domain.Conversation, error)
+	SendMessage(ctx context.Context, userID, conversationID string, input domain.SendMessageInput) (domain.Message, error)
+	GetMessages(ctx context.Context, userID, conversationID, cursor string, limit int) ([]domain.Message, string, error)
+	MarkRead(ctx context.Context, userID, conversationID, messageID string) error
+}
+
+type Webhook interface {
+	CreateWebhook(ctx context.Context, userID string, input domain.CreateWebhookInput) (domain.Webhook, error)
+	GetWebhooks(ctx context.Context, userID string) ([]domain.Webhook, error)
+	GetWebhook(ctx context.Context, userID, webhookID string) (domain.Webhook, error)
+	UpdateWebhook(ctx context.Context, userID, webhookID string, input domain.UpdateWebhookInput) (domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID, webhookID string) error
+	GetDeliveries(ctx context.Context, userID, webhookID, cursor string, limit int) ([]domain.WebhookDelivery, string, error)
+	SendTestEvent(ctx context.Context, userID, webhookID string) (domain.WebhookDelivery, error)
+	Dispatch(ctx context.Context, userID, event string, data any) error
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type APIKey interface {
+	CreateAPIKey(ctx context.Context, userID string, input domain.CreateAPIKeyInput) (domain.APIKey, error)
+	GetAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
+	Authenticate(ctx context.Context, key string) (domain.APIKey, error)
+}
+
+type OIDC interface {
+	AuthURL(ctx context.Context, provider, linkUserID string) (string, error)
+	Callback(ctx context.Context, provider, code, state string) (domain.OIDCResult, error)
+}
+
+type TwoFactor interface {
+	GetStatus(ctx context.Context, userID string) (domain.TwoFactorStatus, error)
+	Setup(ctx context.Context, userID string) (domain.TwoFactorSetup, error)
+	Confirm(ctx context.Context, userID, code string) (domain.RecoveryCodes, error)
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) (domain.RecoveryCodes, error)
+	StartLogin(ctx context.Context, sessionToken string) (string, error)
+	CompleteLogin(ctx context.Context, input domain.TwoFactorLoginInput) (string, error)
+}
+
+type Verification interface {
+	IsVerified(ctx context.Context, userID string) (bool, error)
+	Forget(userID string)
+}
+
+// Publisher pushes events to live connections of a user
+type Publisher interface {
+	Publish(userID string, event realtime.Event) bool
+}
+
+type Media interface {
+	InitUpload(ctx context.Context, userID string, input domain.InitMediaUploadInput) (domain.MediaUpload, error)
+	AppendChunk(ctx context.Context, userID, uploadID string, offset int64, chunk []byte) (domain.MediaUpload, error)
+	CompleteUpload(ctx context.Context, userID, uploadID string) (domain.Media, error)
+	GetImage(ctx context.Context, userID, mediaID string) (*domain.Image, error)
+	StoreImage(ctx context.Context, userID string, image *domain.Image) (string, error)
+	Attach(ctx context.Context, kind domain.AttachmentKind, ownerID, mediaID string) error
+	Detach(ctx context.Context, kind domain.AttachmentKind, ownerID string) error
+	GetAttachment(ctx context.Context, kind domain.AttachmentKind, ownerID string) (*domain.Media, error)
+	GetMedia(ctx context.Context, mediaID string) (*domain.Media, error)
+	OpenMedia(ctx context.Context, name string, expires int64, sign string) (io.ReadSeekCloser, storage.ObjectInfo, error)
+	CollectExpiredUploads(ctx context.Context) (int, error)
+}
+
+type Bookmark interface {
+	AddBookmark(ctx context.Context, userID, tweetID string) error
+	DeleteBookmark(ctx context.Context, userID, tweetID string) error
+	GetBookmarks(ctx context.Context, userID, cursor string, limit int) ([]domain.TweetResponse, string, error)
+	GetBookmarkedTweets(ctx context.Context, userID string, tweetIDs []string) (map[string]bool, error)
+}
+
+type ScheduledTweet interface {
+	CreateScheduledTweet(ctx context.Context, userID string, input domain.CreateScheduledTweetInput) (domain.ScheduledTweet, error)
+	GetScheduledTweets(ctx context.Context, userID, cursor string, limit int) ([]domain.ScheduledTweet, string, error)
+	UpdateScheduledTweet(ctx context.Context, userID, scheduledID string, input domain.UpdateScheduledTweetInput) (domain.ScheduledTweet, error)
+	CancelScheduledTweet(ctx context.Context, userID, scheduledID string) error
+}
+
+type Draft interface {
+	CreateDraft(ctx context.Context, userID string, input domain.CreateDraftInput) (domain.Draft, error)
+	GetDraft(ctx context.Context, userID, draftID string) (domain.Draft, error)
+	GetDrafts(ctx context.Context, userID, cursor string, limit int) ([]domain.Draft, string, error)
+	UpdateDraft(ctx context.Context, userID, draftID string, input domain.UpdateDraftInput) (domain.Draft, error)
+	DeleteDraft(ctx context.Context, userID, draftID string) error
+	PublishDraft(ctx context.Context, userID, draftID string) (string, error)
+}
+
+type Poll interface {
+	CreatePoll(ctx context.Context, authorID, tweetID string, input domain.CreatePollInput) error
+	GetPolls(ctx context.Context, viewerID string, tweetIDs []string) (map[string]*domain.Poll, error)
+	Vote(ctx context.Context, userID, tweetID string, option int) (*domain.Poll, error)
+	DeletePoll(ctx context.Context, tweetID string) error
+	ClosePolls(ctx context.Context) (int, error)
+}
+
+type TweetRevision interface {
+	RecordRevision(ctx context.Context, tweet domain.TweetResponse) (domain.TweetRevision, error)
+	DeleteRevision(ctx context.Context, revision domain.TweetRevision) error
+	GetHistory(ctx context.Context, tweetID string) ([]domain.TweetRevision, error)
+	GetEditStats(ctx context.Context, tweetIDs []string) (map[string]domain.EditStats, error)
+	DeleteHistory(ctx context.Context, tweetID string) error
+}
+
+type Thread interface {
+	GetConversationID(ctx context.Context, userID, inReplyToTweetID string) (string, error)
+	AddReply(ctx context.Context, link domain.ThreadLink) error
+	CreateThread(ctx context.Context, userID string, input domain.CreateThreadInput) ([]string, string, error)
+	GetThread(ctx context.Context, tweetID string) ([]domain.TweetResponse, error)
+	GetLinks(ctx context.Context, tweetIDs []string) (map[string]domain.ThreadLink, error)
+	DeleteLink(ctx context.Context, tweetID string) error
+}
+
+type Relation interface {
+	Block(ctx context.Context, userID, targetID string) error
+	Unblock(ctx context.Context, userID, targetID string) error
+	Mute(ctx context.Context, userID, targetID string) error
+	Unmute(ctx context.Context, userID, targetID string) error
+	CheckBlocked(ctx context.Context, blockerID, userID string) error
+	GetHiddenUsers(ctx context.Context, viewerID string) (map[string]struct{}, error)
+}
+
+type Account interface {
+	GetSettings(ctx context.Context, userID string) (domain.AccountSettings, error)
+	UpdateSettings(ctx context.Context, userID string, input domain.UpdateAccountSettingsInput) (domain.AccountSettings, error)
+	IsPrivate(ctx context.Context, userID string) (bool, error)
+	CheckVisible(ctx context.Context, viewerID, authorID string) error
+	GetProtectedAuthors(ctx context.Context, viewerID string, authorIDs []string) (map[string]struct{}, error)
+}
+
+type Recommendation interface {
+	RecommendUsers(ctx context.Context, userID string, limit int) ([]domain.UserRecommendation, error)
+}
+
 type Services struct {
-	Auth          Auth
-	Tweets        Tweet
-	Comments      Comment
-	Notifications Notification
+	Auth            Auth
+	Tweets          Tweet
+	Comments        Comment
+	Notifications   Notification
+	Media           Media
+	Bookmarks       Bookmark
+	ScheduledTweets ScheduledTweet
+	Drafts          Draft
+	Polls           Poll
+	Revisions       TweetRevision
+	Threads         Thread
+	Relations       Relation
+	Accounts        Account
+	Recommendations Recommendation
+	Presence        Presence
+	Conversations   Conversation
+	Webhooks        Webhook
+	APIKeys         APIKey
+	OIDC            OIDC
+	TwoFactor       TwoFactor
+	Verification    Verification
 }
 
 const (
@@ -59,11 +237,38 @@ const (
 
 // TODO add ping on start
 
-func NewServices(cfg config.Services, log *zap.SugaredLogger, tracer *trace.JaegerTracing) *Services {
+func NewServices(cfg *config.Config, log *zap.SugaredLogger, tracer *trace.JaegerTracing, store storage.BlobStore, repos *repository.Repositories, publisher Publisher) *Services {
+	tweets := NewTweetService(log, tracer.Tracer, clients.MakeTweetsServiceClient(cfg.Services, tracer, grpcRetriesCount, grpcTimeout))
+	media := NewMediaService(log, tracer.Tracer, store, cfg.Media)
+	auth := NewAuthService(log, tracer.Tracer, clients.MakeAuthServiceClient(cfg.Services, tracer, grpcRetriesCount, grpcTimeout))
+	accounts := NewAccountService(log, tracer.Tracer, repos.AccountSettings, repos.Follows)
+	unread := NewUnreadCounts(cfg.Notifications.UnreadCountTTL)
+	webhooks := NewWebhookService(log, tracer.Tracer, repos.Webhooks, cfg.Webhooks)
+	notifications := NewNotificationService(log, tracer.Tracer, clients.MakeNotificationsServiceClient(cfg.Services, tracer, grpcRetriesCount, grpcTimeout), repos.Notifications, repos.Follows, repos.FollowRequests, repos.Preferences, repos.Relations, accounts, auth, webhooks, unread, cfg.Notifications)
+	relations := NewRelationService(log, tracer.Tracer, repos.Relations, notifications)
+	recommender := recommendation.NewRecommender(NewSocialGraph(repos.Follows, tweets, cfg.Recommendations), recommendation.DefaultScorer())
+
 	return &Services{
-		Auth:          NewAuthService(log, tracer.Tracer, clients.MakeAuthServiceClient(cfg, tracer, grpcRetriesCount, grpcTimeout)),
-		Tweets:        NewTweetService(log, tracer.Tracer, clients.MakeTweetsServiceClient(cfg, tracer, grpcRetriesCount, grpcTimeout)),
-		Comments:      NewCommentService(log, tracer.Tracer, clients.MakeCommentsServiceClient(cfg, tracer, grpcRetriesCount, grpcTimeout)),
-		Notifications: NewNotificationService(log, tracer.Tracer, clients.MakeNotificationsServiceClient(cfg, tracer, grpcRetriesCount, grpcTimeout)),
+		Auth:            auth,
+		Tweets:          tweets,
+		Comments:        NewCommentService(log, tracer.Tracer, clients.MakeCommentsServiceClient(cfg.Services, tracer, grpcRetriesCount, grpcTimeout)),
+		Notifications:   notifications,
+		Media:           media,
+		Bookmarks:       NewBookmarkService(log, tracer.Tracer, repos.Bookmarks, tweets),
+		ScheduledTweets: NewScheduledTweetService(log, tracer.Tracer, repos.ScheduledTweets, media),
+		Drafts:          NewDraftService(log, tracer.Tracer, repos.Drafts, tweets, media),
+		Polls:           NewPollService(log, tracer.Tracer, cfg.Polls, repos.Polls, repos.Notifications, unread, relations, accounts),
+		Revisions:       NewTweetRevisionService(log, tracer.Tracer, repos.TweetRevisions, media, cfg.App.EditWindow),
+		Threads:         NewThreadService(log, tracer.Tracer, repos.Threads, tweets, media, relations, accounts),
+		Relations:       relations,
+		Accounts:        accounts,
+		Recommendations: NewRecommendationService(log, tracer.Tracer, recommender, relations, auth),
+		Presence:        NewPresenceService(log, tracer.Tracer, repos.Presence, cfg.Presence),
+		Conversations:   NewConversationService(log, tracer.Tracer, repos.Conversations, repos.Follows, relations, accounts, auth, publisher),
+		Webhooks:        webhooks,
+		APIKeys:         NewAPIKeyService(log, tracer.Tracer, repos.APIKeys),
+		OIDC:            NewOIDCService(log, tracer.Tracer, repos.OIDC, auth, cfg.OIDC, cfg.App.JWT),
+		TwoFactor:       NewTwoFactorService(log, tracer.Tracer, repos.TwoFactor, auth, cfg.TwoFactor, cfg.App.JWT),
+		Verification:    NewVerificationService(log, tracer.Tracer, auth, cfg.Verification),
 	}
 }
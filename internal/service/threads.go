@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxThreadLength guards thread walking against very long or broken chains
+const maxThreadLength = 1000
+
+type ThreadService struct {
+	log       *zap.SugaredLogger
+	tracer    trace.Tracer
+	repo      repository.Threads
+	tweets    Tweet
+	media     Media
+	relations Relation
+	accounts  Account
+}
+
+func NewThreadService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.Threads, tweets Tweet, media Media, relations Relation, accounts Account) *ThreadService {
+	return &ThreadService{log: log, tracer: tracer, repo: repo, tweets: tweets, media: media, relations: relations, accounts: accounts}
+}
+
+// GetConversationID checks that replied tweet exists and is visible to the user,
+// and returns conversation new reply belongs to
+func (t *ThreadService) GetConversationID(ctx context.Context, userID, inReplyToTweetID string) (string, error) {
+	ctx, span := t.tracer.Start(ctx, "Service.GetConversationID")
+	defer span.End()
+
+	target, err := t.tweets.GetTweet(ctx, inReplyToTweetID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", response.ErrReplyTargetNotFound
+		}
+		return "", err
+	}
+
+	// hidden tweets look the same as missing ones, so replying can't be used to probe them
+	if err := checkAccess(ctx, t.relations, t.accounts, userID, target.UserID); err != nil {
+		if errors.Is(err, response.ErrBlocked) || errors.Is(err, response.ErrPrivateAccount) {
+			return "", response.ErrReplyTargetNotFound
+		}
+		return "", err
+	}
+
+	links, err := t.repo.GetLinks(ctx, []string{inReplyToTweetID})
+	if err != nil {
+		t.log.Errorf("cannot get thread links: %v", err)
+		return "", err
+	}
+
+	if link, ok := links[inReplyToTweetID]; ok {
+		return link.ConversationID, nil
+	}
+
+	return inReplyToTweetID, nil
+}
+
+func (t *ThreadService) AddReply(ctx context.Context, link domain.ThreadLink) error {
+	ctx, span := t.tracer.Start(ctx, "Service.AddReply")
+	defer span.End()
+
+	if err := t.repo.AddReply(ctx, link); err != nil {
+		t.log.Errorf("cannot add reply: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateThread publishes tweets one by one, each replying to the previous one.
+// If any of them fails, already published tweets are deleted.
+func (t *ThreadService) CreateThread(ctx context.Context, userID string, input domain.CreateThreadInput) ([]string, string, error) {
+	ctx, span := t.tracer.Start(ctx, "Service.CreateThread")
+	defer span.End()
+
+	inReplyTo := input.InReplyToTweetID
+	var conversationID string
+
+	if inReplyTo != "" {
+		var err error
+		if conversationID, err = t.GetConversationID(ctx, userID, inReplyTo); err != nil {
+			return nil, "", err
+		}
+	}
+
+	created := make([]string, 0, len(input.Tweets))
+
+	for _, tweet := range input.Tweets {
+		tweetID, err := PublishTweet(ctx, t.log, t.tweets, t.media, userID, tweet.Text, tweet.MediaID)
+		if err != nil {
+			t.log.Errorf("cannot publish thread tweet: %v", err)
+			t.rollback(ctx, userID, created)
+			return nil, "", err
+		}
+
+		created = append(created, tweetID)
+
+		if conversationID == "" {
+			conversationID = tweetID
+		}
+
+		if inReplyTo != "" {
+			err := t.repo.AddReply(ctx, domain.ThreadLink{
+				TweetID:          tweetID,
+				InReplyToTweetID: inReplyTo,
+				ConversationID:   conversationID,
+				AuthorID:         userID,
+			})
+
+			if err != nil {
+				t.log.Errorf("cannot link thread tweet: %v", err)
+				t.rollback(ctx, userID, created)
+				return nil, "", err
+			}
+		}
+
+		inReplyTo = tweetID
+	}
+
+	return created, conversationID, nil
+}
+
+// GetThread returns tweets from conversation root down to the given tweet,
+// followed by the author's self-replies continuing the thread
+func (t *ThreadService) GetThread(ctx context.Context, tweetID string) ([]domain.TweetResponse, error) {
+	ctx, span := t.tracer.Start(ctx, "Service.GetThread")
+	defer span.End()
+
+	tweet, err := t.tweets.GetTweet(ctx, tweetID)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors := make([]domain.TweetResponse, 0)
+	current := tweetID
+
+	for len(ancestors) < maxThreadLength {
+		links, err := t.repo.GetLinks(ctx, []string{current})
+		if err != nil {
+			t.log.Errorf("cannot get thread links: %v", err)
+			return nil, err
+		}
+
+		link, ok := links[current]
+		if !ok {
+			break
+		}
+
+		parent, err := t.tweets.GetTweet(ctx, link.InReplyToTweetID)
+		if status.Code(err) == codes.NotFound {
+			// chain is broken by deleted tweet
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ancestors = append(ancestors, parent)
+		current = parent.TweetID
+	}
+
+	thread := make([]domain.TweetResponse, 0, len(ancestors)+1)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		thread = append(thread, ancestors[i])
+	}
+	thread = append(thread, tweet)
+
+	for last := tweet; len(thread) < maxThreadLength; {
+		next, ok, err := t.nextSelfReply(ctx, last)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		thread = append(thread, next)
+		last = next
+	}
+
+	return thread, nil
+}
+
+func (t *ThreadService) GetLinks(ctx context.Context, tweetIDs []string) (map[string]domain.ThreadLink, error) {
+	ctx, span := t.tracer.Start(ctx, "Service.GetLinks")
+	defer span.End()
+
+	links, err := t.repo.GetLinks(ctx, tweetIDs)
+	if err != nil {
+		t.log.Errorf("cannot get thread links: %v", err)
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (t *ThreadService) DeleteLink(ctx context.Context, tweetID string) error {
+	ctx, span := t.tracer.Start(ctx, "Service.DeleteLink")
+	defer span.End()
+
+	if err := t.repo.DeleteLink(ctx, tweetID); err != nil {
+		t.log.Errorf("cannot delete thread link: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// nextSelfReply finds the earliest existing reply of tweet written by the same author
+func (t *ThreadService) nextSelfReply(ctx context.Context, tweet domain.TweetResponse) (domain.TweetResponse, bool, error) {
+	replies, err := t.repo.GetReplies(ctx, tweet.TweetID)
+	if err != nil {
+		t.log.Errorf("cannot get replies: %v", err)
+		return domain.TweetResponse{}, false, err
+	}
+
+	for _, reply := range replies {
+		if reply.AuthorID != tweet.UserID {
+			continue
+		}
+
+		next, err := t.tweets.GetTweet(ctx, reply.TweetID)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return domain.TweetResponse{}, false, err
+		}
+
+		return next, true, nil
+	}
+
+	return domain.TweetResponse{}, false, nil
+}
+
+func (t *ThreadService) rollback(ctx context.Context, userID string, tweetIDs []string) {
+	for i := len(tweetIDs) - 1; i >= 0; i-- {
+		tweetID := tweetIDs[i]
+
+		if err := t.tweets.DeleteTweet(ctx, userID, tweetID); err != nil {
+			t.log.Errorf("cannot rollback thread tweet %s: %v", tweetID, err)
+			continue
+		}
+
+		if err := t.media.Detach(ctx, domain.TweetAttachment, tweetID); err != nil {
+			t.log.Errorf("cannot detach media of thread tweet %s: %v", tweetID, err)
+		}
+
+		if err := t.repo.DeleteLink(ctx, tweetID); err != nil {
+			t.log.Errorf("cannot delete link of thread tweet %s: %v", tweetID, err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/lib/token"
+	"github.com/Verce11o/yata/internal/lib/totp"
+	"github.com/Verce11o/yata/internal/repository"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// recoveryCodeBytes gives 10 character codes, they are shown as xxxxx-xxxxx
+const recoveryCodeBytes = 6
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	repo   repository.TwoFactor
+	auth   Auth
+	cfg    config.TwoFactor
+	jwt    config.JWTConfig
+}
+
+func NewTwoFactorService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.TwoFactor, auth Auth, cfg config.TwoFactor, jwt config.JWTConfig) *TwoFactorService {
+	return &TwoFactorService{log: log, tracer: tracer, repo: repo, auth: auth, cfg: cfg, jwt: jwt}
+}
+
+func (s *TwoFactorService) GetStatus(ctx context.Context, userID string) (domain.TwoFactorStatus, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetTwoFactorStatus")
+	defer span.End()
+
+	enabled, err := s.enabled(ctx, userID)
+	if err != nil || !enabled {
+		return domain.TwoFactorStatus{}, err
+	}
+
+	left, err := s.repo.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		s.log.Errorf("cannot count recovery codes: %v", err)
+		return domain.TwoFactorStatus{}, err
+	}
+
+	return domain.TwoFactorStatus{Enabled: true, RecoveryCodesLeft: left}, nil
+}
+
+// Setup starts enrollment with a new secret, it is not required on login until confirmed
+func (s *TwoFactorService) Setup(ctx context.Context, userID string) (domain.TwoFactorSetup, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.SetupTwoFactor")
+	defer span.End()
+
+	user, err := s.auth.GetUserByID(ctx, userID)
+	if err != nil {
+		return domain.TwoFactorSetup{}, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return domain.TwoFactorSetup{}, err
+	}
+
+	if err := s.repo.SaveSetup(ctx, userID, secret); err != nil {
+		if !errors.Is(err, response.ErrTwoFactorEnabled) {
+			s.log.Errorf("cannot save two factor setup: %v", err)
+		}
+		return domain.TwoFactorSetup{}, err
+	}
+
+	return domain.TwoFactorSetup{
+		Secret: secret,
+		URI:    totp.URI(s.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two factor authentication once user proves authenticator works, recovery codes are shown only here
+func (s *TwoFactorService) Confirm(ctx context.Context, userID, code string) (domain.RecoveryCodes, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.ConfirmTwoFactor")
+	defer span.End()
+
+	settings, err := s.repo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return domain.RecoveryCodes{}, err
+	}
+
+	if settings.Enabled {
+		return domain.RecoveryCodes{}, response.ErrTwoFactorEnabled
+	}
+
+	step, ok := totp.Validate(settings.Secret, normalizeCode(code), time.Now(), s.cfg.Skew)
+	if !ok {
+		return domain.RecoveryCodes{}, response.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return domain.RecoveryCodes{}, err
+	}
+
+	if err := s.repo.EnableTwoFactor(ctx, userID, step, hashes); err != nil {
+		s.log.Errorf("cannot enable two factor: %v", err)
+		return domain.RecoveryCodes{}, err
+	}
+
+	return domain.RecoveryCodes{Codes: codes}, nil
+}
+
+// Disable needs a fresh code, so stolen session alone cannot remove second factor
+func (s *TwoFactorService) Disable(ctx context.Context, userID, code string) error {
+	ctx, span := s.tracer.Start(ctx, "Service.DisableTwoFactor")
+	defer span.End()
+
+	if err := s.verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteTwoFactor(ctx, userID); err != nil {
+		s.log.Errorf("cannot disable two factor: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, used and unused ones
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) (domain.RecoveryCodes, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.RegenerateRecoveryCodes")
+	defer span.End()
+
+	if err := s.verify(ctx, userID, code); err != nil {
+		return domain.RecoveryCodes{}, err
+	}
+
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return domain.RecoveryCodes{}, err
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		s.log.Errorf("cannot replace recovery codes: %v", err)
+		return domain.RecoveryCodes{}, err
+	}
+
+	return domain.RecoveryCodes{Codes: codes}, nil
+}
+
+// StartLogin is called after password was accepted, for users with two factor authentication
+// session token is dropped and challenge token is returned instead, empty one means no second step
+func (s *TwoFactorService) StartLogin(ctx context.Context, sessionToken string) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.StartLogin")
+	defer span.End()
+
+	userID, err := token.ParseToken(sessionToken, s.jwt.Secret)
+	if err != nil {
+		return "", err
+	}
+
+	enabled, err := s.enabled(ctx, userID)
+	if err != nil || !enabled {
+		return "", err
+	}
+
+	challengeToken, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repo.CreateChallenge(ctx, domain.TwoFactorChallenge{
+		TokenHash: hashSecret(challengeToken),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(s.cfg.ChallengeTTL),
+	})
+
+	if err != nil {
+		s.log.Errorf("cannot create login challenge: %v", err)
+		return "", err
+	}
+
+	return challengeToken, nil
+}
+
+// CompleteLogin exchanges challenge token and code for session token, challenge allows only a few attempts
+func (s *TwoFactorService) CompleteLogin(ctx context.Context, input domain.TwoFactorLoginInput) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.CompleteLogin")
+	defer span.End()
+
+	tokenHash := hashSecret(input.ChallengeToken)
+
+	challenge, err := s.repo.AttemptChallenge(ctx, tokenHash, time.Now(), s.cfg.MaxAttempts)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.verify(ctx, challenge.UserID, input.Code); err != nil {
+		return "", err
+	}
+
+	if err := s.repo.DeleteChallenge(ctx, tokenHash); err != nil {
+		s.log.Errorf("cannot delete login challenge: %v", err)
+		return "", err
+	}
+
+	return token.GenerateToken(challenge.UserID, s.jwt.Secret, time.Duration(s.jwt.TokenTTLHours)*time.Hour)
+}
+
+// verify accepts authenticator code or unused recovery code of user with enabled two factor authentication
+func (s *TwoFactorService) verify(ctx context.Context, userID, code string) error {
+	settings, err := s.repo.GetTwoFactor(ctx, userID)
+	if errors.Is(err, response.ErrTwoFactorSetupNotFound) || (err == nil && !settings.Enabled) {
+		return response.ErrTwoFactorNotEnabled
+	}
+
+	if err != nil {
+		return err
+	}
+
+	code = normalizeCode(code)
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(settings.Secret, code, time.Now(), s.cfg.Skew)
+		if !ok {
+			return response.ErrInvalidTwoFactorCode
+		}
+
+		return s.repo.UseStep(ctx, userID, step)
+	}
+
+	return s.repo.UseRecoveryCode(ctx, userID, hashSecret(strings.ToUpper(code)))
+}
+
+func (s *TwoFactorService) enabled(ctx context.Context, userID string) (bool, error) {
+	settings, err := s.repo.GetTwoFactor(ctx, userID)
+	if errors.Is(err, response.ErrTwoFactorSetupNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		s.log.Errorf("cannot get two factor settings: %v", err)
+		return false, err
+	}
+
+	return settings.Enabled, nil
+}
+
+func (s *TwoFactorService) newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, s.cfg.RecoveryCodes)
+	hashes := make([]string, 0, s.cfg.RecoveryCodes)
+
+	for i := 0; i < s.cfg.RecoveryCodes; i++ {
+		raw := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		code := recoveryEncoding.EncodeToString(raw)
+
+		codes = append(codes, strings.ToLower(code[:5]+"-"+code[5:]))
+		hashes = append(hashes, hashSecret(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeCode drops separators users type or copy along with codes
+func normalizeCode(code string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code))
+}
+
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
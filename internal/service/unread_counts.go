@@ -0,0 +1,81 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// unreadCountsSize is when expired entries are swept from cache
+const unreadCountsSize = 10000
+
+type unreadCount struct {
+	count     int
+	counted   bool
+	expiresAt time.Time
+	// invalidatedAt keeps count computed before the last change from being stored
+	invalidatedAt time.Time
+}
+
+// UnreadCounts caches unread notification badges. Notifications service cannot count them,
+// so every count lists all notifications; it is invalidated by anything that creates or reads them
+type UnreadCounts struct {
+	mu     sync.Mutex
+	counts map[string]unreadCount
+	ttl    time.Duration
+}
+
+func NewUnreadCounts(ttl time.Duration) *UnreadCounts {
+	return &UnreadCounts{counts: make(map[string]unreadCount), ttl: ttl}
+}
+
+func (u *UnreadCounts) get(userID string, now time.Time) (int, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	entry, ok := u.counts[userID]
+	if !ok || !entry.counted || !now.Before(entry.expiresAt) {
+		return 0, false
+	}
+
+	return entry.count, true
+}
+
+// set stores count started at countedAt unless user's notifications changed since then
+func (u *UnreadCounts) set(userID string, count int, countedAt time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	entry := u.counts[userID]
+	if !entry.invalidatedAt.IsZero() && !entry.invalidatedAt.Before(countedAt) {
+		return
+	}
+
+	u.sweep(countedAt)
+
+	u.counts[userID] = unreadCount{count: count, counted: true, expiresAt: countedAt.Add(u.ttl), invalidatedAt: entry.invalidatedAt}
+}
+
+func (u *UnreadCounts) Invalidate(userIDs ...string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := time.Now()
+	u.sweep(now)
+
+	for _, userID := range userIDs {
+		// entry is kept until ttl, so count that is being computed right now is not stored
+		u.counts[userID] = unreadCount{expiresAt: now.Add(u.ttl), invalidatedAt: now}
+	}
+}
+
+func (u *UnreadCounts) sweep(now time.Time) {
+	if len(u.counts) < unreadCountsSize {
+		return
+	}
+
+	for userID, entry := range u.counts {
+		if !now.Before(entry.expiresAt) {
+			delete(u.counts, userID)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"github.com/Verce11o/yata/internal/config"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// verificationCacheSize is when expired entries are swept from cache
+const verificationCacheSize = 10000
+
+type verificationEntry struct {
+	verified  bool
+	expiresAt time.Time
+}
+
+// VerificationService caches whether users verified their email, so mutating requests do not call auth service every time
+type VerificationService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	auth   Auth
+	cfg    config.Verification
+
+	mu    sync.Mutex
+	cache map[string]verificationEntry
+}
+
+func NewVerificationService(log *zap.SugaredLogger, tracer trace.Tracer, auth Auth, cfg config.Verification) *VerificationService {
+	return &VerificationService{log: log, tracer: tracer, auth: auth, cfg: cfg, cache: make(map[string]verificationEntry)}
+}
+
+func (s *VerificationService) IsVerified(ctx context.Context, userID string) (bool, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.IsVerified")
+	defer span.End()
+
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.cache[userID]
+	s.mu.Unlock()
+
+	if ok && now.Before(entry.expiresAt) {
+		return entry.verified, nil
+	}
+
+	user, err := s.auth.GetUserByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	// verification is not undone, unverified users are rechecked sooner to pick up activation made elsewhere
+	ttl := s.cfg.CacheTTL
+	if !user.IsVerified {
+		ttl = s.cfg.UnverifiedCacheTTL
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= verificationCacheSize {
+		for id, cached := range s.cache {
+			if !now.Before(cached.expiresAt) {
+				delete(s.cache, id)
+			}
+		}
+	}
+
+	s.cache[userID] = verificationEntry{verified: user.IsVerified, expiresAt: now.Add(ttl)}
+
+	return user.IsVerified, nil
+}
+
+// Forget drops cached status, it is called when user activates account
+func (s *VerificationService) Forget(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cache, userID)
+}
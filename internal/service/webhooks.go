@@ -0,0 +1,369 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Verce11o/yata/internal/config"
+	"github.com/Verce11o/yata/internal/domain"
+	"github.com/Verce11o/yata/internal/lib/egress"
+	"github.com/Verce11o/yata/internal/lib/response"
+	"github.com/Verce11o/yata/internal/lib/signature"
+	"github.com/Verce11o/yata/internal/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const webhookSecretBytes = 32
+
+type WebhookService struct {
+	log    *zap.SugaredLogger
+	tracer trace.Tracer
+	repo   repository.Webhooks
+	cfg    config.Webhooks
+	client *http.Client
+}
+
+func NewWebhookService(log *zap.SugaredLogger, tracer trace.Tracer, repo repository.Webhooks, cfg config.Webhooks) *WebhookService {
+	client := egress.NewClient(cfg.Timeout)
+	if cfg.AllowPrivateNetworks {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	return &WebhookService{log: log, tracer: tracer, repo: repo, cfg: cfg, client: client}
+}
+
+// CreateWebhook returns the signing secret, it is not shown again
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID string, input domain.CreateWebhookInput) (domain.Webhook, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.CreateWebhook")
+	defer span.End()
+
+	if err := s.checkURL(ctx, input.URL); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	webhook, err := s.repo.CreateWebhook(ctx, domain.Webhook{
+		WebhookID: uuid.NewString(),
+		UserID:    userID,
+		URL:       input.URL,
+		Events:    input.Events,
+		Secret:    hex.EncodeToString(secret),
+		Enabled:   true,
+	})
+
+	if err != nil {
+		s.log.Errorf("cannot create webhook: %v", err)
+		return domain.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context, userID string) ([]domain.Webhook, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetWebhooks")
+	defer span.End()
+
+	webhooks, err := s.repo.GetWebhooks(ctx, userID)
+	if err != nil {
+		s.log.Errorf("cannot get webhooks: %v", err)
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, userID, webhookID string) (domain.Webhook, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetWebhook")
+	defer span.End()
+
+	webhook, err := s.repo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	if webhook.UserID != userID {
+		return domain.Webhook{}, response.ErrWebhookNotFound
+	}
+
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+func (s *WebhookService) UpdateWebhook(ctx context.Context, userID, webhookID string, input domain.UpdateWebhookInput) (domain.Webhook, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.UpdateWebhook")
+	defer span.End()
+
+	webhook, err := s.GetWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	if input.URL != nil {
+		if err := s.checkURL(ctx, *input.URL); err != nil {
+			return domain.Webhook{}, err
+		}
+		webhook.URL = *input.URL
+	}
+
+	if input.Events != nil {
+		webhook.Events = input.Events
+	}
+
+	if input.Enabled != nil {
+		if *input.Enabled && !webhook.Enabled {
+			webhook.Failures = 0
+		}
+		webhook.Enabled = *input.Enabled
+	}
+
+	updated, err := s.repo.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		s.log.Errorf("cannot update webhook: %v", err)
+		return domain.Webhook{}, err
+	}
+
+	updated.Secret = ""
+
+	return updated, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	ctx, span := s.tracer.Start(ctx, "Service.DeleteWebhook")
+	defer span.End()
+
+	return s.repo.DeleteWebhook(ctx, userID, webhookID)
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, userID, webhookID, cursor string, limit int) ([]domain.WebhookDelivery, string, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.GetDeliveries")
+	defer span.End()
+
+	if _, err := s.GetWebhook(ctx, userID, webhookID); err != nil {
+		return nil, "", err
+	}
+
+	deliveries, nextCursor, err := s.repo.GetDeliveries(ctx, webhookID, cursor, pageLimit(limit))
+	if err != nil {
+		s.log.Errorf("cannot get webhook deliveries: %v", err)
+		return nil, "", err
+	}
+
+	return deliveries, nextCursor, nil
+}
+
+// SendTestEvent queues test delivery even to disabled webhook, so endpoint can be checked before enabling it again
+func (s *WebhookService) SendTestEvent(ctx context.Context, userID, webhookID string) (domain.WebhookDelivery, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.SendTestEvent")
+	defer span.End()
+
+	webhook, err := s.GetWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	return s.queue(ctx, webhook, domain.WebhookEventTest, map[string]string{"webhook_id": webhook.WebhookID})
+}
+
+// Dispatch queues event for every webhook of user subscribed to it
+func (s *WebhookService) Dispatch(ctx context.Context, userID, event string, data any) error {
+	ctx, span := s.tracer.Start(ctx, "Service.Dispatch")
+	defer span.End()
+
+	webhooks, err := s.repo.GetSubscribedWebhooks(ctx, userID, event)
+	if err != nil {
+		s.log.Errorf("cannot get subscribed webhooks: %v", err)
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		if _, err := s.queue(ctx, webhook, event, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *WebhookService) queue(ctx context.Context, webhook domain.Webhook, event string, data any) (domain.WebhookDelivery, error) {
+	deliveryID := uuid.NewString()
+
+	payload, err := json.Marshal(domain.WebhookPayload{
+		DeliveryID: deliveryID,
+		Event:      event,
+		CreatedAt:  time.Now().UTC(),
+		Data:       data,
+	})
+
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	delivery, err := s.repo.CreateDelivery(ctx, domain.WebhookDelivery{
+		DeliveryID: deliveryID,
+		WebhookID:  webhook.WebhookID,
+		Event:      event,
+		Payload:    payload,
+	})
+
+	if err != nil {
+		s.log.Errorf("cannot create webhook delivery: %v", err)
+		return domain.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// DeliverDue sends due deliveries, a delivery is claimed for a while, so replicas do not send it twice
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.DeliverDue")
+	defer span.End()
+
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, time.Now(), s.cfg.ClaimTTL, s.cfg.BatchSize)
+	if err != nil {
+		s.log.Errorf("cannot claim webhook deliveries: %v", err)
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		s.deliver(ctx, delivery)
+	}
+
+	return len(deliveries), nil
+}
+
+func (s *WebhookService) deliver(ctx context.Context, delivery domain.WebhookDelivery) {
+	webhook, err := s.repo.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		s.log.Errorf("cannot get webhook %s: %v", delivery.WebhookID, err)
+		return
+	}
+
+	test := delivery.Event == domain.WebhookEventTest
+
+	if !webhook.Enabled && !test {
+		s.finish(ctx, delivery, domain.WebhookDeliveryFailed, 0, "webhook is disabled")
+		return
+	}
+
+	responseStatus, err := s.send(ctx, webhook, delivery)
+
+	if err == nil {
+		s.finish(ctx, delivery, domain.WebhookDeliverySucceeded, responseStatus, "")
+		if !test {
+			s.record(ctx, webhook, true)
+		}
+		return
+	}
+
+	if delivery.Attempts < s.cfg.MaxAttempts {
+		s.log.Debugf("cannot deliver webhook %s, attempt %d: %v", delivery.DeliveryID, delivery.Attempts, err)
+
+		if err := s.repo.RetryDelivery(ctx, delivery.DeliveryID, responseStatus, err.Error(), time.Now().Add(s.backoff(delivery.Attempts))); err != nil {
+			s.log.Errorf("cannot reschedule webhook delivery %s: %v", delivery.DeliveryID, err)
+		}
+		return
+	}
+
+	s.finish(ctx, delivery, domain.WebhookDeliveryFailed, responseStatus, err.Error())
+	if !test {
+		s.record(ctx, webhook, false)
+	}
+}
+
+// send posts payload signed over "timestamp.payload", so receivers can reject replayed requests
+func (s *WebhookService) send(ctx context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery) (int, error) {
+	ctx, span := s.tracer.Start(ctx, "Service.SendWebhook")
+	defer span.End()
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signed := append([]byte(timestamp+"."), delivery.Payload...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "yata-webhooks")
+	req.Header.Set("X-Yata-Event", delivery.Event)
+	req.Header.Set("X-Yata-Delivery", delivery.DeliveryID)
+	req.Header.Set("X-Yata-Timestamp", timestamp)
+	req.Header.Set("X-Yata-Signature", "sha256="+signature.Sign(webhook.Secret, signed))
+
+	resp, err := s.client.Do(req)
+	if errors.Is(err, egress.ErrAddressNotAllowed) {
+		// resolved address is not logged, delivery log is visible to webhook owner
+		return 0, egress.ErrAddressNotAllowed
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain body, so connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// checkURL gives early feedback on internal urls, deliveries are checked again when connecting
+func (s *WebhookService) checkURL(ctx context.Context, rawURL string) error {
+	if s.cfg.AllowPrivateNetworks {
+		return nil
+	}
+
+	if err := egress.CheckURL(ctx, rawURL); err != nil {
+		return response.ErrWebhookURLNotAllowed
+	}
+
+	return nil
+}
+
+func (s *WebhookService) finish(ctx context.Context, delivery domain.WebhookDelivery, status domain.WebhookDeliveryStatus, responseStatus int, lastError string) {
+	if err := s.repo.FinishDelivery(ctx, delivery.DeliveryID, status, responseStatus, lastError); err != nil {
+		s.log.Errorf("cannot finish webhook delivery %s: %v", delivery.DeliveryID, err)
+	}
+}
+
+func (s *WebhookService) record(ctx context.Context, webhook domain.Webhook, succeeded bool) {
+	updated, err := s.repo.RecordDelivery(ctx, webhook.WebhookID, succeeded, s.cfg.DisableAfter)
+	if err != nil {
+		s.log.Errorf("cannot record webhook delivery: %v", err)
+		return
+	}
+
+	if webhook.Enabled && !updated.Enabled {
+		s.log.Infof("webhook %s disabled after %d failed deliveries", webhook.WebhookID, updated.Failures)
+	}
+}
+
+func (s *WebhookService) backoff(attempt int) time.Duration {
+	backoff := s.cfg.RetryBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > s.cfg.MaxRetryBackoff {
+		return s.cfg.MaxRetryBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/Verce11o/yata/internal/config"
+	"io"
+	"log"
+	"time"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+	Metadata    map[string]string
+}
+
+// BlobStore keeps media files and partial uploads
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string, metadata map[string]string) (ObjectInfo, error)
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+func NewBlobStore(cfg config.Media) BlobStore {
+	switch cfg.Storage {
+	case "s3":
+		store, err := NewS3Store(cfg.S3)
+		if err != nil {
+			log.Fatalf("error while connect to s3 storage: %v", err)
+		}
+		return store
+	default:
+		store, err := NewLocalStore(cfg.LocalPath)
+		if err != nil {
+			log.Fatalf("error while init local storage: %v", err)
+		}
+		return store
+	}
+}
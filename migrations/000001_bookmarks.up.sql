@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS bookmarks
+(
+    user_id    UUID        NOT NULL,
+    tweet_id   UUID        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, tweet_id)
+);
+
+CREATE INDEX IF NOT EXISTS bookmarks_user_created_idx ON bookmarks (user_id, created_at DESC, tweet_id DESC);
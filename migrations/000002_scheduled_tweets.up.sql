@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS scheduled_tweets
+(
+    id           UUID PRIMARY KEY,
+    user_id      UUID        NOT NULL,
+    text         TEXT        NOT NULL,
+    media_id     TEXT        NOT NULL DEFAULT '',
+    publish_at   TIMESTAMPTZ NOT NULL,
+    status       TEXT        NOT NULL,
+    tweet_id     TEXT        NOT NULL DEFAULT '',
+    attempts     INT         NOT NULL DEFAULT 0,
+    last_error   TEXT        NOT NULL DEFAULT '',
+    locked_until TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_tweets_user_created_idx ON scheduled_tweets (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS scheduled_tweets_due_idx ON scheduled_tweets (publish_at) WHERE status IN ('pending', 'publishing');
+
+CREATE TABLE IF NOT EXISTS leases
+(
+    name       TEXT PRIMARY KEY,
+    holder     TEXT        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
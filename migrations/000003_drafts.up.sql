@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS drafts
+(
+    id                 UUID PRIMARY KEY,
+    user_id            UUID        NOT NULL,
+    text               TEXT        NOT NULL DEFAULT '',
+    media_ids          TEXT        NOT NULL DEFAULT '',
+    version            BIGINT      NOT NULL DEFAULT 1,
+    published_tweet_id TEXT        NOT NULL DEFAULT '',
+    locked_until       TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS drafts_user_created_idx ON drafts (user_id, created_at DESC, id DESC);
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS polls
+(
+    tweet_id        UUID PRIMARY KEY,
+    author_id       UUID        NOT NULL,
+    options         TEXT        NOT NULL,
+    closes_at       TIMESTAMPTZ NOT NULL,
+    closed_notified BOOLEAN     NOT NULL DEFAULT false,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS polls_closing_idx ON polls (closes_at) WHERE NOT closed_notified;
+
+CREATE TABLE IF NOT EXISTS poll_votes
+(
+    tweet_id   UUID        NOT NULL REFERENCES polls (tweet_id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL,
+    option     INT         NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (tweet_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL,
+    sender_id  UUID        NOT NULL,
+    type       TEXT        NOT NULL,
+    target_id  TEXT        NOT NULL DEFAULT '',
+    read       BOOLEAN     NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_created_idx ON notifications (user_id, created_at DESC);
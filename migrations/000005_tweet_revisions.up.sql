@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS tweet_revisions
+(
+    tweet_id    UUID        NOT NULL,
+    revision    INT         NOT NULL,
+    text        TEXT        NOT NULL,
+    media_id    TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL,
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (tweet_id, revision)
+);
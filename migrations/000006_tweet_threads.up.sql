@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS tweet_threads
+(
+    tweet_id             UUID PRIMARY KEY,
+    in_reply_to_tweet_id UUID        NOT NULL,
+    conversation_id      UUID        NOT NULL,
+    author_id            UUID        NOT NULL,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS tweet_threads_reply_idx ON tweet_threads (in_reply_to_tweet_id, created_at);
+CREATE INDEX IF NOT EXISTS tweet_threads_conversation_idx ON tweet_threads (conversation_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_relations
+(
+    user_id    UUID        NOT NULL,
+    target_id  UUID        NOT NULL,
+    relation   VARCHAR(16) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, target_id, relation)
+);
+
+CREATE INDEX IF NOT EXISTS user_relations_target_idx ON user_relations (target_id, relation);
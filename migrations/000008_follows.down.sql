@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS follow_requests;
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS account_settings;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS account_settings
+(
+    user_id    UUID PRIMARY KEY,
+    is_private BOOLEAN     NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS follows
+(
+    follower_id UUID        NOT NULL,
+    followee_id UUID        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (follower_id, followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followee_idx ON follows (followee_id, created_at);
+
+CREATE TABLE IF NOT EXISTS follow_requests
+(
+    requester_id UUID        NOT NULL,
+    target_id    UUID        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (requester_id, target_id)
+);
+
+CREATE INDEX IF NOT EXISTS follow_requests_target_idx ON follow_requests (target_id, created_at);
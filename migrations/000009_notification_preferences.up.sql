@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS notification_preferences
+(
+    user_id       UUID PRIMARY KEY,
+    types         TEXT        NOT NULL DEFAULT '{}',
+    quiet_enabled BOOLEAN     NOT NULL DEFAULT FALSE,
+    quiet_start   VARCHAR(5)  NOT NULL DEFAULT '',
+    quiet_end     VARCHAR(5)  NOT NULL DEFAULT '',
+    time_zone     VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_presence;
+DROP TABLE IF EXISTS presence_sessions;
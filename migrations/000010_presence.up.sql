@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS presence_sessions
+(
+    session_id UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS presence_sessions_user_idx ON presence_sessions (user_id, expires_at);
+
+CREATE TABLE IF NOT EXISTS user_presence
+(
+    user_id      UUID PRIMARY KEY,
+    last_seen_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,34 @@
+ALTER TABLE account_settings ADD COLUMN IF NOT EXISTS allow_messages BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS conversations
+(
+    conversation_id UUID PRIMARY KEY,
+    creator_id      UUID        NOT NULL,
+    is_group        BOOLEAN     NOT NULL DEFAULT FALSE,
+    -- sorted pair of members for direct conversations, so there is only one per pair
+    direct_key      TEXT UNIQUE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_message_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS conversation_members
+(
+    conversation_id      UUID NOT NULL REFERENCES conversations (conversation_id) ON DELETE CASCADE,
+    user_id              UUID NOT NULL,
+    last_read_message_id UUID,
+    last_read_at         TIMESTAMPTZ,
+    PRIMARY KEY (conversation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS conversation_members_user_idx ON conversation_members (user_id);
+
+CREATE TABLE IF NOT EXISTS messages
+(
+    message_id      UUID PRIMARY KEY,
+    conversation_id UUID        NOT NULL REFERENCES conversations (conversation_id) ON DELETE CASCADE,
+    sender_id       UUID        NOT NULL,
+    text            TEXT        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS messages_conversation_idx ON messages (conversation_id, created_at DESC, message_id DESC);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS oidc_states
+(
+    state         VARCHAR(64) PRIMARY KEY,
+    provider      VARCHAR(64) NOT NULL,
+    nonce         VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    link_user_id  UUID,
+    expires_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS oidc_states_expires_idx ON oidc_states (expires_at);
+
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    provider   VARCHAR(64)  NOT NULL,
+    subject    VARCHAR(255) NOT NULL,
+    user_id    UUID         NOT NULL,
+    email      VARCHAR(320) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, subject),
+    UNIQUE (user_id, provider)
+);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS two_factor
+(
+    user_id        UUID PRIMARY KEY,
+    secret         VARCHAR(64) NOT NULL,
+    enabled        BOOLEAN     NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT      NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes
+(
+    user_id   UUID        NOT NULL REFERENCES two_factor (user_id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at   TIMESTAMPTZ,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS two_factor_challenges
+(
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id    UUID        NOT NULL,
+    attempts   INT         NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS two_factor_challenges_expires_idx ON two_factor_challenges (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE polls DROP COLUMN IF EXISTS locked_until;
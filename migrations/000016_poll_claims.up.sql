@@ -0,0 +1 @@
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;